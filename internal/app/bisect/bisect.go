@@ -0,0 +1,131 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package bisect contains the app definition for c4t-bisect.
+package bisect
+
+import (
+	"io"
+	"log"
+
+	"github.com/c4-project/c4t/internal/config"
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/helper/srvrun"
+	"github.com/c4-project/c4t/internal/quantity"
+	cimpl "github.com/c4-project/c4t/internal/serviceimpl/compiler"
+	"github.com/c4-project/c4t/internal/stage/bisector"
+	"github.com/c4-project/c4t/internal/stage/invoker"
+	"github.com/c4-project/c4t/internal/stage/invoker/runner"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/ux"
+	"github.com/c4-project/c4t/internal/ux/singleobs"
+	"github.com/c4-project/c4t/internal/ux/stdflag"
+
+	c "github.com/urfave/cli/v2"
+)
+
+const (
+	// Name is the name of the bisector binary.
+	Name = "c4t-bisect"
+
+	usage = "finds the optimisation passes needed to reproduce a bad compilation"
+
+	readme = `
+   Takes a plan that has been invoked, and the name of a compilation in that
+   plan with a bad run (in the form subject@compiler), and asks the compiler
+   which individual optimisation passes its selected optimisation level
+   enables.  It then delta-debugs over those passes, recompiling and rerunning
+   the subject with the others disabled, and reports the smallest set of
+   enabled passes that still reproduces the bad result.  The output is a plan
+   containing the rerun with that set.
+
+   The compiler must support pass listing (currently, only the gcc style does).
+   The pass list comes from the machine that ran the compilation, connecting
+   over SSH if the plan's machine is remote.
+`
+
+	flagCompilationLong = "compilation"
+	usageCompilation    = "bisect the compilation with this `name` (subject@compiler)"
+
+	// defaultOutDir is the default directory used for the results of the bisector.
+	defaultOutDir = "bisect_results"
+)
+
+// App creates the c4t-bisect app.
+func App(outw, errw io.Writer) *c.App {
+	a := c.App{
+		Name:        Name,
+		Usage:       usage,
+		Description: readme,
+		Flags:       flags(),
+		Action: func(ctx *c.Context) error {
+			return run(ctx, outw, errw)
+		},
+	}
+	return stdflag.SetPlanAppSettings(&a, outw, errw)
+}
+
+func flags() []c.Flag {
+	return []c.Flag{
+		stdflag.VerboseFlag(),
+		stdflag.ConfFileCliFlag(),
+		stdflag.OutDirCliFlag(defaultOutDir),
+		&c.StringFlag{
+			Name:     flagCompilationLong,
+			Usage:    usageCompilation,
+			Required: true,
+		},
+	}
+}
+
+func run(ctx *c.Context, outw, errw io.Writer) error {
+	cfg, err := stdflag.ConfigFromCli(ctx)
+	if err != nil {
+		return err
+	}
+
+	b, err := makeBisector(ctx, cfg, iohelp.EnsureWriter(errw))
+	if err != nil {
+		return err
+	}
+
+	err = ux.RunOnCliPlan(ctx, b, outw)
+	cerr := b.Close()
+	return errhelp.FirstError(err, cerr)
+}
+
+func makeBisector(ctx *c.Context, cfg *config.Config, errw io.Writer) (*bisector.Bisector, error) {
+	l := log.New(errw, "[bisector] ", log.LstdFlags)
+	v := stdflag.Verbose(ctx)
+
+	n, err := compilation.ParseName(ctx.String(flagCompilationLong))
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := invoker.New(stdflag.OutDirFromCli(ctx),
+		&runner.FromPlanFactory{Config: cfg.SSH},
+		invoker.AllowReinvoke(true),
+		invoker.ObserveCopiesWith(singleobs.Copier(l, v)...),
+		invoker.ObserveMachWith(singleobs.MachNode(l, v)...),
+		invoker.OverrideBaseQuantities(cfg.Quantities.Mach),
+		invoker.OverrideQuantitiesFromPlanThen(quantity.MachNodeSet{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return bisector.New(inv,
+		&cimpl.CResolve,
+		&runner.FromPlanServiceFactory{
+			Config: cfg.SSH,
+			Local:  srvrun.NewExecRunner(srvrun.StderrTo(errw)),
+			Stderr: errw,
+		},
+		bisector.ForCompilation(n),
+		bisector.ObserveWith(singleobs.Bisector(l)...),
+	)
+}
@@ -8,8 +8,12 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+
+	"github.com/c4-project/c4t/internal/remote"
 
 	"github.com/c4-project/c4t/internal/config/pretty"
 
@@ -32,6 +36,10 @@ This program provides utilities for manipulating the c4t config file.
 
 With no arguments, it produces an initial config file for the current system and dumps it to stdout.
 
+If -` + FlagRemoteHost + ` is given, it instead dials into that host over SSH and probes it, producing a config file
+containing a machine block for the remote host (including its SSH settings, core count, architecture, and compilers).
+This uses any SSH configuration in the current c4t config file, if one exists.
+
 The following flags print information about the current c4t config:
 
 -` + FlagPrintGlobalPath + `: prints the path that c4t uses by default when looking for a config file.
@@ -47,6 +55,24 @@ You can use this to open the global config in a text editor, or save the config
 	FlagPrintCompilers      = "print-compilers"
 	flagPrintCompilersShort = "c"
 	usagePrintCompilers     = "print information about configured compilers"
+
+	// FlagRemoteHost is the flag used for probing a remote host.
+	FlagRemoteHost      = "remote-host"
+	flagRemoteHostShort = "r"
+	usageRemoteHost     = "probe this remote host over SSH, rather than the current system"
+
+	// FlagRemoteUser is the flag used for setting the user when probing a remote host.
+	FlagRemoteUser      = "remote-user"
+	flagRemoteUserShort = "u"
+	usageRemoteUser     = "user to use when probing a remote host"
+
+	// FlagRemotePort is the flag used for setting the port when probing a remote host.
+	FlagRemotePort  = "remote-port"
+	usageRemotePort = "port to use when probing a remote host (if zero, uses the SSH default)"
+
+	// FlagRemoteCopyDir is the flag used for setting the copy directory of a remote host.
+	FlagRemoteCopyDir  = "remote-copy-dir"
+	usageRemoteCopyDir = "directory on the remote host to which c4t should copy intermediate files"
 )
 
 // App is the entry point for c4t-config.
@@ -75,6 +101,24 @@ func flags() []c.Flag {
 			Aliases: []string{flagPrintCompilersShort},
 			Usage:   usagePrintCompilers,
 		},
+		&c.StringFlag{
+			Name:    FlagRemoteHost,
+			Aliases: []string{flagRemoteHostShort},
+			Usage:   usageRemoteHost,
+		},
+		&c.StringFlag{
+			Name:    FlagRemoteUser,
+			Aliases: []string{flagRemoteUserShort},
+			Usage:   usageRemoteUser,
+		},
+		&c.IntFlag{
+			Name:  FlagRemotePort,
+			Usage: usageRemotePort,
+		},
+		&c.StringFlag{
+			Name:  FlagRemoteCopyDir,
+			Usage: usageRemoteCopyDir,
+		},
 	}
 	flags = append(flags, stdflag.TabulatorCliFlags()...)
 	flags = append(flags, stdflag.ConfFileCliFlag())
@@ -85,6 +129,9 @@ func run(ctx *c.Context, outw io.Writer, errw io.Writer) error {
 	if dc := dumpConfigFromCli(ctx); dc.isDumping() {
 		return dump(ctx, outw, dc)
 	}
+	if mc := remoteConfigFromCli(ctx); mc != nil {
+		return probeRemoteAndDump(ctx, outw, mc)
+	}
 	return probeAndDump(ctx.Context, outw, errw)
 }
 
@@ -151,3 +198,48 @@ func probeAndDump(ctx context.Context, outw io.Writer, errw io.Writer) error {
 	}
 	return cfg.Dump(outw)
 }
+
+func remoteConfigFromCli(ctx *c.Context) *remote.MachineConfig {
+	host := ctx.String(FlagRemoteHost)
+	if host == "" {
+		return nil
+	}
+	return &remote.MachineConfig{
+		Host:    host,
+		User:    ctx.String(FlagRemoteUser),
+		Port:    ctx.Int(FlagRemotePort),
+		DirCopy: ctx.String(FlagRemoteCopyDir),
+	}
+}
+
+func probeRemoteAndDump(ctx *c.Context, outw io.Writer, mc *remote.MachineConfig) error {
+	gc, err := globalSSHFromCli(ctx)
+	if err != nil {
+		return err
+	}
+	mr, err := mc.MachineRunner(gc)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = mr.Close() }()
+
+	sr := mr.ServiceRunner()
+	cfg := config.Config{}
+	if err := config.RemoteProberSet(ctx.Context, sr, mc).Probe(ctx.Context, sr, &cfg); err != nil {
+		return err
+	}
+	return cfg.Dump(outw)
+}
+
+// globalSSHFromCli gets any global SSH configuration from the config file, if one exists.
+func globalSSHFromCli(ctx *c.Context) (*remote.Config, error) {
+	cfg, err := stdflag.ConfigFromCli(ctx)
+	if err != nil {
+		// Not having a config file yet is fine, as we might be probing to make one.
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cfg.SSH, nil
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"runtime/pprof"
 	"strings"
 
@@ -57,7 +58,13 @@ const (
    In dashboard mode (the default), pressing Ctrl-C on the terminal stops the
    tester gracefully.  In no-dashboard mode, the tester will shut down in
    response to interrupt signals, which can usually be sent by pressing Ctrl-C
-   anyway.
+   anyway.  Either way, any cycles in progress are abandoned.
+
+   To stop without abandoning cycles, send the tester SIGTERM (or, on Unix,
+   SIGUSR1): it then stops starting new cycles, and exits once every cycle in
+   progress has finished, analysed, and saved its results.  Sending SIGTERM a
+   second time stops the tester immediately.  The tester also stops in this
+   way when a machine reaches the campaign limits in its configuration.
 
    Most of the director's options can be configured through the main config
    file.  Options specified on the command line, where appropriate, override
@@ -188,22 +195,63 @@ func runWithObs(ctx context.Context, cfg *config.Config, args args, a *c4f.Runne
 	if err != nil {
 		return err
 	}
+	return runDirector(ctx, d, o)
+}
+
+// directRunner is the part of a director that runDirector uses.
+type directRunner interface {
+	Direct(ctx context.Context) error
+	Stop()
+}
 
+// obsRunner is the part of a director observer set that runDirector uses.
+type obsRunner interface {
+	Run(ctx context.Context, cancel context.CancelFunc) error
+}
+
+// runDirector runs d alongside the observer loop of o, stopping d gracefully on any of stopSignals.
+func runDirector(ctx context.Context, d directRunner, o obsRunner) error {
 	// TODO(@MattWindsor91): is this really necessary?
 	cctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	done := make(chan struct{})
+
 	// TODO(@MattWindsor91): is this nesting of errgroups inefficient?
 	eg, ectx := errgroup.WithContext(cctx)
 	eg.Go(func() error {
+		// We don't cancel the observers when the director stops on its own (for instance, after a graceful stop):
+		// they stop once every instance has closed, and cancelling them early would drop the observations of any
+		// cycles that were still draining.
+		defer close(done)
 		return d.Direct(ectx)
 	})
 	eg.Go(func() error {
 		return o.Run(ectx, cancel)
 	})
+	eg.Go(func() error {
+		return stopOnSignal(ectx, done, d)
+	})
 	return eg.Wait()
 }
 
+// stopOnSignal asks d to stop gracefully if the process receives any of stopSignals before ctx or done closes.
+//
+// After the first such signal, we restore the default signal behaviour, so that a second signal stops the process.
+func stopOnSignal(ctx context.Context, done <-chan struct{}, d directRunner) error {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, stopSignals...)
+	defer signal.Stop(ch)
+
+	select {
+	case <-ctx.Done():
+	case <-done:
+	case <-ch:
+		d.Stop()
+	}
+	return nil
+}
+
 func makeDirector(cfg *config.Config, glob id.ID, a *c4f.Runner, obs *directorobs.Obs) (*director.Director, error) {
 	ms, err := cfg.Machines()
 	if err != nil {
@@ -213,6 +261,7 @@ func makeDirector(cfg *config.Config, glob id.ID, a *c4f.Runner, obs *directorob
 		director.ConfigFromGlobal(cfg),
 		director.FilterMachines(glob),
 		director.ObserveWith(obs.Observers()...),
+		director.Yields(obs.Yields()),
 	)
 }
 
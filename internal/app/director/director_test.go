@@ -0,0 +1,81 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/c4-project/c4t/internal/director"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/ux/directorobs"
+	"github.com/stretchr/testify/require"
+)
+
+// drainingDirector is a fake director whose instance finishes its last cycle only after Direct returns.
+type drainingDirector struct {
+	// io is the instance observer to which the instance sends its observations.
+	io director.InstanceObserver
+	// returned is closed when Direct returns.
+	returned chan struct{}
+}
+
+func (d *drainingDirector) Direct(context.Context) error {
+	go func() {
+		<-d.returned
+		// Give anything reacting to Direct returning a chance to run first.
+		time.Sleep(10 * time.Millisecond)
+		d.io.OnAnalysis(analysis.Analysis{})
+		d.io.OnInstance(director.InstanceClosedMessage())
+	}()
+	close(d.returned)
+	return nil
+}
+
+func (d *drainingDirector) Stop() {}
+
+// fwdRunner adapts a forwarding observer to the interface runDirector expects of observer sets.
+type fwdRunner struct {
+	fwd *directorobs.ForwardObserver
+}
+
+func (f fwdRunner) Run(ctx context.Context, _ context.CancelFunc) error {
+	return f.fwd.Run(ctx)
+}
+
+// analysisRecorder is a forward handler that records the analyses it sees.
+type analysisRecorder struct {
+	directorobs.ForwardHandler
+
+	analyses []director.CycleAnalysis
+}
+
+func (a *analysisRecorder) OnCycleAnalysis(c director.CycleAnalysis) {
+	a.analyses = append(a.analyses, c)
+}
+
+// TestRunDirector_drain tests that analyses from cycles that finish after Direct returns still reach the observers.
+func TestRunDirector_drain(t *testing.T) {
+	t.Parallel()
+
+	l, err := directorobs.NewLogger(iohelp.DiscardCloser(), 0)
+	require.NoError(t, err, "logger should construct without errors")
+	rec := analysisRecorder{ForwardHandler: l}
+	fwd, err := directorobs.NewForwardObserver(0, &rec)
+	require.NoError(t, err, "forwarder should construct without errors")
+	io, err := fwd.Instance(id.FromString("localhost"))
+	require.NoError(t, err, "instance should construct without errors")
+
+	d := drainingDirector{io: io, returned: make(chan struct{})}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, runDirector(ctx, &d, fwdRunner{fwd: fwd}), "director should stop without errors")
+	require.Len(t, rec.analyses, 1, "analysis from draining cycle should have been recorded")
+}
@@ -0,0 +1,17 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+//go:build !windows
+// +build !windows
+
+package director
+
+import (
+	"os"
+	"syscall"
+)
+
+// stopSignals contains the signals that ask the director to stop gracefully.
+var stopSignals = []os.Signal{syscall.SIGTERM, syscall.SIGUSR1}
@@ -0,0 +1,15 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"os"
+	"syscall"
+)
+
+// stopSignals contains the signals that ask the director to stop gracefully.
+// Windows has no SIGUSR1.
+var stopSignals = []os.Signal{syscall.SIGTERM}
@@ -75,6 +75,7 @@ func makeFuzzer(ctx *c.Context, cfg *config.Config, drv fuzzer.Driver, l *log.Lo
 		fuzzer.OverrideQuantities(cfg.Quantities.Fuzz),
 		fuzzer.OverrideQuantities(setupQuantityFlags(ctx)),
 		fuzzer.UseConfig(cfg.Fuzz),
+		fuzzer.UseSampleConfig(cfg.Sample),
 	)
 }
 
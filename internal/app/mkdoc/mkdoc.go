@@ -21,8 +21,10 @@ import (
 	"github.com/c4-project/c4t/internal/app/coverage"
 
 	"github.com/c4-project/c4t/internal/app/analyse"
+	"github.com/c4-project/c4t/internal/app/bisect"
 	"github.com/c4-project/c4t/internal/app/invoke"
 	"github.com/c4-project/c4t/internal/app/perturb"
+	"github.com/c4-project/c4t/internal/app/reduce"
 	"github.com/c4-project/c4t/internal/app/setc"
 
 	"github.com/c4-project/c4t/internal/app/fuzz"
@@ -72,6 +74,7 @@ func run(ctx *c.Context, outw io.Writer, errw io.Writer) error {
 var appFuncs = [...]func(io.Writer, io.Writer) *c.App{
 	analyse.App,
 	backend.App,
+	bisect.App,
 	config.App,
 	coverage.App,
 	director.App,
@@ -83,6 +86,7 @@ var appFuncs = [...]func(io.Writer, io.Writer) *c.App{
 	obs.App,
 	perturb.App,
 	plan.App,
+	reduce.App,
 	setc.App,
 	stat.App,
 }
@@ -11,6 +11,7 @@ import (
 	"log"
 	"os"
 
+	mdl "github.com/c4-project/c4t/internal/model/service/compiler"
 	"github.com/c4-project/c4t/internal/quantity"
 
 	"github.com/c4-project/c4t/internal/stage/perturber"
@@ -32,6 +33,8 @@ const (
 	flagFullIDs      = "full-ids"
 	flagFullIDsShort = "I"
 	usageFullIDs     = "map compilers to their 'full' IDs on perturbance"
+	flagStrategy     = "strategy"
+	usageStrategy    = "`strategy` for choosing compiler instances (random, matrix, pairwise, or bandit); overrides the config"
 )
 
 // App creates the c4t-perturb app.
@@ -60,6 +63,7 @@ func flags() []c.Flag {
 			DefaultText: "set seed from time",
 		},
 		&c.BoolFlag{Name: flagFullIDs, Aliases: []string{flagFullIDsShort}, Usage: usageFullIDs},
+		&c.StringFlag{Name: flagStrategy, Usage: usageStrategy},
 		stdflag.CorpusSizeCliFlag(),
 	}
 }
@@ -79,6 +83,10 @@ func makePerturber(ctx *c.Context, errw io.Writer) (*perturber.Perturber, error)
 	}
 
 	qs := quantities(ctx, cfg)
+	pc, err := perturbConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	l := log.New(errw, "[perturb] ", log.LstdFlags)
 
@@ -88,9 +96,25 @@ func makePerturber(ctx *c.Context, errw io.Writer) (*perturber.Perturber, error)
 		perturber.OverrideQuantities(qs),
 		perturber.UseSeed(ctx.Int64(flagSeed)),
 		perturber.UseFullCompilerIDs(ctx.Bool(flagFullIDs)),
+		perturber.UseConfig(pc),
+		perturber.UseSampleConfig(cfg.Sample),
 	)
 }
 
+func perturbConfig(ctx *c.Context, cfg *config.Config) (*mdl.PerturbConfig, error) {
+	s := ctx.String(flagStrategy)
+	if s == "" {
+		return cfg.Perturb, nil
+	}
+	var pc mdl.PerturbConfig
+	if cfg.Perturb != nil {
+		pc = *cfg.Perturb
+	}
+	var err error
+	pc.Strategy, err = mdl.StrategyOfString(s)
+	return &pc, err
+}
+
 func quantities(ctx *c.Context, cfg *config.Config) quantity.PerturbSet {
 	qs := cfg.Quantities.Perturb
 	qs.Override(quantity.PerturbSet{
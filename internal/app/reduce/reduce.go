@@ -0,0 +1,185 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package reduce contains the app definition for c4t-reduce.
+package reduce
+
+import (
+	"io"
+	"log"
+	"path/filepath"
+
+	"github.com/c4-project/c4t/internal/config"
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/serviceimpl/backend"
+	"github.com/c4-project/c4t/internal/stage/invoker"
+	"github.com/c4-project/c4t/internal/stage/invoker/runner"
+	"github.com/c4-project/c4t/internal/stage/lifter"
+	"github.com/c4-project/c4t/internal/stage/oracle"
+	"github.com/c4-project/c4t/internal/stage/reducer"
+	"github.com/c4-project/c4t/internal/ux"
+	"github.com/c4-project/c4t/internal/ux/singleobs"
+	"github.com/c4-project/c4t/internal/ux/stdflag"
+
+	c "github.com/urfave/cli/v2"
+)
+
+const (
+	// Name is the name of the reducer binary.
+	Name = "c4t-reduce"
+
+	usage = "shrinks a bad fuzzed subject by replaying cut-down fuzzer traces"
+
+	readme = `
+   Takes a plan that has been fuzzed and invoked, and a subject in that plan
+   with a bad run (for instance, a flagged observation), and tries to find
+   the smallest subset of the subject's fuzzer trace that still produces the
+   same bad result on the same compiler.  Each candidate is
+   replayed through c4f, lifted, and invoked; if an oracle is configured, it
+   is also checked against the reference model.  The output is a plan
+   containing only the smallest reproducing candidate.
+`
+
+	flagSubjectLong  = "subject"
+	flagSubjectShort = "s"
+	usageSubject     = "reduce the subject with this `name`"
+
+	flagCompilerLong = "compiler"
+	usageCompiler    = "reproduce the bad run on the compiler with this `ID` (default: first bad compiler)"
+
+	// defaultOutDir is the default directory used for the results of the reducer.
+	defaultOutDir = "reduce_results"
+
+	segCandidates = "candidates"
+	segLift       = "lift"
+	segInvoke     = "invoke"
+	segOracle     = "oracle"
+)
+
+// App creates the c4t-reduce app.
+func App(outw, errw io.Writer) *c.App {
+	a := c.App{
+		Name:        Name,
+		Usage:       usage,
+		Description: readme,
+		Flags:       flags(),
+		Action: func(ctx *c.Context) error {
+			return run(ctx, outw, errw)
+		},
+	}
+	return stdflag.SetPlanAppSettings(&a, outw, errw)
+}
+
+func flags() []c.Flag {
+	fs := []c.Flag{
+		stdflag.VerboseFlag(),
+		stdflag.ConfFileCliFlag(),
+		stdflag.OutDirCliFlag(defaultOutDir),
+		&c.StringFlag{
+			Name:     flagSubjectLong,
+			Aliases:  []string{flagSubjectShort},
+			Usage:    usageSubject,
+			Required: true,
+		},
+		&c.StringFlag{
+			Name:    flagCompilerLong,
+			Aliases: []string{stdflag.FlagCompiler},
+			Usage:   usageCompiler,
+		},
+	}
+	return append(fs, stdflag.C4fRunnerCliFlags()...)
+}
+
+func run(ctx *c.Context, outw, errw io.Writer) error {
+	cfg, err := stdflag.ConfigFromCli(ctx)
+	if err != nil {
+		return err
+	}
+
+	errw = iohelp.EnsureWriter(errw)
+	r, err := makeReducer(ctx, cfg, errw)
+	if err != nil {
+		return err
+	}
+
+	err = ux.RunOnCliPlan(ctx, r, outw)
+	cerr := r.Close()
+	return errhelp.FirstError(err, cerr)
+}
+
+func makeReducer(ctx *c.Context, cfg *config.Config, errw io.Writer) (*reducer.Reducer, error) {
+	l := log.New(errw, "[reducer] ", log.LstdFlags)
+	outdir := stdflag.OutDirFromCli(ctx)
+
+	cs, err := makeCheckers(ctx, cfg, l, errw, outdir)
+	if err != nil {
+		return nil, err
+	}
+
+	os := []reducer.Option{
+		reducer.ForSubject(ctx.String(flagSubjectLong)),
+		reducer.CheckWith(cs...),
+		reducer.ObserveWith(singleobs.Reducer(l)...),
+	}
+	if cstr := ctx.String(flagCompilerLong); cstr != "" {
+		cid, err := id.TryFromString(cstr)
+		if err != nil {
+			return nil, err
+		}
+		os = append(os, reducer.ForCompiler(cid))
+	}
+	return reducer.New(
+		stdflag.C4fRunnerFromCli(ctx, errw),
+		reducer.NewPathset(filepath.Join(outdir, segCandidates)),
+		os...,
+	)
+}
+
+// makeCheckers makes the chain of stages used to check each candidate: a lifter, an invoker, and an oracle if
+// the configuration enables one.
+func makeCheckers(ctx *c.Context, cfg *config.Config, l *log.Logger, errw io.Writer, outdir string) ([]plan.Runner, error) {
+	v := stdflag.Verbose(ctx)
+
+	lft, err := lifter.New(
+		&backend.Resolve,
+		lifter.NewPathset(filepath.Join(outdir, segLift)),
+		lifter.ObserveWith(singleobs.Builder(l, v)...),
+		lifter.SendStderrTo(errw),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := invoker.New(filepath.Join(outdir, segInvoke),
+		&runner.FromPlanFactory{Config: cfg.SSH},
+		invoker.AllowReinvoke(true),
+		invoker.ObserveCopiesWith(singleobs.Copier(l, v)...),
+		invoker.ObserveMachWith(singleobs.MachNode(l, v)...),
+		invoker.OverrideBaseQuantities(cfg.Quantities.Mach),
+		invoker.OverrideQuantitiesFromPlanThen(quantity.MachNodeSet{}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	cs := []plan.Runner{lft, inv}
+
+	if cfg.Oracle == nil || cfg.Oracle.Disabled {
+		return cs, nil
+	}
+	orc, err := oracle.New(
+		&backend.Resolve,
+		filepath.Join(outdir, segOracle),
+		oracle.ObserveWith(singleobs.Builder(l, v)...),
+		oracle.UseConfig(cfg.Oracle),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return append(cs, orc), nil
+}
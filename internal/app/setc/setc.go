@@ -51,6 +51,10 @@ const (
 	flagMoptLong  = "machine-opt"
 	flagMoptShort = "m"
 	usageMopt     = "set the compiler's machine optimising profile `name`"
+
+	flagFlagLong  = "flag"
+	flagFlagShort = "f"
+	usageFlag     = "pass the compiler this extra `flag` (can be repeated)"
 )
 
 // App creates the c4t-plan app.
@@ -85,6 +89,11 @@ func flags() []c.Flag {
 			Aliases: []string{flagMoptShort},
 			Usage:   usageMopt,
 		},
+		&c.StringSliceFlag{
+			Name:    flagFlagLong,
+			Aliases: []string{flagFlagShort},
+			Usage:   usageFlag,
+		},
 	}
 }
 
@@ -99,6 +108,7 @@ func run(ctx *c.Context, outw io.Writer) error {
 		cid:       cid,
 		opt:       ctx.String(flagOptLong),
 		mopt:      ctx.String(flagMoptLong),
+		flags:     ctx.StringSlice(flagFlagLong),
 	}
 	return ux.RunOnCliPlan(ctx, &cs, outw)
 }
@@ -117,6 +127,7 @@ type CompilerSetter struct {
 	cid       id.ID
 	opt       string
 	mopt      string
+	flags     []string
 }
 
 // Stage gets the stage record for a compiler setter.
@@ -144,7 +155,12 @@ func (c *CompilerSetter) set(cnf *compiler.Instance) error {
 	if err := c.setOpt(cnf); err != nil {
 		return err
 	}
-	return c.setMOpt(cnf)
+	if err := c.setMOpt(cnf); err != nil {
+		return err
+	}
+	// We don't check the flags against the compiler's flag pool, so that we can try out flags outside the pool.
+	cnf.SelectedFlags = c.flags
+	return nil
 }
 
 // TODO(@MattWindsor91): move some of this to optlevel?
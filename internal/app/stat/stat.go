@@ -32,6 +32,8 @@ const (
 	usageCsvMutations  = "dump CSV of mutation testing results"
 	flagShowMutations  = "mutations"
 	usageShowMutations = "show mutations matching `filter` ('all', 'hit', 'killed', 'escaped')"
+	flagShowSources    = "sources"
+	usageShowSources   = "show the `N` most and least productive source subjects on each machine"
 	flagUseTotals      = "use-totals"
 	flagUseTotalsShort = "t"
 	usageUseTotals     = "use multi-session totals rather than per-session totals"
@@ -59,6 +61,7 @@ func flags() []c.Flag {
 		stdflag.ConfFileCliFlag(),
 		&c.BoolFlag{Name: flagCsvMutations, Usage: usageCsvMutations},
 		&c.StringFlag{Name: flagShowMutations, Usage: usageShowMutations, DefaultText: "do not show"},
+		&c.IntFlag{Name: flagShowSources, Usage: usageShowSources, DefaultText: "do not show"},
 		&c.BoolFlag{Name: flagUseTotals, Aliases: []string{flagUseTotalsShort}, Usage: usageUseTotals},
 		&c.PathFlag{
 			Name:        flagStatFile,
@@ -101,20 +104,27 @@ func prettyPrint(ctx *c.Context, set *stat.Set, w io.Writer, totals bool) error
 
 func makePretty(ctx *c.Context, w io.Writer, totals bool) (*pretty.Printer, error) {
 	flt, err := makeMutationFilter(ctx)
-	if flt == nil || err != nil {
+	if err != nil {
 		return nil, err
 	}
+	nsrcs := ctx.Int(flagShowSources)
+	if flt == nil && nsrcs <= 0 {
+		return nil, nil
+	}
 	// TODO(@MattWindsor91): add other pretty-printing reports if needs be
 	return pretty.NewPrinter(
 		pretty.UseTotals(totals),
 		pretty.WriteTo(w),
 		pretty.ShowMutants(flt),
+		pretty.ShowSources(nsrcs),
 	)
 }
 
 func makeMutationFilter(ctx *c.Context) (stat.MutantFilter, error) {
 	s := ctx.String(flagShowMutations)
 	switch s {
+	case "":
+		return nil, nil
 	case "all":
 		return stat.FilterAllMutants, nil
 	case "hit":
@@ -24,7 +24,7 @@ const BinC4fC = "c4f-c"
 func (a *Runner) ProbeSubject(ctx context.Context, path string) (*subject.Named, error) {
 	// TODO(@MattWindsor91): stat dumping and subject probing should likely be two separate things.
 	// Perform arch check first.
-	l, err := litmus.New(path, litmus.ReadArchFromFile(), litmus.PopulateStatsFrom(ctx, a))
+	l, err := litmus.New(path, litmus.ReadArchFromFile(), litmus.ReadHashFromFile(), litmus.PopulateStatsFrom(ctx, a))
 	if err != nil {
 		return nil, fmt.Errorf("stats read on %s failed: %w", path, err)
 	}
@@ -0,0 +1,22 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package c4f
+
+import (
+	"context"
+
+	"github.com/c4-project/c4t/internal/model/service/fuzzer"
+)
+
+// Replay wraps the c4f trace replayer.
+func (a *Runner) Replay(ctx context.Context, j fuzzer.ReplayJob) error {
+	cs := CmdSpec{
+		Cmd:    BinC4fFuzz,
+		Subcmd: "replay",
+		Args:   []string{"-trace", j.Trace, "-o", j.OutLitmus, j.In},
+	}
+	return a.Run(ctx, cs)
+}
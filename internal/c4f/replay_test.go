@@ -0,0 +1,42 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package c4f_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/c4f"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/model/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunner_Replay tests the happy path of Runner.Replay using a mock command runner.
+func TestRunner_Replay(t *testing.T) {
+	t.Parallel()
+
+	j := fuzzer.ReplayJob{
+		In:        "foo.litmus",
+		Trace:     "foo.trace",
+		OutLitmus: "foo.replay.litmus",
+	}
+
+	cr := new(mocks.Runner)
+	cr.Test(t)
+	cr.On("Run", mock.Anything, mock.Anything).Return(nil).Once()
+
+	a := c4f.Runner{Base: cr}
+	require.NoError(t, a.Replay(context.Background(), j), "mocked replay should succeed")
+
+	cr.AssertExpectations(t)
+	ri := cr.Calls[0].Arguments.Get(1).(service.RunInfo)
+	assert.Equal(t, c4f.BinC4fFuzz, ri.Cmd, "command")
+	assert.Equal(t, []string{"replay", "-trace", j.Trace, "-o", j.OutLitmus, j.In}, ri.Args, "arguments")
+}
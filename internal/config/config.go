@@ -12,15 +12,19 @@ import (
 	"github.com/c4-project/c4t/internal/id"
 
 	"github.com/c4-project/c4t/internal/model/service/backend"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
 	"github.com/pelletier/go-toml"
 
 	"github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/model/service/generator"
+	"github.com/c4-project/c4t/internal/model/service/transformer"
 
 	"github.com/c4-project/c4t/internal/quantity"
 
 	"github.com/c4-project/c4t/internal/machine"
 
 	"github.com/c4-project/c4t/internal/remote"
+	"github.com/c4-project/c4t/internal/subject/corpus"
 )
 
 // Config is a top-level tester config struct.
@@ -40,6 +44,21 @@ type Config struct {
 	// Fuzz contains fuzzer config overrides.
 	Fuzz *fuzzer.Config `toml:"fuzz,omitempty"`
 
+	// Perturb contains perturber config overrides.
+	Perturb *compiler.PerturbConfig `toml:"perturb,omitempty"`
+
+	// Sample contains corpus sampling config, used when the perturber or fuzzer cut the corpus down to size.
+	Sample *corpus.SampleConfig `toml:"sample,omitempty"`
+
+	// Transform, if present, configures an external transformer to run over each cycle's corpus after fuzzing.
+	Transform *transformer.Config `toml:"transform,omitempty"`
+
+	// Oracle, if present, enables checking of run observations against a reference model.
+	Oracle *backend.OracleConfig `toml:"oracle,omitempty"`
+
+	// Generators contains corpus generators, whose output the director adds to its inputs when planning and rescanning.
+	Generators []generator.Config `toml:"generators,omitempty"`
+
 	// SSH contains top-level SSH configuration.
 	SSH *remote.Config `toml:"ssh,omitempty"`
 
@@ -19,6 +19,7 @@ import (
 	"github.com/c4-project/c4t/internal/model/service"
 
 	"github.com/c4-project/c4t/internal/machine"
+	"github.com/c4-project/c4t/internal/remote"
 )
 
 // ProberSet contains the various probers used by configuration probing.
@@ -29,6 +30,8 @@ type ProberSet struct {
 	Backend backend.Prober
 	// Compiler is a compiler prober used for probing.
 	Compiler compiler.Prober
+	// SSH, if present, is the SSH configuration attached to the probed machine.
+	SSH *remote.MachineConfig
 }
 
 // LocalProberSet provides a prober set suitable for most local probing.
@@ -40,6 +43,15 @@ func LocalProberSet() ProberSet {
 	}
 }
 
+// RemoteProberSet provides a prober set for probing the remote machine configured in ssh.
+// The machine prober runs its commands through sr, which should be the same remote runner later passed to Probe.
+func RemoteProberSet(ctx context.Context, sr service.Runner, ssh *remote.MachineConfig) ProberSet {
+	ps := LocalProberSet()
+	ps.Machine = machine.CommandProber(ctx, sr)
+	ps.SSH = ssh
+	return ps
+}
+
 // Probe populates c with information found by scrutinising the current machine.
 func (p ProberSet) Probe(ctx context.Context, sr service.Runner, c *Config) error {
 	if err := p.probeMachines(ctx, sr, c); err != nil {
@@ -73,6 +85,7 @@ func (p ProberSet) probeMachine(ctx context.Context, sr service.Runner) (machine
 		c   machine.Config
 		err error
 	)
+	c.SSH = p.SSH
 	if err = c.Probe(p.Machine); err != nil {
 		return c, err
 	}
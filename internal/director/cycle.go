@@ -18,17 +18,34 @@ import (
 // cycleInstance contains state for one cycle of one instance of the director.
 type cycleInstance struct {
 	// cycle contains information about which cycle this is.
+	// This is only filled in once the cycle becomes the instance's current cycle.
 	cycle Cycle
 
 	// p points to the plan being built in this cycle.
 	p *plan.Plan
 
-	// stages contains the stages to run in this cycle.
-	stages []plan.Runner
+	// buf is the buffer in which this cycle is prepared and run.
+	buf *buffer
+
+	// start is the time at which this cycle began preparing.
+	start time.Time
+
+	// started is true once this cycle has been announced as the current cycle.
+	started bool
+
+	// prepared is true once this cycle has finished preparing, successfully or otherwise.
+	prepared bool
+
+	// dropped is true if the instance dropped this cycle from its pipeline, while draining, before it started.
+	dropped bool
+
+	// err contains any error that occurred while preparing this cycle.
+	err error
 }
 
-func (c *cycleInstance) run(ctx context.Context) error {
-	for _, s := range c.stages {
+// run runs stages, in order, on this cycle's plan.
+func (c *cycleInstance) run(ctx context.Context, stages []plan.Runner) error {
+	for _, s := range stages {
 		if err := c.runStage(ctx, s); err != nil {
 			return err
 		}
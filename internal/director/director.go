@@ -9,9 +9,19 @@ package director
 import (
 	"context"
 	"fmt"
+	"path"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/srvrun"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+
+	"github.com/c4-project/c4t/internal/model/service/backend"
 	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/model/service/transformer"
 
 	"github.com/c4-project/c4t/internal/plan/analysis"
 
@@ -51,29 +61,53 @@ type Director struct {
 	ssh *remote.Config
 	// fcfg, if present, provides fuzzer configuration.
 	fcfg *fuzzer2.Config
+	// pcfg, if present, provides perturber configuration.
+	pcfg *compiler.PerturbConfig
+	// scfg, if present, provides corpus sampling configuration.
+	scfg *corpus.SampleConfig
+	// ocfg, if present, provides oracle configuration.
+	ocfg *backend.OracleConfig
+	// tcfg, if present, provides external transformer configuration.
+	tcfg *transformer.Config
+	// yields, if present, provides historical compiler yields to each instance's perturber.
+	yields perturber.YieldSource
 	// quantities contains various tunable quantities for the director's stages.
 	quantities quantity.RootSet
 	// files is the input file set.
 	files []string
 	// filters is the set of compiled filter sets to use in analysis.
 	filters analysis.FilterSet
+	// gens contains the corpus generators whose output the director adds to the input file set.
+	gens generators
+	// stop closes when someone asks the director to stop gracefully.
+	stop chan struct{}
+	// stopOnce makes sure that we only close stop once.
+	stopOnce sync.Once
 }
 
 // New creates a new Director with driver set e, input paths files, machines ms, and options opt.
+//
+// The input paths may be empty only if opt configures at least one corpus generator.
 func New(e Env, ms machine.ConfigMap, files []string, opt ...Option) (*Director, error) {
-	if len(files) == 0 {
-		return nil, liftInitError(corpus.ErrNone)
-	}
 	if len(ms) == 0 {
 		return nil, liftInitError(ErrNoMachines)
 	}
 	if err := e.Check(); err != nil {
 		return nil, liftInitError(err)
 	}
-	d := Director{files: files, env: e, machines: ms}
+	d := Director{
+		files:    files,
+		env:      e,
+		machines: ms,
+		gens:     generators{runner: srvrun.NewExecRunner()},
+		stop:     make(chan struct{}),
+	}
 	if err := Options(opt...)(&d); err != nil {
 		return nil, liftInitError(err)
 	}
+	if len(d.files) == 0 && len(d.gens.configs) == 0 {
+		return nil, liftInitError(corpus.ErrNone)
+	}
 	return &d, d.initAfterOptions()
 }
 
@@ -88,44 +122,88 @@ func (d *Director) initAfterOptions() error {
 }
 
 // initInstances performs the initial set-up of instances (before allocation of plan resources to them).
+//
+// Each machine gets as many instances as its quantity set asks for, and the instances for each machine are adjacent.
 func (d *Director) initInstances() error {
-	// TODO(@MattWindsor91): eventually decouple machines from instances.
-
-	d.instances = make([]Instance, len(d.machines))
-	i := 0
+	mids, err := d.machines.IDs()
+	if err != nil {
+		return err
+	}
+	qss := make([]quantity.MachineSet, len(mids))
+	ninst := 0
+	for j, mid := range mids {
+		mc := d.machines[mid]
+		qss[j] = d.machineQuantities(&mc)
+		ninst += instanceCount(qss[j])
+	}
+	d.instances = make([]Instance, 0, ninst)
 
 	// This is a bit weird, but necessary at the moment to solve a race involving instance observers.
-	OnPrepare(PrepareInstancesMessage(len(d.instances)), LowerToPrepare(d.observers)...)
-
-	for mid, mc := range d.machines {
-		if err := d.initInstance(i, mid, mc); err != nil {
-			return err
+	OnPrepare(PrepareInstancesMessage(ninst), LowerToPrepare(d.observers)...)
+
+	for j, mid := range mids {
+		n := instanceCount(qss[j])
+		// Each machine's instances share the machine's campaign limits, health, and coverage.
+		lim := newLimiter(qss[j].Limits)
+		st := newMachineState(qss[j])
+		for k, ps := range d.paths.Instances(mid, n) {
+			m := Machine{
+				ID:         mid,
+				Config:     instanceConfig(d.machines[mid], k, n),
+				Pathset:    ps,
+				Quantities: qss[j],
+				shared:     st,
+			}
+			if err := d.initInstance(&m, lim); err != nil {
+				return err
+			}
 		}
-		i++
 	}
 	return nil
 }
 
-func (d *Director) initInstance(i int, mid id.ID, mc machine.Config) error {
-	obs, err := d.instanceObservers(mid)
+// instanceCount gets the number of instances that should run on a machine with quantity set qs.
+func instanceCount(qs quantity.MachineSet) int {
+	if qs.Instances < 1 {
+		return 1
+	}
+	return qs.Instances
+}
+
+// instanceConfig gets the machine config for the k-th of n instances on a machine with config mc.
+//
+// If there is more than one instance, and the machine is remote, each instance copies its files into its own
+// subdirectory of the machine's copy directory.
+func instanceConfig(mc machine.Config, k, n int) machine.Config {
+	if n <= 1 || mc.SSH == nil {
+		return mc
+	}
+	ssh := *mc.SSH
+	ssh.DirCopy = path.Join(ssh.DirCopy, strconv.Itoa(k))
+	mc.SSH = &ssh
+	return mc
+}
+
+func (d *Director) initInstance(m *Machine, lim *limiter) error {
+	obs, err := d.instanceObservers(m.ID)
 	if err != nil {
 		return err
 	}
-	m := Machine{
-		ID:         mid,
-		Config:     mc,
-		Pathset:    d.paths.Instance(mid),
-		Quantities: d.machineQuantities(&mc),
-	}
-	d.instances[i] = Instance{
-		Index:        i,
-		SSHConfig:    d.ssh,
-		Env:          d.env,
-		Observers:    obs,
-		Machine:      &m,
-		Filters:      d.filters,
-		FuzzerConfig: d.fcfg,
-	}
+	d.instances = append(d.instances, Instance{
+		Index:           len(d.instances),
+		SSHConfig:       d.ssh,
+		Env:             d.env,
+		Observers:       obs,
+		Machine:         m,
+		Filters:         d.filters,
+		FuzzerConfig:    d.fcfg,
+		PerturbConfig:   d.pcfg,
+		SampleConfig:    d.scfg,
+		OracleConfig:    d.ocfg,
+		TransformConfig: d.tcfg,
+		Yields:          d.yields,
+		limits:          lim,
+	})
 	return nil
 }
 
@@ -155,36 +233,91 @@ func liftInitError(err error) error {
 }
 
 // Direct runs the director d.
+//
+// The director runs until ctx closes, or until every instance has stopped (either by reaching its campaign limits or
+// through Stop); in the latter case, Direct returns nil.
 func (d *Director) Direct(ctx context.Context) error {
 	if err := d.prepare(ctx); err != nil {
 		return err
 	}
 
-	pn, err := d.plan(ctx)
+	gdirs, err := d.gens.generate(ctx, d.paths.DirGenerated)
 	if err != nil {
 		return err
 	}
 
-	return d.runLoops(ctx, pn)
+	// We expand the input paths here, rather than in the planner, so that any rescanner knows which files it has seen.
+	files, err := planner.ExpandLitmusInputs(append(gdirs, d.files...))
+	if err != nil {
+		return err
+	}
+
+	pn, err := d.plan(ctx, files)
+	if err != nil {
+		return err
+	}
+	if err := d.pruneGenerated(gdirs, pn); err != nil {
+		return err
+	}
+
+	return d.runLoops(ctx, pn, d.makeRescanner(files, pn))
 }
 
-func (d *Director) plan(ctx context.Context) (plan.Map, error) {
+func (d *Director) plan(ctx context.Context, files []string) (plan.Map, error) {
 	p, err := d.makePlanner()
 	if err != nil {
 		return nil, fmt.Errorf("when making planner: %w", err)
 	}
-	return p.Plan(ctx, d.machines, d.files...)
+	return p.Plan(ctx, d.machines, files...)
+}
+
+// pruneGenerated removes those generator directories in gdirs from which nothing made it into any machine's plan in pn.
+func (d *Director) pruneGenerated(gdirs []string, pn plan.Map) error {
+	cs := make([]corpus.Corpus, 0, len(pn))
+	for _, p := range pn {
+		cs = append(cs, p.Corpus)
+	}
+	_, err := pruneGenerated(gdirs, cs...)
+	return err
+}
+
+// makeRescanner makes a rescanner that knows about the input files files and the corpus planned in pn.
+// If rescanning is disabled, this returns nil.
+func (d *Director) makeRescanner(files []string, pn plan.Map) *rescanner {
+	if !d.quantities.Plan.RescanInterval.IsActive() {
+		return nil
+	}
+	// Every machine's plan starts with the same corpus.
+	var c corpus.Corpus
+	for _, p := range pn {
+		c = p.Corpus
+		break
+	}
+	rs := newRescanner(d.files, files, c, d.prober(), time.Duration(d.quantities.Plan.RescanInterval))
+	rs.gens, rs.genRoot = &d.gens, d.paths.DirGenerated
+	return rs
 }
 
 func (d *Director) makePlanner() (*planner.Planner, error) {
+	src := d.env.Planner
+	src.SProbe = d.prober()
 	return planner.New(
-		d.env.Planner,
+		src,
 		planner.ObserveWith(LowerToPlanner(d.observers)...),
 		planner.OverrideQuantities(d.quantities.Plan),
 	)
 }
 
-func (d *Director) runLoops(ctx context.Context, plans plan.Map) error {
+// prober gets the subject prober that the director's planner and rescanner use.
+func (d *Director) prober() planner.SubjectProber {
+	if d.env.Planner.SProbe == nil {
+		// Let the planner complain about this.
+		return nil
+	}
+	return generatedProber{SubjectProber: d.env.Planner.SProbe, root: d.paths.DirGenerated}
+}
+
+func (d *Director) runLoops(ctx context.Context, plans plan.Map, rs *rescanner) error {
 	// Doing this here so that the perceived experiment length is always at or slightly above the timeout interval.
 	start := time.Now()
 	cctx, cancel := d.quantities.GlobalTimeout.OnContext(ctx)
@@ -196,19 +329,56 @@ func (d *Director) runLoops(ctx context.Context, plans plan.Map) error {
 	for _, m := range d.instances {
 		m := m
 		m.Machine.InitialPlan = plans[m.Machine.ID]
+		if rs != nil {
+			m.rescanCh, m.rescanDone = rs.sink()
+		}
+		m.stopCh = d.stop
+		if b := m.Machine.Quantities.Limits.Budget; b.IsActive() {
+			m.deadlineCh = time.After(time.Until(start.Add(time.Duration(b))))
+		}
 		eg.Go(func() error { return m.Run(ectx) })
 	}
-	return eg.Wait()
+	if rs == nil {
+		return eg.Wait()
+	}
+
+	// The rescanner would otherwise run forever, so we stop it once every instance has closed.
+	rctx, rcancel := context.WithCancel(ectx)
+	var reg errgroup.Group
+	reg.Go(func() error { return rs.run(rctx) })
+	err := eg.Wait()
+	rcancel()
+	return errhelp.FirstError(err, reg.Wait())
+}
+
+// Stop asks the director to stop gracefully.
+//
+// Each instance stops starting new cycles, and closes once any cycle it has already started has finished (including
+// its analysis and saving).  Once every instance has closed, Direct returns.  Stop may be called more than once, and
+// from any goroutine.
+func (d *Director) Stop() {
+	d.stopOnce.Do(func() { close(d.stop) })
 }
 
 func (d *Director) announceTimes(ctx context.Context, start time.Time) {
 	obs := LowerToPrepare(d.observers)
 	OnPrepare(PrepareStartMessage(start), obs...)
-	if dl, ok := ctx.Deadline(); ok {
+	if dl, ok := d.deadline(ctx, start); ok {
 		OnPrepare(PrepareTimeoutMessage(dl), obs...)
 	}
 }
 
+// deadline gets the earlier of ctx's deadline and the end of the default time budget from start, if either exists.
+func (d *Director) deadline(ctx context.Context, start time.Time) (time.Time, bool) {
+	dl, ok := ctx.Deadline()
+	if b := d.quantities.Limits.Budget; b.IsActive() {
+		if bdl := start.Add(time.Duration(b)); !ok || bdl.Before(dl) {
+			return bdl, true
+		}
+	}
+	return dl, ok
+}
+
 func (d *Director) prepare(ctx context.Context) error {
 	obs := LowerToPrepare(d.observers)
 
@@ -0,0 +1,128 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/generator"
+	"github.com/c4-project/c4t/internal/stage/planner"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+)
+
+// generators runs the director's corpus generators.
+type generators struct {
+	// configs contains the configuration of each enabled generator.
+	configs []generator.Config
+	// runner runs the generator commands.
+	runner service.Runner
+}
+
+// generate runs each generator into a fresh directory under root, returning the directories.
+// It stops at the first generator that fails.
+func (g *generators) generate(ctx context.Context, root string) ([]string, error) {
+	dirs := make([]string, 0, len(g.configs))
+	for _, c := range g.configs {
+		dir, err := g.generateOne(ctx, c, root)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// generateOne runs the generator configured by c into a fresh directory under root.
+// If the generator itself fails, generateOne still returns the directory, so that the caller can report it.
+//
+// We never reuse a directory, as subjects planned from earlier runs may still be in use; pruneGenerated removes
+// directories once we know that they aren't.
+func (g *generators) generateOne(ctx context.Context, c generator.Config, root string) (string, error) {
+	dir, err := os.MkdirTemp(root, c.Name+"_")
+	if err != nil {
+		return "", fmt.Errorf("making directory for generator %s: %w", c.Name, err)
+	}
+	return dir, c.Generate(ctx, g.runner, dir)
+}
+
+// generatedProber is a subject prober that renames subjects generated into directories under root.
+//
+// Generators tend to reuse the same test names on each run, and we can't have two subjects with the same name, so we
+// prefix the name of each generated subject with the name of its (unique) directory.  The prober names subjects from
+// their litmus headers, not their files, so we can only do this after probing.
+type generatedProber struct {
+	planner.SubjectProber
+
+	// root is the directory under which generators make their output directories.
+	root string
+}
+
+// ProbeSubject probes the litmus test at f, renaming the resulting subject if f is in a generator directory.
+func (g generatedProber) ProbeSubject(ctx context.Context, f string) (*subject.Named, error) {
+	s, err := g.SubjectProber.ProbeSubject(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	if dir, ok := generatedDir(g.root, f); ok {
+		s.Name = dir + "_" + s.Name
+	}
+	return s, nil
+}
+
+// generatedDir gets the name of the generator directory under root containing file path f, if there is one.
+func generatedDir(root, f string) (string, bool) {
+	if root == "" || !inDir(root, f) {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, f)
+	if err != nil {
+		return "", false
+	}
+	parts := strings.SplitN(filepath.ToSlash(rel), "/", 2)
+	return parts[0], len(parts) == 2
+}
+
+// pruneGenerated removes each generator output directory in dirs that contains the source of no subject in any of
+// the corpora cs, returning the directories it removed.
+//
+// As nothing removes subjects from a corpus once added, no cycle can ever use the tests in such a directory.
+func pruneGenerated(dirs []string, cs ...corpus.Corpus) ([]string, error) {
+	var removed []string
+	for _, d := range dirs {
+		if usesDir(d, cs...) {
+			continue
+		}
+		if err := os.RemoveAll(d); err != nil {
+			return removed, fmt.Errorf("removing unused generator directory %s: %w", d, err)
+		}
+		removed = append(removed, d)
+	}
+	return removed, nil
+}
+
+// usesDir gets whether any of cs contains a subject whose source is in directory d.
+func usesDir(d string, cs ...corpus.Corpus) bool {
+	for _, c := range cs {
+		for _, s := range c {
+			if inDir(d, s.Source.Filepath()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inDir gets whether file path f is inside directory d.
+func inDir(d, f string) bool {
+	rel, err := filepath.Rel(d, f)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
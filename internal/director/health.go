@@ -0,0 +1,274 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/invoker/runner"
+)
+
+// defaultBackoff contains the backoff quantities that apply where the configuration doesn't override them.
+var defaultBackoff = quantity.BackoffSet{
+	Initial:       quantity.Timeout(5 * time.Second),
+	Max:           quantity.Timeout(5 * time.Minute),
+	Jitter:        0.1,
+	ProbeInterval: quantity.Timeout(1 * time.Minute),
+}
+
+// breaker tracks the consecutive errored cycles on a machine, and decides when to back off and when to park.
+type breaker struct {
+	// qs contains the backoff quantities.
+	qs quantity.BackoffSet
+	// rng jitters backoff delays.
+	rng *rand.Rand
+	// nerrs counts the consecutive errored cycles.
+	nerrs int
+}
+
+// newBreaker makes a breaker that overrides the default backoff quantities with qs.
+func newBreaker(qs quantity.BackoffSet) breaker {
+	b := breaker{qs: defaultBackoff, rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	b.qs.Override(qs)
+	return b
+}
+
+// fail records an errored cycle, returning whether the breaker has tripped.
+func (b *breaker) fail() bool {
+	b.nerrs++
+	return 0 < b.qs.BreakAfter && b.qs.BreakAfter <= b.nerrs
+}
+
+// reset records a successful cycle or health probe.
+func (b *breaker) reset() {
+	b.nerrs = 0
+}
+
+// delay gets the delay to wait before the next cycle.
+func (b *breaker) delay() time.Duration {
+	return b.qs.Delay(b.nerrs, b.rng)
+}
+
+// health tracks the health of a machine across all of the instances running on it.
+//
+// Once the machine's breaker trips, the machine is parked: none of its instances start cycles until one of them
+// probes the machine successfully.
+type health struct {
+	// mu guards the fields below.
+	mu sync.Mutex
+	// breaker counts the machine's consecutive errored cycles.
+	breaker breaker
+	// resumed is non-nil if the machine is parked, and closes when it resumes.
+	resumed chan struct{}
+	// probing is true while an instance is probing the machine.
+	probing bool
+	// lastProbe is the time at which the last probe finished.
+	lastProbe time.Time
+	// gen counts the times the machine has resumed.
+	// Run stages made before the machine last resumed may hold dead connections, so instances remake them first.
+	gen uint64
+}
+
+// newHealth makes a health tracker for a machine with backoff quantities qs.
+func newHealth(qs quantity.BackoffSet) *health {
+	return &health{breaker: newBreaker(qs)}
+}
+
+// probeInterval gets the interval between health probes.
+func (h *health) probeInterval() time.Duration {
+	// The quantities never change, so we needn't lock.
+	return time.Duration(h.breaker.qs.ProbeInterval)
+}
+
+// failure is the outcome of recording an errored cycle.
+type failure struct {
+	// nerrs is the number of consecutive errored cycles on the machine.
+	nerrs int
+	// delay is how long to wait before the next cycle, if the machine isn't parked.
+	delay time.Duration
+	// tripped is true if this error parked the machine.
+	tripped bool
+	// resumed is non-nil if the machine is parked (by this error or an earlier one), and closes when it resumes.
+	resumed <-chan struct{}
+}
+
+// fail records an errored cycle.
+func (h *health) fail() failure {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f := failure{tripped: h.breaker.fail(), nerrs: h.breaker.nerrs}
+	switch {
+	case h.resumed != nil:
+		f.tripped = false
+	case f.tripped:
+		h.resumed = make(chan struct{})
+	default:
+		f.delay = h.breaker.delay()
+	}
+	if h.resumed != nil {
+		f.resumed = h.resumed
+	}
+	return f
+}
+
+// succeed records a successful cycle.
+// This doesn't resume a parked machine; only a passing probe does that.
+func (h *health) succeed() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.breaker.reset()
+}
+
+// parked gets a channel that closes when the machine resumes, if it is parked, and nil otherwise.
+func (h *health) parked() <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.resumed == nil {
+		return nil
+	}
+	return h.resumed
+}
+
+// claimProbe tries to claim the right to probe the machine.
+// It fails if the machine isn't parked, if another instance is probing it, or if a probe finished less than a probe
+// interval ago.
+func (h *health) claimProbe() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.resumed == nil || h.probing || time.Since(h.lastProbe) < h.probeInterval() {
+		return false
+	}
+	h.probing = true
+	return true
+}
+
+// endProbe records the end of a probe claimed through claimProbe; if ok, the probe passed, and the machine resumes.
+func (h *health) endProbe(ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.probing = false
+	h.lastProbe = time.Now()
+	if !ok {
+		return
+	}
+	h.breaker.reset()
+	close(h.resumed)
+	h.resumed = nil
+	h.gen++
+}
+
+// generation gets the number of times the machine has resumed.
+func (h *health) generation() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.gen
+}
+
+// handleError reports that the cycle in res failed with err, and either backs off or parks the instance.
+//
+// Only the instance whose error trips the machine's breaker reports that the machine has parked; the machine's other
+// instances park alongside it as their cycles finish.
+func (i *Instance) handleError(err error, res cycleResult) {
+	OnCycle(CycleErrorMessage(res.cycle, err), i.Observers...)
+	f := i.Machine.shared.health.fail()
+	if f.tripped {
+		OnInstance(InstanceParkedMessage(f.nerrs), i.Observers...)
+	}
+	if f.resumed != nil {
+		i.park(f.resumed)
+		return
+	}
+	OnInstance(InstanceBackoffMessage(f.nerrs, f.delay), i.Observers...)
+	i.timeoutCh = time.After(f.delay)
+}
+
+// park stops the instance launching cycles until its machine resumes, which happens when resumed closes.
+//
+// Each parked instance tries to probe the machine at every probe interval, but only one probe runs at a time.
+func (i *Instance) park(resumed <-chan struct{}) {
+	i.resumeCh = resumed
+	i.probeCh = time.After(i.Machine.shared.health.probeInterval())
+}
+
+// launchProbe starts a health probe of this instance's machine, unless another instance has beaten us to it.
+func (i *Instance) launchProbe(ctx context.Context) {
+	h := i.Machine.shared.health
+	i.probeCh = nil
+	if !h.claimProbe() {
+		// If the other instance's probe passes, resumeCh will close before this fires.
+		i.probeCh = time.After(h.probeInterval())
+		return
+	}
+
+	ch := make(chan error)
+	go func() {
+		err := i.stageMaker().probe()
+		select {
+		case <-ctx.Done():
+		case ch <- err:
+		}
+	}()
+
+	i.probeResCh = ch
+}
+
+// probe checks that this instance's machine is reachable, by opening (and then closing) a fresh runner factory.
+// For remote machines, this establishes a new SSH connection.
+func (i *Instance) probe() error {
+	f, err := runner.FactoryFromRemoteConfig(i.SSHConfig, i.Machine.Config.SSH)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// handleProbe handles the result err of a health probe.
+//
+// If the probe passed, the machine, and every instance parked on it, resumes cycling; otherwise, we stay parked and
+// probe again later.
+func (i *Instance) handleProbe(ctx context.Context, err error) {
+	h := i.Machine.shared.health
+	i.probeResCh = nil
+	h.endProbe(err == nil)
+	if err != nil {
+		OnInstance(InstanceProbeFailedMessage(err), i.Observers...)
+		i.probeCh = time.After(h.probeInterval())
+		return
+	}
+	OnInstance(InstanceResumedMessage(), i.Observers...)
+	i.resume(ctx)
+}
+
+// resume resumes cycling after the instance's machine has resumed.
+func (i *Instance) resume(ctx context.Context) {
+	i.resumeCh, i.probeCh = nil, nil
+	i.launch(ctx)
+}
+
+// refreshStages closes and recreates the run stages of buffer b, if they predate the machine last resuming (so that,
+// for instance, any remote connections are re-established).
+//
+// Only the run stages touch the machine, so we keep the preparation stages: another buffer may be preparing a cycle
+// with them, and the perturber's coverage would otherwise restart.  We refresh a buffer's stages only when it is about
+// to run a cycle, so nothing else is using them.
+func (i *Instance) refreshStages(b *buffer) error {
+	gen := i.Machine.shared.health.generation()
+	if b.gen == gen {
+		return nil
+	}
+	// The old stages may well fail to close (for instance, if they hold a dead connection); this doesn't matter.
+	_ = closeStages(b.run)
+	var err error
+	if b.run, err = i.stageMaker().makeRunStages(b); err != nil {
+		return err
+	}
+	b.gen = gen
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/quantity"
+)
+
+// TestInstance_park tests that an instance parks after enough errored cycles, probes its machine until it passes, and
+// then resumes with fresh run stages but the same preparation stages.
+func TestInstance_park(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		nruns int
+	)
+	sm := fakeStages{
+		run: func(context.Context, *buffer, *plan.Plan) error {
+			mu.Lock()
+			defer mu.Unlock()
+			nruns++
+			if nruns <= 2 {
+				return errors.New("machine unreachable")
+			}
+			return nil
+		},
+		probeErrs: []error{errors.New("still unreachable")},
+	}
+	qs := quantity.MachineSet{
+		Lookahead: 1,
+		Backoff: quantity.BackoffSet{
+			Initial:       quantity.Timeout(time.Millisecond),
+			Max:           quantity.Timeout(time.Millisecond),
+			BreakAfter:    2,
+			ProbeInterval: quantity.Timeout(time.Millisecond),
+		},
+		// The errored cycles count towards the limit.
+		Limits: quantity.LimitSet{Cycles: 6},
+	}
+	var obs eventObserver
+	i := newTestInstance(t, qs, &sm, &obs)
+	preps := append([]*fakeStage(nil), sm.preps...)
+	runs := append([]*fakeStage(nil), sm.runs...)
+
+	runTestInstance(t, i)
+
+	assert.Equal(t,
+		[]string{"error", "backoff", "error", "parked", "probe-failed", "resumed", "finish", "finish", "finish", "finish"},
+		obs.filter("error", "finish", "backoff", "parked", "probe-failed", "resumed"),
+		"wrong health events")
+	assert.Equal(t, 2, sm.nprobes, "wrong number of probes")
+
+	assert.Equal(t, preps, sm.preps, "preparation stages shouldn't be remade")
+	for _, s := range preps {
+		assert.False(t, s.isClosed(), "preparation stages shouldn't be closed")
+	}
+	if assert.Len(t, sm.runs, 2*len(runs), "run stages should be remade once") {
+		for _, s := range runs {
+			assert.True(t, s.isClosed(), "old run stages should be closed")
+		}
+		for j, b := range i.Machine.buffers {
+			assert.Same(t, sm.runs[len(runs)+j], b.run[0], "buffer should hold its new run stage")
+		}
+	}
+}
+
+// TestInstance_park_shared tests that the instances on a machine park and resume together, with only the instance
+// that trips the breaker reporting the park.
+func TestInstance_park_shared(t *testing.T) {
+	t.Parallel()
+
+	qs := quantity.MachineSet{
+		Lookahead: 1,
+		Backoff: quantity.BackoffSet{
+			Initial:       quantity.Timeout(time.Millisecond),
+			Max:           quantity.Timeout(time.Millisecond),
+			BreakAfter:    1,
+			ProbeInterval: quantity.Timeout(time.Millisecond),
+		},
+		Limits: quantity.LimitSet{Cycles: 9},
+	}
+
+	var (
+		obs [2]eventObserver
+		sms [2]fakeStages
+		is  [2]*Instance
+	)
+	st := newMachineState(qs)
+	lim := newLimiter(qs.Limits)
+
+	// Every run that starts after the machine resumes should have fresh stages.
+	checkGen := func(b *buffer) {
+		if gen := st.health.generation(); gen != 0 {
+			assert.Equal(t, gen, b.gen, "run stages should be remade after the machine resumes")
+		}
+	}
+	var (
+		mu    sync.Mutex
+		first = true
+	)
+	// The first instance's first run fails, tripping the breaker.
+	sms[0].run = func(_ context.Context, b *buffer, _ *plan.Plan) error {
+		checkGen(b)
+		mu.Lock()
+		defer mu.Unlock()
+		if first {
+			first = false
+			return errors.New("machine unreachable")
+		}
+		return nil
+	}
+	// The second instance's first run waits until the machine has parked, and then finishes.
+	var waited bool
+	sms[1].run = func(_ context.Context, b *buffer, _ *plan.Plan) error {
+		checkGen(b)
+		if waited {
+			return nil
+		}
+		waited = true
+		for st.health.parked() == nil && st.health.generation() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		return nil
+	}
+
+	for j := range is {
+		is[j] = newTestInstance(t, qs, &sms[j], &obs[j])
+		is[j].Machine.shared = st
+		is[j].limits = lim
+	}
+
+	var wg sync.WaitGroup
+	for _, i := range is {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runTestInstance(t, i)
+		}()
+	}
+	wg.Wait()
+
+	events := append(obs[0].filter("error", "backoff", "parked", "resumed", "finish"),
+		obs[1].filter("error", "backoff", "parked", "resumed", "finish")...)
+	assert.ElementsMatch(t,
+		[]string{"error", "parked", "resumed", "finish", "finish", "finish", "finish", "finish", "finish", "finish", "finish"},
+		events,
+		"wrong health events")
+	assert.Contains(t, obs[0].filter("parked"), "parked", "the instance that tripped the breaker should report the park")
+	assert.Equal(t, 1, sms[0].nprobes+sms[1].nprobes, "the instances should share one probe")
+	assert.Equal(t, uint64(9), st.cycles, "cycle numbers should be unique across the machine")
+
+	for j := range sms {
+		current := map[*fakeStage]bool{}
+		for _, b := range is[j].Machine.buffers {
+			current[b.run[0].(*fakeStage)] = true
+		}
+		for _, s := range sms[j].runs {
+			if !current[s] {
+				assert.True(t, s.isClosed(), "replaced run stages should be closed")
+			}
+		}
+	}
+}
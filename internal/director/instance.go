@@ -10,19 +10,25 @@ import (
 	"errors"
 	"time"
 
+	"github.com/c4-project/c4t/internal/model/service/compiler"
 	"github.com/c4-project/c4t/internal/mutation"
 
+	"github.com/c4-project/c4t/internal/model/service/backend"
 	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
+	transformer2 "github.com/c4-project/c4t/internal/model/service/transformer"
 
 	"github.com/c4-project/c4t/internal/plan/analysis"
 
 	"github.com/c4-project/c4t/internal/stage/invoker/runner"
 
 	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/c4-project/c4t/internal/subject/corpus"
 
 	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/srvrun"
 
 	"github.com/c4-project/c4t/internal/stage/analyser"
+	"github.com/c4-project/c4t/internal/stage/confirmer"
 
 	"github.com/c4-project/c4t/internal/remote"
 
@@ -30,11 +36,14 @@ import (
 
 	"github.com/c4-project/c4t/internal/stage/lifter"
 
+	"github.com/c4-project/c4t/internal/stage/oracle"
+
+	"github.com/c4-project/c4t/internal/stage/regressor"
+	"github.com/c4-project/c4t/internal/stage/transformer"
+
 	"github.com/c4-project/c4t/internal/stage/fuzzer"
 
 	"github.com/c4-project/c4t/internal/plan"
-
-	"github.com/c4-project/c4t/internal/helper/iohelp"
 )
 
 // Instance contains the state necessary to run a single loop of a director.
@@ -60,21 +69,77 @@ type Instance struct {
 	// FuzzerConfig contains the fuzzer config for this instance.
 	FuzzerConfig *fuzzer2.Config
 
+	// PerturbConfig contains the perturber config for this instance.
+	PerturbConfig *compiler.PerturbConfig
+
+	// SampleConfig contains the corpus sampling config for this instance.
+	SampleConfig *corpus.SampleConfig
+
+	// OracleConfig contains the oracle config for this instance; if nil, the oracle is disabled.
+	OracleConfig *backend.OracleConfig
+
+	// TransformConfig contains the external transformer config for this instance; if nil, there is no transformer.
+	TransformConfig *transformer2.Config
+
+	// Yields, if present, supplies historical compiler yields to the perturber.
+	Yields perturber.YieldSource
+
 	// mutantCh stores a channel that will receive mutations, if any.
 	mutantCh <-chan mutation.Mutant
 
+	// rescanCh stores a channel that will receive rescans of the input paths, if any.
+	rescanCh <-chan rescan
+
+	// rescanDone stores a channel that the instance closes when it stops receiving rescans, if any.
+	rescanDone chan<- struct{}
+
 	// timeoutCh stores the current error cooldown channel, if any.
 	// This is refreshed whenever an error occurs.
 	timeoutCh <-chan time.Time
 
-	// cycleCh stores the current cycle result channel, if any.
-	// This is refreshed whenever a new cycle is launched.
+	// resumeCh stores a channel that closes when the instance's machine resumes, if the instance is parked.
+	resumeCh <-chan struct{}
+
+	// probeCh stores the channel that fires when the next health probe is due, if the instance is parked.
+	probeCh <-chan time.Time
+
+	// probeResCh stores the current health probe result channel, if any.
+	probeResCh <-chan error
+
+	// limits tracks the instance's machine's progress towards its campaign limits.
+	limits *limiter
+
+	// stopCh stores a channel that closes when the director asks the instance to stop gracefully, if any.
+	stopCh <-chan struct{}
+
+	// deadlineCh stores a channel that fires when the machine's time budget runs out, if any.
+	deadlineCh <-chan time.Time
+
+	// draining is true if the instance has stopped starting new cycles.
+	draining bool
+
+	// cycles contains the cycles in the instance's pipeline, oldest (that is, current) first.
+	cycles []*cycleInstance
+
+	// free contains the buffers not in use by any cycle in the pipeline.
+	free []*buffer
+
+	// prepCh stores the result channel of the cycle currently being prepared, if any.
+	prepCh <-chan *cycleInstance
+
+	// cycleCh stores the result channel of the cycle currently being run, if any.
 	cycleCh <-chan cycleResult
+
+	// stages, if non-nil, overrides the instance's own stage making and health probing.
+	stages stageMaker
 }
 
 // Run runs this instance's testing loop.
 func (i *Instance) Run(ctx context.Context) error {
 	err := i.runInner(ctx)
+	if i.rescanDone != nil {
+		close(i.rescanDone)
+	}
 	cerr := i.cleanUp()
 	OnInstance(InstanceClosedMessage(), i.Observers...)
 	return errhelp.FirstError(err, cerr)
@@ -93,17 +158,19 @@ func (i *Instance) prepare(ctx context.Context) error {
 	if err = i.check(); err != nil {
 		return err
 	}
-	if err = i.Machine.Pathset.Scratch.Prepare(); err != nil {
-		return err
+	if i.Machine.shared == nil {
+		// This instance is alone on its machine.
+		i.Machine.shared = newMachineState(i.Machine.Quantities)
 	}
 	// TODO(@MattWindsor91): move this out of the instance, if possible.
 	if err := i.prepareMutation(ctx); err != nil {
 		return err
 	}
 	// This must happen after preparing the mutation config, otherwise the kill channel won't be installed.
-	if i.Machine.stages, err = i.makeStages(); err != nil {
+	if i.Machine.buffers, err = i.makeBuffers(); err != nil {
 		return err
 	}
+	i.free = append([]*buffer(nil), i.Machine.buffers...)
 
 	return nil
 }
@@ -118,8 +185,8 @@ func (i *Instance) cleanUp() error {
 
 func (m *Machine) cleanUp() error {
 	var err error
-	for _, r := range m.stages {
-		err = r.Close()
+	for _, b := range m.buffers {
+		err = b.close()
 	}
 	return err
 }
@@ -149,19 +216,44 @@ func (i *Instance) mainLoop(ctx context.Context) error {
 	i.handleFirstMutant(ctx)
 	i.launch(ctx)
 
-	for {
+	for !i.drained() {
 		select {
 		case <-ctx.Done():
-			i.drainCycleCh()
+			i.abandon()
 			return ctx.Err()
 		case m := <-i.mutantCh:
 			i.handleMutantChange(m)
-		case res := <-i.cycleCh:
+		case rs := <-i.rescanCh:
+			i.handleRescan(rs)
+		case c, ok := <-i.prepCh:
+			// If the channel closed without a cycle, the context has closed, and we'll abandon the pipeline shortly.
+			if !ok {
+				i.prepCh = nil
+				break
+			}
+			i.handlePrepEnd(ctx, c)
+		case res, ok := <-i.cycleCh:
+			// As above.
+			if !ok {
+				i.cycleCh = nil
+				break
+			}
 			i.handleCycleEnd(ctx, res)
 		case <-i.timeoutCh:
 			i.launch(ctx)
+		case <-i.resumeCh:
+			i.resume(ctx)
+		case <-i.probeCh:
+			i.launchProbe(ctx)
+		case err := <-i.probeResCh:
+			i.handleProbe(ctx, err)
+		case <-i.stopCh:
+			i.startDrain(StopRequested)
+		case <-i.deadlineCh:
+			i.startDrain(StopBudget)
 		}
 	}
+	return nil
 }
 
 // handleFirstMutant synchronises with any mutant channel to make sure that we retrieve the first mutant before looping.
@@ -177,68 +269,30 @@ func (i *Instance) handleFirstMutant(ctx context.Context) {
 	}
 }
 
+// handleCycleEnd handles the end of the current cycle, with result res.
 func (i *Instance) handleCycleEnd(ctx context.Context, res cycleResult) {
 	i.cycleCh = nil
-	if res.err != nil {
-		i.handleError(res.err, res)
-		return
-	}
+	err := res.err
 	// Don't clean up scratch after a failing iteration; we might need the information in the scratch
-	if err := i.cleanUpCycle(); err != nil {
-		i.handleError(err, res)
-		return
-	}
-	OnCycle(CycleFinishMessage(res.cycle), i.Observers...)
-	i.Machine.cycle++
-	// Only re-launch if we actually managed to complete the cycle without any errors; otherwise, wait on i.timeoutCh
-	i.launch(ctx)
-}
-
-func (i *Instance) drainCycleCh() {
-	if i.cycleCh == nil {
-		return
+	if err == nil {
+		err = i.cycles[0].buf.cleanUp()
 	}
-	for range i.cycleCh {
+	if err == nil {
+		OnCycle(CycleFinishMessage(res.cycle), i.Observers...)
+		i.Machine.shared.health.succeed()
+	} else {
+		// Errored cycles still count towards the cycle limit, so that a machine that keeps erroring stops in the end.
+		i.handleError(err, res)
 	}
+	i.retire()
+	// Only advance if we actually managed to complete the cycle without any errors; otherwise, wait on i.timeoutCh
+	i.advance(ctx)
 }
 
-func (i *Instance) handleError(err error, res cycleResult) {
-	OnCycle(CycleErrorMessage(res.cycle, err), i.Observers...)
-	i.timeoutCh = time.After(5 * time.Second)
-	// TODO(@MattWindsor91): exponential backoff timeout
-}
-
-// launch launches one iteration of the main testing loop for one machine.
+// launch resumes the main testing loop for one machine.
 func (i *Instance) launch(ctx context.Context) {
 	i.timeoutCh = nil
-
-	c := i.makeCycleInstance()
-	OnCycle(CycleStartMessage(c.cycle), i.Observers...)
-
-	ch := make(chan cycleResult)
-	go func() {
-		err := c.run(ctx)
-		select {
-		case <-ctx.Done():
-		case ch <- cycleResult{cycle: c.cycle, err: err}:
-		}
-		close(ch)
-	}()
-
-	i.cycleCh = ch
-}
-
-func (i *Instance) makeCycleInstance() cycleInstance {
-	return cycleInstance{
-		cycle: Cycle{
-			Instance:  i.Index,
-			MachineID: i.Machine.ID,
-			Iter:      i.Machine.cycle,
-			Start:     time.Now(),
-		},
-		p:      i.plan(),
-		stages: i.Machine.stages,
-	}
+	i.advance(ctx)
 }
 
 func (i *Instance) plan() *plan.Plan {
@@ -247,18 +301,43 @@ func (i *Instance) plan() *plan.Plan {
 	return &pcopy
 }
 
-// makeStages constructs a slice of stage runners that will be
-func (i *Instance) makeStages() ([]plan.Runner, error) {
-	var stages []plan.Runner
+// makeStages constructs the preparation and run stages for buffer b.
+func (i *Instance) makeStages(b *buffer) error {
+	var err error
+	sm := i.stageMaker()
+	if b.prep, err = sm.makePrepStages(b); err != nil {
+		return err
+	}
+	b.run, err = sm.makeRunStages(b)
+	return err
+}
 
-	for _, f := range []func() (plan.Runner, error){
+// makePrepStages constructs the stages that prepare a plan in buffer b.
+func (i *Instance) makePrepStages(b *buffer) ([]plan.Runner, error) {
+	return i.makeRunners(b,
 		i.makePerturber,
 		i.makeFuzzer,
+		i.makeRegressor,
+		i.makeTransformer,
 		i.makeLifter,
+	)
+}
+
+// makeRunStages constructs the stages that run a plan prepared in buffer b, and analyse the results.
+func (i *Instance) makeRunStages(b *buffer) ([]plan.Runner, error) {
+	return i.makeRunners(b,
 		i.makeInvoker,
+		i.makeOracle,
+		i.makeConfirmer,
 		i.makeAnalyser,
-	} {
-		s, err := f()
+	)
+}
+
+// makeRunners constructs stage runners for buffer b using each of fs in turn, skipping any disabled stages.
+func (i *Instance) makeRunners(b *buffer, fs ...func(*buffer) (plan.Runner, error)) ([]plan.Runner, error) {
+	var stages []plan.Runner
+	for _, f := range fs {
+		s, err := f(b)
 		if err != nil {
 			return nil, err
 		}
@@ -269,68 +348,144 @@ func (i *Instance) makeStages() ([]plan.Runner, error) {
 	return stages, nil
 }
 
-func (i *Instance) makeAnalyser() (plan.Runner, error) {
+func (i *Instance) makeAnalyser(*buffer) (plan.Runner, error) {
+	obs := LowerToAnalyser(i.Observers)
+	if i.limits != nil {
+		obs = append(obs, i.limits)
+	}
 	return analyser.New(
-		analyser.ObserveWith(LowerToAnalyser(i.Observers)...),
+		analyser.ObserveWith(obs...),
 		analyser.ObserveSaveWith(LowerToSaver(i.Observers)...),
 		analyser.Analysis(
 			analysis.WithWorkerCount(10), // TODO(@MattWindsor91): get this from somewhere
 			analysis.WithFilters(i.Filters),
 		),
 		analyser.SaveToPathset(&i.Machine.Pathset.Saved),
+		analyser.RegressTo(i.Machine.Pathset.Regress),
 	)
 }
 
-func (i *Instance) makePerturber() (plan.Runner, error) {
+func (i *Instance) makePerturber(b *buffer) (plan.Runner, error) {
 	return perturber.New(
 		i.Env.CInspector,
-		perturber.ObserveWith(LowerToPerturber(i.Observers)...),
+		perturber.ObserveWith(b.rec),
 		perturber.OverrideQuantities(i.Machine.Quantities.Perturb),
 		perturber.UseFullCompilerIDs(true),
+		perturber.UseConfig(i.PerturbConfig),
+		perturber.UseSampleConfig(i.SampleConfig),
+		perturber.UseYields(i.Yields),
+		perturber.ShareCoverage(&i.Machine.shared.coverage),
 	)
 }
 
 // makeFuzzer makes a plan runner for the fuzzer stage.
 // If the fuzzer is disabled, this returns nil.
-func (i *Instance) makeFuzzer() (plan.Runner, error) {
+func (i *Instance) makeFuzzer(b *buffer) (plan.Runner, error) {
 	if i.FuzzerConfig != nil && i.FuzzerConfig.Disabled {
 		return nil, nil
 	}
 
 	return fuzzer.New(
 		i.Env.Fuzzer,
-		fuzzer.NewPathset(i.Machine.Pathset.Scratch.DirFuzz),
-		fuzzer.ObserveWith(LowerToBuilder(i.Observers)...),
+		fuzzer.NewPathset(b.scratch.DirFuzz),
+		fuzzer.ObserveWith(b.rec),
 		fuzzer.OverrideQuantities(i.Machine.Quantities.Fuzz),
 		fuzzer.UseConfig(i.FuzzerConfig),
+		fuzzer.UseSampleConfig(i.SampleConfig),
+	)
+}
+
+// makeRegressor makes a plan runner for the regression stage.
+// If the sampling config doesn't ask for a share of regression subjects, this returns nil.
+func (i *Instance) makeRegressor(*buffer) (plan.Runner, error) {
+	if i.SampleConfig == nil || i.SampleConfig.RegressShare == 0 {
+		return nil, nil
+	}
+	return regressor.New(
+		i.Machine.Pathset.Regress,
+		regressor.UseSampleConfig(i.SampleConfig),
 	)
 }
 
-func (i *Instance) makeLifter() (plan.Runner, error) {
+// makeTransformer makes a plan runner for the external transformer stage.
+// If there is no transformer, or it is disabled, this returns nil.
+func (i *Instance) makeTransformer(b *buffer) (plan.Runner, error) {
+	if i.TransformConfig == nil || i.TransformConfig.Disabled {
+		return nil, nil
+	}
+	return transformer.New(
+		srvrun.NewExecRunner(),
+		i.TransformConfig,
+		b.scratch.DirTransform,
+		transformer.ObserveWith(b.rec),
+		transformer.PopulateStatsWith(i.Env.Fuzzer),
+	)
+}
+
+func (i *Instance) makeLifter(b *buffer) (plan.Runner, error) {
 	return lifter.New(
 		i.Env.BResolver,
-		lifter.NewPathset(i.Machine.Pathset.Scratch.DirLift),
-		lifter.ObserveWith(LowerToBuilder(i.Observers)...),
+		lifter.NewPathset(b.scratch.DirLift),
+		lifter.ObserveWith(b.rec),
+		lifter.OverrideQuantities(i.Machine.Quantities.Lift),
 	)
 }
 
-func (i *Instance) makeInvoker() (plan.Runner, error) {
+func (i *Instance) makeInvoker(b *buffer) (plan.Runner, error) {
+	return i.makeInvokerInDir(b.scratch.DirRun)
+}
+
+// makeInvokerInDir makes an invoker that copies machine node files into ldir, with options os.
+func (i *Instance) makeInvokerInDir(ldir string, os ...invoker.Option) (*invoker.Invoker, error) {
 	// Unlike the single-shot, we don't late-bind the factory using the plan.  This is because we've already
 	// got the machine configuration without it.
 	f, err := runner.FactoryFromRemoteConfig(i.SSHConfig, i.Machine.Config.SSH)
 	if err != nil {
 		return nil, err
 	}
-	return invoker.New(i.Machine.Pathset.Scratch.DirRun,
+	return invoker.New(ldir,
 		f,
 		invoker.ObserveCopiesWith(LowerToCopy(i.Observers)...),
 		invoker.ObserveMachWith(LowerToMach(i.Observers)...),
 		// As above, there is no loading of quantities using the plan, as we already know which machine the plan is
 		// targeting without consulting the plan.
 		invoker.OverrideBaseQuantities(i.Machine.Quantities.Mach),
+		invoker.Options(os...),
 	)
 }
 
-func (i *Instance) cleanUpCycle() error {
-	return iohelp.Rmdirs(i.Machine.Pathset.Scratch.Dirs()...)
+// makeConfirmer makes a plan runner for the confirmation stage.
+// If confirmation is disabled, this returns nil.
+func (i *Instance) makeConfirmer(b *buffer) (plan.Runner, error) {
+	qs := i.Machine.Quantities.Confirm
+	if qs.NRepeats <= 0 {
+		return nil, nil
+	}
+
+	// The confirmer needs its own invoker, as it re-invokes already-invoked plans into a separate directory.
+	inv, err := i.makeInvokerInDir(b.scratch.DirConfirm, invoker.AllowReinvoke(true))
+	if err != nil {
+		return nil, err
+	}
+	return confirmer.New(
+		inv,
+		confirmer.ObserveWith(LowerToBuilder(i.Observers)...),
+		confirmer.OverrideQuantities(qs),
+	)
+}
+
+// makeOracle makes a plan runner for the oracle stage.
+// If the oracle is disabled, this returns nil.
+func (i *Instance) makeOracle(b *buffer) (plan.Runner, error) {
+	if i.OracleConfig == nil || i.OracleConfig.Disabled {
+		return nil, nil
+	}
+
+	return oracle.New(
+		i.Env.BResolver,
+		b.scratch.DirOracle,
+		oracle.ObserveWith(LowerToBuilder(i.Observers)...),
+		oracle.UseConfig(i.OracleConfig),
+		oracle.OverrideQuantities(i.Machine.Quantities.Oracle),
+	)
 }
@@ -0,0 +1,202 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/director/pathset"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+)
+
+// fakeStage is a stage that hands each plan it runs to a callback, and otherwise does nothing.
+type fakeStage struct {
+	// st is the stage that this fake pretends to be.
+	st stage.Stage
+	// f, if non-nil, is called on each plan; if it fails, so does the stage.
+	f func(ctx context.Context, p *plan.Plan) error
+
+	// mu guards closed.
+	mu sync.Mutex
+	// closed is true once the stage has been closed.
+	closed bool
+}
+
+func (s *fakeStage) Stage() stage.Stage {
+	return s.st
+}
+
+func (s *fakeStage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeStage) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *fakeStage) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
+	if s.f != nil {
+		if err := s.f(ctx, p); err != nil {
+			return nil, err
+		}
+	}
+	np := *p
+	return &np, nil
+}
+
+// fakeStages is a stage maker that makes one fake stage each for preparing and running plans.
+type fakeStages struct {
+	// prep and run, if non-nil, are called by the preparation and run stages respectively, with the buffer in which
+	// each stage works.
+	prep, run func(ctx context.Context, b *buffer, p *plan.Plan) error
+	// probeErrs contains the results of successive health probes; once exhausted, probes pass.
+	probeErrs []error
+
+	// mu guards the fields below.
+	mu sync.Mutex
+	// preps and runs contain every preparation and run stage made so far.
+	preps, runs []*fakeStage
+	// nprobes counts the health probes so far.
+	nprobes int
+}
+
+func (f *fakeStages) makePrepStages(b *buffer) ([]plan.Runner, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := &fakeStage{st: stage.Lift, f: bindBuffer(f.prep, b)}
+	f.preps = append(f.preps, s)
+	return []plan.Runner{s}, nil
+}
+
+func (f *fakeStages) makeRunStages(b *buffer) ([]plan.Runner, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := &fakeStage{st: stage.Invoke, f: bindBuffer(f.run, b)}
+	f.runs = append(f.runs, s)
+	return []plan.Runner{s}, nil
+}
+
+// bindBuffer binds the buffer argument of f, if non-nil, to b.
+func bindBuffer(f func(context.Context, *buffer, *plan.Plan) error, b *buffer) func(context.Context, *plan.Plan) error {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, p *plan.Plan) error { return f(ctx, b, p) }
+}
+
+func (f *fakeStages) probe() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nprobes++
+	if f.nprobes <= len(f.probeErrs) {
+		return f.probeErrs[f.nprobes-1]
+	}
+	return nil
+}
+
+// eventObserver records the cycle, instance, and perturber messages that an instance sends, as short event names.
+//
+// The fake stages don't send any other observations, so we leave the rest of the observer interface unimplemented.
+type eventObserver struct {
+	InstanceObserver
+
+	// mu guards events.
+	mu sync.Mutex
+	// events contains the recorded events, in order.
+	events []string
+}
+
+func (o *eventObserver) OnCycle(m CycleMessage) {
+	o.record([...]string{CycleStart: "start", CycleFinish: "finish", CycleError: "error"}[m.Kind])
+}
+
+func (o *eventObserver) OnInstance(m InstanceMessage) {
+	o.record([...]string{
+		KindInstanceClosed:      "closed",
+		KindInstanceMutant:      "mutant",
+		KindInstanceRescan:      "rescan",
+		KindInstanceBackoff:     "backoff",
+		KindInstanceParked:      "parked",
+		KindInstanceProbeFailed: "probe-failed",
+		KindInstanceResumed:     "resumed",
+		KindInstanceStopping:    "stopping",
+	}[m.Kind])
+}
+
+func (o *eventObserver) OnPerturb(perturber.Message) {
+	o.record("perturb")
+}
+
+func (o *eventObserver) record(e string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, e)
+}
+
+// filter gets the recorded events that appear in keep, in order.
+func (o *eventObserver) filter(keep ...string) []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var es []string
+	for _, e := range o.events {
+		for _, k := range keep {
+			if e == k {
+				es = append(es, e)
+				break
+			}
+		}
+	}
+	return es
+}
+
+// newTestInstance makes an instance with quantities qs whose buffers contain the fake stages made by sm, and which
+// sends messages to obs.
+//
+// This mirrors Instance.prepare, without the parts that need a real environment.
+func newTestInstance(t *testing.T, qs quantity.MachineSet, sm stageMaker, obs ...InstanceObserver) *Instance {
+	t.Helper()
+
+	i := Instance{
+		Machine: &Machine{
+			ID:         id.FromString("foo"),
+			Pathset:    &pathset.Instance{Scratch: *pathset.NewScratch(t.TempDir())},
+			Quantities: qs,
+			shared:     newMachineState(qs),
+		},
+		Observers: obs,
+		limits:    newLimiter(qs.Limits),
+		stages:    sm,
+	}
+
+	var err error
+	i.Machine.buffers, err = i.makeBuffers()
+	require.NoError(t, err, "making buffers")
+	i.free = append([]*buffer(nil), i.Machine.buffers...)
+	return &i
+}
+
+// runTestInstance runs i's main loop until it drains, failing the test if this takes too long.
+func runTestInstance(t *testing.T, i *Instance) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, i.mainLoop(ctx), "running instance")
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"sync"
+
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// StopReason is the enumeration of reasons why an instance might stop starting new cycles.
+type StopReason uint8
+
+const (
+	// StopRequested means that someone asked the director to stop gracefully.
+	StopRequested StopReason = iota
+	// StopBudget means that the machine has used up its time budget.
+	StopBudget
+	// StopCycles means that the machine has run its maximum number of cycles, counting errored ones.
+	StopCycles
+	// StopFlagged means that the machine has found its maximum number of flagged or forbidden subjects.
+	StopFlagged
+)
+
+//go:generate stringer -type StopReason
+
+// limiter tracks a machine's progress towards its campaign limits, across all of its instances.
+//
+// A nil limiter never reaches any limits.
+type limiter struct {
+	// qs contains the limits.
+	qs quantity.LimitSet
+
+	// mu guards the fields below.
+	mu sync.Mutex
+	// claimed counts the cycles that instances have claimed, and not given back.
+	// Instances only give back cycles that they drop before running; errored cycles stay claimed.
+	claimed int
+	// flagged counts the flagged and forbidden subjects found so far.
+	flagged int
+}
+
+// newLimiter makes a limiter for the limits in qs.
+func newLimiter(qs quantity.LimitSet) *limiter {
+	return &limiter{qs: qs}
+}
+
+// claimCycle tries to claim a cycle against the machine's cycle limit, returning false if there are none left.
+func (l *limiter) claimCycle() bool {
+	if l == nil || l.qs.Cycles <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.qs.Cycles <= l.claimed {
+		return false
+	}
+	l.claimed++
+	return true
+}
+
+// unclaimCycle gives back a cycle claimed by claimCycle that never ran.
+func (l *limiter) unclaimCycle() {
+	if l == nil || l.qs.Cycles <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.claimed--
+}
+
+// flaggedReached gets whether the machine has found its maximum number of flagged or forbidden subjects.
+func (l *limiter) flaggedReached() bool {
+	if l == nil || l.qs.Flagged <= 0 {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.qs.Flagged <= l.flagged
+}
+
+// OnAnalysis counts the flagged and forbidden subjects in a.
+//
+// Both statuses mark subjects that the director saves as interesting, so both count towards the limit.
+func (l *limiter) OnAnalysis(a analysis.Analysis) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flagged += len(a.ByStatus[status.Flagged]) + len(a.ByStatus[status.Forbidden])
+}
+
+// startDrain stops this instance from starting new cycles, for reason r.
+//
+// Once any cycles still in the pipeline have finished, the instance closes.  Cycles that were being prepared ahead of
+// the current cycle haven't touched the machine, so we drop them; the exception is when the machine has run out of
+// cycles, as the instance has claimed those cycles against the limit.
+func (i *Instance) startDrain(r StopReason) {
+	if i.draining {
+		return
+	}
+	i.draining = true
+	i.stopCh, i.deadlineCh = nil, nil
+	if r != StopCycles {
+		keep := 0
+		if len(i.cycles) != 0 && i.cycles[0].started {
+			keep = 1
+		}
+		for _, c := range i.cycles[keep:] {
+			i.drop(c)
+		}
+		i.cycles = i.cycles[:keep]
+	}
+	OnInstance(InstanceStoppingMessage(r), i.Observers...)
+}
+
+// drop drops c, a cycle that hasn't started yet, giving back its claim on the cycle limit.
+//
+// If c is still preparing, its buffer stays in use until the preparation finishes (see handlePrepEnd).
+func (i *Instance) drop(c *cycleInstance) {
+	c.dropped = true
+	i.limits.unclaimCycle()
+	if c.prepared {
+		i.discard(c)
+	}
+}
+
+// discard frees the buffer of c, a dropped cycle that has finished preparing, throwing away its preparations.
+func (i *Instance) discard(c *cycleInstance) {
+	c.buf.rec.discard()
+	// Nothing will look at the scratch of a cycle that never ran, and it would otherwise leak into the buffer's next
+	// cycle; there isn't much we can do if we can't remove it, though.
+	_ = c.buf.cleanUp()
+	i.free = append(i.free, c.buf)
+}
+
+// drained gets whether this instance has stopped starting new cycles, and has no cycles left in its pipeline.
+//
+// We also wait for any health probe, so that the instance releases its claim on probing the machine.
+func (i *Instance) drained() bool {
+	return i.draining && len(i.cycles) == 0 && i.prepCh == nil && i.cycleCh == nil && i.probeResCh == nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// TestLimiter_OnAnalysis tests that the limiter counts both flagged and forbidden subjects towards the flagged limit.
+func TestLimiter_OnAnalysis(t *testing.T) {
+	t.Parallel()
+
+	l := newLimiter(quantity.LimitSet{Flagged: 3})
+	l.OnAnalysis(analysis.Analysis{ByStatus: map[status.Status]corpus.Corpus{
+		status.Flagged: {"foo": {}},
+		status.Ok:      {"bar": {}},
+	}})
+	assert.False(t, l.flaggedReached(), "one flagged subject shouldn't reach the limit")
+	l.OnAnalysis(analysis.Analysis{ByStatus: map[status.Status]corpus.Corpus{
+		status.Forbidden: {"baz": {}, "barbaz": {}},
+	}})
+	assert.True(t, l.flaggedReached(), "forbidden subjects should count towards the limit")
+}
+
+// TestInstance_startDrain_preparing tests that an instance that stops while preparing a cycle ahead drops that cycle,
+// and frees its buffer once the preparation finishes.
+func TestInstance_startDrain_preparing(t *testing.T) {
+	t.Parallel()
+
+	var (
+		obs  eventObserver
+		mu   sync.Mutex
+		nrep int64
+		// blocked closes once the second cycle has started preparing; release lets it finish.
+		blocked = make(chan struct{})
+		release = make(chan struct{})
+		stop    = make(chan struct{})
+	)
+	sm := fakeStages{
+		prep: func(_ context.Context, b *buffer, p *plan.Plan) error {
+			mu.Lock()
+			nrep++
+			p.Metadata.Seed = nrep
+			mu.Unlock()
+
+			b.rec.OnPerturb(perturber.Message{})
+			if err := os.WriteFile(filepath.Join(b.scratch.Dirs()[0], "prepared"), nil, 0644); err != nil {
+				return err
+			}
+			if p.Metadata.Seed == 2 {
+				close(blocked)
+				<-release
+			}
+			return nil
+		},
+		run: func(_ context.Context, _ *buffer, p *plan.Plan) error {
+			if p.Metadata.Seed != 1 {
+				t.Errorf("cycle %d shouldn't run", p.Metadata.Seed)
+				return nil
+			}
+			// Stop the instance while the next cycle is still preparing, and let that cycle finish preparing only once
+			// the instance has started draining.
+			<-blocked
+			close(stop)
+			for len(obs.filter("stopping")) == 0 {
+				runtime.Gosched()
+			}
+			close(release)
+			return nil
+		},
+	}
+	qs := quantity.MachineSet{Lookahead: 1, Limits: quantity.LimitSet{Cycles: 10}}
+	i := newTestInstance(t, qs, &sm, &obs)
+	i.stopCh = stop
+	runTestInstance(t, i)
+
+	assert.Equal(t, []string{"start", "perturb", "stopping", "finish"}, obs.filter("start", "perturb", "stopping", "finish"),
+		"the dropped cycle shouldn't start, nor forward its observations")
+	assert.Equal(t, 1, i.limits.claimed, "the dropped cycle should give back its claim")
+	assert.ElementsMatch(t, i.Machine.buffers, i.free, "every buffer should be free")
+	for _, b := range i.Machine.buffers {
+		require.False(t, b.rec.held, "recorders shouldn't hold back observations")
+		require.Empty(t, b.rec.pending, "recorders shouldn't keep dropped observations")
+		assert.NoFileExists(t, filepath.Join(b.scratch.Dirs()[0], "prepared"), "buffers should be cleaned up")
+	}
+}
+
+// TestInstance_limits_errored tests that errored cycles count towards the cycle limit, so that an instance whose
+// cycles keep erroring still stops.
+func TestInstance_limits_errored(t *testing.T) {
+	t.Parallel()
+
+	sm := fakeStages{
+		run: func(context.Context, *buffer, *plan.Plan) error {
+			return errors.New("machine unreachable")
+		},
+	}
+	qs := quantity.MachineSet{
+		// With no breaker, the instance would back off and retry forever.
+		Backoff: quantity.BackoffSet{
+			Initial: quantity.Timeout(time.Millisecond),
+			Max:     quantity.Timeout(time.Millisecond),
+		},
+		Limits: quantity.LimitSet{Cycles: 3},
+	}
+	var obs eventObserver
+	runTestInstance(t, newTestInstance(t, qs, &sm, &obs))
+
+	assert.Equal(t, []string{"error", "error", "error", "stopping"}, obs.filter("error", "finish", "stopping"),
+		"instance should stop after its cycle limit, even if every cycle errors")
+}
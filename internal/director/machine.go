@@ -7,6 +7,7 @@ package director
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/c4-project/c4t/internal/director/pathset"
 	"github.com/c4-project/c4t/internal/helper/iohelp"
@@ -14,6 +15,7 @@ import (
 	"github.com/c4-project/c4t/internal/machine"
 	"github.com/c4-project/c4t/internal/plan"
 	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/perturber"
 )
 
 // TODO(@MattWindsor91): make a proper distinction between instances and machines.
@@ -35,12 +37,35 @@ type Machine struct {
 	// Config contains the machine config for this machine.
 	Config machine.Config
 
-	// cycle is the number of the current cycle for the machine.
-	// This is held separately from the instance as an instance may (eventually) run cycles for multiple machines.
-	cycle uint64
+	// buffers contains the scratch buffers, and their stages, for this machine.
+	buffers []*buffer
 
-	// stages is the set of stages to run for this machine.
-	stages []plan.Runner
+	// shared contains the state that this machine shares with every other instance running on the same machine.
+	shared *machineState
+}
+
+// machineState contains the state shared between all of the instances running on a machine.
+type machineState struct {
+	// cycles counts the cycles started on the machine.
+	cycles uint64
+
+	// health tracks the machine's consecutive errors, and whether it is parked.
+	health *health
+
+	// coverage tracks the perturbers' coverage of each compiler's configuration space.
+	// Every buffer's perturber shares it, so that cycles prepared ahead, or on other instances, don't repeat the
+	// choices of earlier cycles.
+	coverage perturber.CoverageMap
+}
+
+// newMachineState makes the shared state for a machine with quantity set qs.
+func newMachineState(qs quantity.MachineSet) *machineState {
+	return &machineState{health: newHealth(qs.Backoff)}
+}
+
+// nextCycle claims the number of the next cycle on the machine.
+func (s *machineState) nextCycle() uint64 {
+	return atomic.AddUint64(&s.cycles, 1) - 1
 }
 
 func (m *Machine) check() error {
@@ -154,6 +154,19 @@ type InstanceObserver interface {
 type InstanceMessage struct {
 	Kind   InstanceMessageKind
 	Mutant mutation.Mutant
+	// Added contains, if Kind is KindInstanceRescan, the names of the subjects added to the instance's corpus.
+	Added []string
+	// Rejected contains, if Kind is KindInstanceRescan, the paths of new input files that couldn't be probed, and
+	// the output directories of any generators that failed.
+	Rejected []string
+	// Errors contains, if Kind is KindInstanceBackoff or KindInstanceParked, the number of consecutive errored cycles.
+	Errors int
+	// Delay contains, if Kind is KindInstanceBackoff, how long the instance will wait before its next cycle.
+	Delay time.Duration
+	// Err contains, if Kind is KindInstanceProbeFailed, the error from the health probe.
+	Err error
+	// Stop contains, if Kind is KindInstanceStopping, the reason why the instance is stopping.
+	Stop StopReason
 }
 
 // InstanceMessageKind is the enumeration of kinds of instance message.
@@ -165,6 +178,25 @@ const (
 	KindInstanceClosed InstanceMessageKind = iota
 	// KindInstanceMutant means that the instance has changed to a new mutant (in Mutant).
 	KindInstanceMutant
+	// KindInstanceRescan means that a rescan of the input paths found new input files (in Added and Rejected).
+	KindInstanceRescan
+	// KindInstanceBackoff means that, after a number of consecutive errored cycles (in Errors), the instance is waiting
+	// (for Delay) before starting another cycle.
+	KindInstanceBackoff
+	// KindInstanceParked means that, after a number of consecutive errored cycles (in Errors), the circuit breaker of
+	// the instance's machine has tripped: no instance on the machine will start any more cycles until the machine
+	// passes a health probe.
+	//
+	// Only the instance whose error trips the breaker sends this message; the machine's other instances park silently.
+	KindInstanceParked
+	// KindInstanceProbeFailed means that the instance is parked, and has probed its machine, which failed (with Err).
+	KindInstanceProbeFailed
+	// KindInstanceResumed means that the instance has probed its machine, which passed, and so every instance on the
+	// machine is resuming.
+	KindInstanceResumed
+	// KindInstanceStopping means that the instance has stopped starting new cycles (for the reason in Stop), and will
+	// close once its current cycles finish.
+	KindInstanceStopping
 )
 
 // InstanceClosedMessage constructs an InstanceMessage stating that the instance has closed.
@@ -177,6 +209,40 @@ func InstanceMutantMessage(m mutation.Mutant) InstanceMessage {
 	return InstanceMessage{Kind: KindInstanceMutant, Mutant: m}
 }
 
+// InstanceRescanMessage constructs an InstanceMessage stating that a rescan added the subjects named in added to the
+// instance's corpus, and couldn't probe the new input files in rejected.
+func InstanceRescanMessage(added, rejected []string) InstanceMessage {
+	return InstanceMessage{Kind: KindInstanceRescan, Added: added, Rejected: rejected}
+}
+
+// InstanceBackoffMessage constructs an InstanceMessage stating that the instance is waiting for d after nerrs
+// consecutive errored cycles.
+func InstanceBackoffMessage(nerrs int, d time.Duration) InstanceMessage {
+	return InstanceMessage{Kind: KindInstanceBackoff, Errors: nerrs, Delay: d}
+}
+
+// InstanceParkedMessage constructs an InstanceMessage stating that the instance has parked after nerrs consecutive
+// errored cycles.
+func InstanceParkedMessage(nerrs int) InstanceMessage {
+	return InstanceMessage{Kind: KindInstanceParked, Errors: nerrs}
+}
+
+// InstanceProbeFailedMessage constructs an InstanceMessage stating that the instance's machine failed a health probe
+// with error err.
+func InstanceProbeFailedMessage(err error) InstanceMessage {
+	return InstanceMessage{Kind: KindInstanceProbeFailed, Err: err}
+}
+
+// InstanceResumedMessage constructs an InstanceMessage stating that the instance has resumed after being parked.
+func InstanceResumedMessage() InstanceMessage {
+	return InstanceMessage{Kind: KindInstanceResumed}
+}
+
+// InstanceStoppingMessage constructs an InstanceMessage stating that the instance is stopping for reason r.
+func InstanceStoppingMessage(r StopReason) InstanceMessage {
+	return InstanceMessage{Kind: KindInstanceStopping, Stop: r}
+}
+
 // OnInstance sends OnInstance to each observer in obs.
 func OnInstance(m InstanceMessage, obs ...InstanceObserver) {
 	for _, o := range obs {
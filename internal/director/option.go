@@ -11,6 +11,8 @@ import (
 	"github.com/c4-project/c4t/internal/model/service/backend"
 
 	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/model/service/generator"
+	"github.com/c4-project/c4t/internal/model/service/transformer"
 
 	"github.com/c4-project/c4t/internal/plan/analysis"
 
@@ -18,6 +20,7 @@ import (
 
 	"github.com/c4-project/c4t/internal/model/service/compiler"
 	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/c4-project/c4t/internal/subject/corpus"
 
 	"github.com/c4-project/c4t/internal/id"
 
@@ -151,6 +154,69 @@ func FuzzerConfig(cfg *fuzzer2.Config) Option {
 	}
 }
 
+// PerturbConfig sets the perturber configuration to cfg.
+func PerturbConfig(cfg *compiler.PerturbConfig) Option {
+	return func(d *Director) error {
+		d.pcfg = cfg
+		return nil
+	}
+}
+
+// SampleConfig sets the corpus sampling configuration, used by both the perturber and fuzzer, to cfg.
+func SampleConfig(cfg *corpus.SampleConfig) Option {
+	return func(d *Director) error {
+		d.scfg = cfg
+		return nil
+	}
+}
+
+// OracleConfig sets the oracle configuration to cfg.
+// If cfg is nil, the oracle is disabled.
+func OracleConfig(cfg *backend.OracleConfig) Option {
+	return func(d *Director) error {
+		d.ocfg = cfg
+		return nil
+	}
+}
+
+// TransformConfig sets the external transformer configuration to cfg.
+// If cfg is nil, there is no transformer stage.
+func TransformConfig(cfg *transformer.Config) Option {
+	return func(d *Director) error {
+		if cfg != nil && !cfg.Disabled {
+			if err := cfg.Check(); err != nil {
+				return err
+			}
+		}
+		d.tcfg = cfg
+		return nil
+	}
+}
+
+// Yields sets the source of historical compiler yields used by each instance's perturber.
+func Yields(src perturber.YieldSource) Option {
+	return func(d *Director) error {
+		d.yields = src
+		return nil
+	}
+}
+
+// Generators adds the enabled generators among gs to the director's corpus generators.
+func Generators(gs ...generator.Config) Option {
+	return func(d *Director) error {
+		for _, g := range gs {
+			if g.Disabled {
+				continue
+			}
+			if err := g.Check(); err != nil {
+				return err
+			}
+			d.gens.configs = append(d.gens.configs, g)
+		}
+		return nil
+	}
+}
+
 // Env groups together the bits of configuration that pertain to dealing with the environment.
 type Env struct {
 	// Fuzzer is a single-shot fuzzing driver.
@@ -189,6 +255,11 @@ func ConfigFromGlobal(g *config.Config) Option {
 		OutDir(g.Paths.OutDir),
 		OverrideQuantities(g.Quantities),
 		FuzzerConfig(g.Fuzz),
+		PerturbConfig(g.Perturb),
+		SampleConfig(g.Sample),
+		OracleConfig(g.Oracle),
+		TransformConfig(g.Transform),
+		Generators(g.Generators...),
 		SSH(g.SSH),
 	)
 }
@@ -5,7 +5,10 @@
 
 package pathset
 
-import "github.com/c4-project/c4t/internal/stage/analyser/saver"
+import (
+	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stage/regressor"
+)
 
 // Instance is an instance-specific pathset.
 type Instance struct {
@@ -13,4 +16,7 @@ type Instance struct {
 	Saved saver.Pathset
 	// ScratchPaths contains the scratch pathset for this machine.
 	Scratch Scratch
+	// Regress contains the regression corpus store for this machine.
+	// This is shared between all instances on the same machine.
+	Regress *regressor.Store
 }
@@ -9,15 +9,19 @@ package pathset
 
 import (
 	"path/filepath"
+	"strconv"
 
 	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stage/regressor"
 
 	"github.com/c4-project/c4t/internal/id"
 )
 
 const (
-	segSaved   = "saved"
-	segScratch = "scratch"
+	segGenerated = "generated"
+	segRegress   = "regress"
+	segSaved     = "saved"
+	segScratch   = "scratch"
 )
 
 // Pathset contains the pre-computed paths used by the director.
@@ -27,13 +31,21 @@ type Pathset struct {
 
 	// DirScratch is the directory that the director uses for ephemeral run data.
 	DirScratch string
+
+	// DirRegress is the directory in which the director keeps its regression corpora.
+	DirRegress string
+
+	// DirGenerated is the directory into which the director's corpus generators write their tests.
+	DirGenerated string
 }
 
 // New constructs a new pathset from the directory root.
 func New(root string) *Pathset {
 	return &Pathset{
-		DirSaved:   filepath.Join(root, segSaved),
-		DirScratch: filepath.Join(root, segScratch),
+		DirSaved:     filepath.Join(root, segSaved),
+		DirScratch:   filepath.Join(root, segScratch),
+		DirRegress:   filepath.Join(root, segRegress),
+		DirGenerated: filepath.Join(root, segGenerated),
 	}
 }
 
@@ -42,9 +54,35 @@ func (p Pathset) Instance(mid id.ID) *Instance {
 	tags := mid.Tags()
 	saved := append([]string{p.DirSaved}, tags...)
 	scratch := append([]string{p.DirScratch}, tags...)
+	regress := append([]string{p.DirRegress}, tags...)
 	// TODO(@MattWindsor91): the pointer soup here needs simplifying
 	return &Instance{
 		Saved:   *saver.NewPathset(filepath.Join(saved...)),
 		Scratch: *NewScratch(filepath.Join(scratch...)),
+		Regress: regressor.NewStore(filepath.Join(regress...)),
+	}
+}
+
+// Instances gets n instance pathsets for a machine with ID mid.
+//
+// If n is 1 (or less), this is just the pathset from Instance.  Otherwise, each instance gets its own numbered
+// subdirectory of the machine's scratch and saved directories, but all share the machine's regression store.
+func (p Pathset) Instances(mid id.ID, n int) []*Instance {
+	mi := p.Instance(mid)
+	if n <= 1 {
+		return []*Instance{mi}
+	}
+	tags := mid.Tags()
+	saved := filepath.Join(append([]string{p.DirSaved}, tags...)...)
+	scratch := filepath.Join(append([]string{p.DirScratch}, tags...)...)
+	is := make([]*Instance, n)
+	for i := range is {
+		seg := strconv.Itoa(i)
+		is[i] = &Instance{
+			Saved:   *saver.NewPathset(filepath.Join(saved, seg)),
+			Scratch: *NewScratch(filepath.Join(scratch, seg)),
+			Regress: mi.Regress,
+		}
 	}
+	return is
 }
@@ -24,15 +24,19 @@ func ExampleNew() {
 
 	fmt.Println(filepath.ToSlash(p.DirScratch))
 	fmt.Println(filepath.ToSlash(p.DirSaved))
+	fmt.Println(filepath.ToSlash(p.DirRegress))
+	fmt.Println(filepath.ToSlash(p.DirGenerated))
 
 	// Output:
 	// tests/scratch
 	// tests/saved
+	// tests/regress
+	// tests/generated
 }
 
 // ExamplePathset_Instance is a runnable example for Pathset.Instance.
 func ExamplePathset_Instance() {
-	p := pathset.Pathset{DirSaved: "saved", DirScratch: "scratch"}
+	p := pathset.Pathset{DirSaved: "saved", DirScratch: "scratch", DirRegress: "regress"}
 	mid := id.FromString("foo.bar.baz")
 	mi := p.Instance(mid)
 
@@ -42,16 +46,42 @@ func ExamplePathset_Instance() {
 	for _, path := range mi.Saved.DirList() {
 		fmt.Println(filepath.ToSlash(path))
 	}
+	fmt.Println(filepath.ToSlash(mi.Regress.DirRoot))
 
 	// Output:
 	// scratch/foo/bar/baz/fuzz
 	// scratch/foo/bar/baz/lift
 	// scratch/foo/bar/baz/run
+	// scratch/foo/bar/baz/oracle
+	// scratch/foo/bar/baz/confirm
+	// scratch/foo/bar/baz/transform
 	// saved/foo/bar/baz/flagged
 	// saved/foo/bar/baz/compile_fail
 	// saved/foo/bar/baz/compile_timeout
 	// saved/foo/bar/baz/run_fail
 	// saved/foo/bar/baz/run_timeout
+	// saved/foo/bar/baz/forbidden
+	// regress/foo/bar/baz
+}
+
+// ExamplePathset_Instances is a runnable example for Pathset.Instances.
+func ExamplePathset_Instances() {
+	p := pathset.Pathset{DirSaved: "saved", DirScratch: "scratch", DirRegress: "regress"}
+	mid := id.FromString("foo.bar")
+
+	fmt.Println(len(p.Instances(mid, 1)), filepath.ToSlash(p.Instances(mid, 1)[0].Scratch.DirRun))
+
+	mis := p.Instances(mid, 2)
+	for _, mi := range mis {
+		fmt.Println(filepath.ToSlash(mi.Scratch.DirRun), filepath.ToSlash(mi.Saved.DirList()[0]))
+	}
+	fmt.Println(filepath.ToSlash(mis[0].Regress.DirRoot), mis[0].Regress == mis[1].Regress)
+
+	// Output:
+	// 1 scratch/foo/bar/run
+	// scratch/foo/bar/0/run saved/foo/bar/0/flagged
+	// scratch/foo/bar/1/run saved/foo/bar/1/flagged
+	// regress/foo/bar true
 }
 
 // TestPathset_Prepare tests Scratch.Prepare.
@@ -65,4 +95,6 @@ func TestPathset_Prepare(t *testing.T) {
 	require.NoError(t, p.Prepare(), "prepare shouldn't error on temp dir")
 	assert.DirExists(t, p.DirSaved, "saved dir should now exist")
 	assert.DirExists(t, p.DirScratch, "saved dir should now exist")
+	assert.DirExists(t, p.DirRegress, "regress dir should now exist")
+	assert.DirExists(t, p.DirGenerated, "generated dir should now exist")
 }
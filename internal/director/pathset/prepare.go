@@ -11,5 +11,5 @@ import (
 
 // Prepare prepares this pathset by making its directories.
 func (p *Pathset) Prepare() error {
-	return iohelp.Mkdirs(p.DirSaved, p.DirScratch)
+	return iohelp.Mkdirs(p.DirSaved, p.DirScratch, p.DirRegress, p.DirGenerated)
 }
@@ -7,38 +7,69 @@ package pathset
 
 import (
 	"path/filepath"
+	"strconv"
 
 	"github.com/c4-project/c4t/internal/helper/iohelp"
 )
 
 const (
-	segFuzz = "fuzz"
-	segLift = "lift"
-	segRun  = "run"
+	segBuffer    = "buffer"
+	segConfirm   = "confirm"
+	segFuzz      = "fuzz"
+	segLift      = "lift"
+	segOracle    = "oracle"
+	segRun       = "run"
+	segTransform = "transform"
 )
 
 // Scratch contains the pre-computed paths for a machine run.
 type Scratch struct {
+	// DirRoot is the directory under which all of the other directories sit.
+	DirRoot string
 	// DirFuzz is the directory to which fuzzed subjects will be output.
 	DirFuzz string
 	// DirLift is the directory to which lifter outputs will be written.
 	DirLift string
 	// DirRun is the directory into which c4t-mach output will go.
 	DirRun string
+	// DirOracle is the directory to which reference model outputs will be written.
+	DirOracle string
+	// DirConfirm is the directory into which c4t-mach output will go when confirming bad results.
+	DirConfirm string
+	// DirTransform is the directory to which transformed subjects will be output.
+	DirTransform string
 }
 
 // NewScratch creates a machine pathset rooted at root.
 func NewScratch(root string) *Scratch {
 	return &Scratch{
-		DirFuzz: filepath.Join(root, segFuzz),
-		DirLift: filepath.Join(root, segLift),
-		DirRun:  filepath.Join(root, segRun),
+		DirRoot:      root,
+		DirFuzz:      filepath.Join(root, segFuzz),
+		DirLift:      filepath.Join(root, segLift),
+		DirRun:       filepath.Join(root, segRun),
+		DirOracle:    filepath.Join(root, segOracle),
+		DirConfirm:   filepath.Join(root, segConfirm),
+		DirTransform: filepath.Join(root, segTransform),
 	}
 }
 
 // Dirs gets all of the directories in this pathset, which is useful for making and removing directories.
 func (p *Scratch) Dirs() []string {
-	return []string{p.DirFuzz, p.DirLift, p.DirRun}
+	return []string{p.DirFuzz, p.DirLift, p.DirRun, p.DirOracle, p.DirConfirm, p.DirTransform}
+}
+
+// Buffers splits this pathset into n buffers, so that several cycles can use scratch space at once.
+//
+// If n is 1 (or less), the only buffer is this pathset; otherwise, each buffer sits in its own numbered subdirectory.
+func (p *Scratch) Buffers(n int) []*Scratch {
+	if n <= 1 {
+		return []*Scratch{p}
+	}
+	bs := make([]*Scratch, n)
+	for i := range bs {
+		bs[i] = NewScratch(filepath.Join(p.DirRoot, segBuffer, strconv.Itoa(i)))
+	}
+	return bs
 }
 
 // Prepare prepares this pathset by making its directories.
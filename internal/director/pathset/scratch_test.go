@@ -23,11 +23,32 @@ func ExampleNewScratch() {
 	fmt.Println("run: ", filepath.ToSlash(p.DirRun))
 	fmt.Println("lift:", filepath.ToSlash(p.DirLift))
 	fmt.Println("fuzz:", filepath.ToSlash(p.DirFuzz))
+	fmt.Println("orcl:", filepath.ToSlash(p.DirOracle))
+	fmt.Println("conf:", filepath.ToSlash(p.DirConfirm))
+	fmt.Println("xfrm:", filepath.ToSlash(p.DirTransform))
 
 	// Output:
 	// run:  scratch/run
 	// lift: scratch/lift
 	// fuzz: scratch/fuzz
+	// orcl: scratch/oracle
+	// conf: scratch/confirm
+	// xfrm: scratch/transform
+}
+
+// ExampleScratch_Buffers is a runnable example for Scratch.Buffers.
+func ExampleScratch_Buffers() {
+	p := pathset.NewScratch("scratch")
+
+	fmt.Println(filepath.ToSlash(p.Buffers(1)[0].DirFuzz))
+	for _, b := range p.Buffers(2) {
+		fmt.Println(filepath.ToSlash(b.DirFuzz), filepath.ToSlash(b.DirRun))
+	}
+
+	// Output:
+	// scratch/fuzz
+	// scratch/buffer/0/fuzz scratch/buffer/0/run
+	// scratch/buffer/1/fuzz scratch/buffer/1/run
 }
 
 // TestScratch_Prepare tests Scratch.Prepare.
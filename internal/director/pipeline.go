@@ -0,0 +1,300 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/c4-project/c4t/internal/director/pathset"
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/c4-project/c4t/internal/subject/corpus/builder"
+)
+
+// buffer is one of an instance's scratch buffers, along with the stages that work inside it.
+//
+// Each cycle is prepared (perturbed, fuzzed, and lifted) on the local machine, and then run on the target machine and
+// analysed.  An instance with a lookahead of n has n+1 buffers, so that it can prepare up to n cycles while the machine
+// runs an earlier one.
+type buffer struct {
+	// scratch is the scratch pathset for this buffer.
+	scratch *pathset.Scratch
+	// rec holds back observations from the preparation stages until this buffer's cycle becomes the current cycle.
+	rec *recorder
+	// prep contains the stages that prepare a plan.
+	prep []plan.Runner
+	// run contains the stages that run a prepared plan and analyse the results.
+	run []plan.Runner
+	// gen is the generation of the machine's health when run was made; see health.gen.
+	gen uint64
+}
+
+// close closes all of the stages in this buffer.
+func (b *buffer) close() error {
+	perr := closeStages(b.prep)
+	rerr := closeStages(b.run)
+	return errhelp.FirstError(perr, rerr)
+}
+
+// closeStages closes each of ss, returning the last error.
+func closeStages(ss []plan.Runner) error {
+	var err error
+	for _, s := range ss {
+		if cerr := s.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// stageMaker makes the stages that work inside an instance's buffers, and probes the health of its machine.
+//
+// An Instance is its own stageMaker, unless told otherwise (as in tests).
+type stageMaker interface {
+	// makePrepStages makes the stages that prepare a plan in buffer b.
+	makePrepStages(b *buffer) ([]plan.Runner, error)
+	// makeRunStages makes the stages that run a plan prepared in buffer b on the machine, and analyse the results.
+	makeRunStages(b *buffer) ([]plan.Runner, error)
+	// probe checks that the machine is reachable.
+	probe() error
+}
+
+// stageMaker gets the stage maker for this instance.
+func (i *Instance) stageMaker() stageMaker {
+	if i.stages != nil {
+		return i.stages
+	}
+	return i
+}
+
+// cleanUp removes the scratch directories of this buffer.
+func (b *buffer) cleanUp() error {
+	return iohelp.Rmdirs(b.scratch.Dirs()...)
+}
+
+// makeBuffers makes and prepares this instance's buffers.
+func (i *Instance) makeBuffers() ([]*buffer, error) {
+	ss := i.Machine.Pathset.Scratch.Buffers(i.Machine.Quantities.Lookahead + 1)
+	bs := make([]*buffer, len(ss))
+	for j, s := range ss {
+		if err := s.Prepare(); err != nil {
+			return nil, err
+		}
+		bs[j] = &buffer{scratch: s, rec: &recorder{obs: i.Observers}}
+		if err := i.makeStages(bs[j]); err != nil {
+			return nil, err
+		}
+	}
+	return bs, nil
+}
+
+// recorder is a perturber (and so also builder) observer that forwards observations to an instance's observers.
+//
+// While its buffer is preparing a cycle ahead of the current cycle, the recorder holds back observations, as the
+// observers would otherwise attribute them to the current cycle.
+type recorder struct {
+	// obs contains the observers to which the recorder forwards.
+	obs []InstanceObserver
+
+	// mu guards the fields below.
+	mu sync.Mutex
+	// held is false if the recorder is forwarding observations directly.
+	held bool
+	// pending contains any held-back observations.
+	pending []func(InstanceObserver)
+}
+
+// OnBuild records or forwards a builder message.
+func (r *recorder) OnBuild(m builder.Message) {
+	r.observe(func(o InstanceObserver) { o.OnBuild(m) })
+}
+
+// OnCompilerConfig records or forwards a compiler configuration message.
+func (r *recorder) OnCompilerConfig(m compiler.Message) {
+	r.observe(func(o InstanceObserver) { o.OnCompilerConfig(m) })
+}
+
+// OnPerturb records or forwards a perturber message.
+func (r *recorder) OnPerturb(m perturber.Message) {
+	r.observe(func(o InstanceObserver) { o.OnPerturb(m) })
+}
+
+func (r *recorder) observe(f func(InstanceObserver)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.held {
+		r.pending = append(r.pending, f)
+		return
+	}
+	for _, o := range r.obs {
+		f(o)
+	}
+}
+
+// hold makes the recorder hold back observations until release.
+func (r *recorder) hold() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.held = true
+}
+
+// discard throws away any held-back observations, then stops holding them back.
+func (r *recorder) discard() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = nil
+	r.held = false
+}
+
+// release forwards any held-back observations, then stops holding them back.
+func (r *recorder) release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, f := range r.pending {
+		for _, o := range r.obs {
+			f(o)
+		}
+	}
+	r.pending = nil
+	r.held = false
+}
+
+// halted gets whether the instance is backing off or parked, and so shouldn't advance its pipeline.
+func (i *Instance) halted() bool {
+	return i.timeoutCh != nil || i.resumeCh != nil || i.probeCh != nil || i.probeResCh != nil
+}
+
+// advance moves this instance's pipeline along, if it isn't halted.
+//
+// Unless the instance is draining, it starts preparing a new cycle if there is a free buffer and nothing else
+// preparing.  It then announces the oldest cycle if it hasn't been announced yet, and starts running that cycle if it
+// has been prepared and nothing else is running.
+func (i *Instance) advance(ctx context.Context) {
+	if i.halted() {
+		return
+	}
+	if ch := i.Machine.shared.health.parked(); ch != nil {
+		// Another instance has parked the machine.
+		i.park(ch)
+		return
+	}
+	if !i.draining && i.limits.flaggedReached() {
+		i.startDrain(StopFlagged)
+	}
+	if !i.draining && i.prepCh == nil && len(i.free) != 0 {
+		if i.limits.claimCycle() {
+			i.startPrep(ctx)
+		} else {
+			i.startDrain(StopCycles)
+		}
+	}
+	if len(i.cycles) == 0 {
+		return
+	}
+	c := i.cycles[0]
+	if !c.started {
+		i.announce(c)
+	}
+	if i.cycleCh != nil || !c.prepared {
+		return
+	}
+	if c.err != nil {
+		i.handleCycleEnd(ctx, cycleResult{cycle: c.cycle, err: c.err})
+		return
+	}
+	i.startRun(ctx, c)
+}
+
+// startPrep starts preparing a new cycle in the first free buffer.
+func (i *Instance) startPrep(ctx context.Context) {
+	b := i.free[0]
+	i.free = i.free[1:]
+	b.rec.hold()
+
+	c := &cycleInstance{p: i.plan(), buf: b, start: time.Now()}
+	i.cycles = append(i.cycles, c)
+
+	// The stages may change under us if the instance parks, so we capture them now.
+	stages := b.prep
+	ch := make(chan *cycleInstance)
+	go func() {
+		c.err = c.run(ctx, stages)
+		select {
+		case <-ctx.Done():
+		case ch <- c:
+		}
+		close(ch)
+	}()
+
+	i.prepCh = ch
+}
+
+// handlePrepEnd handles the end of the preparation of cycle c.
+func (i *Instance) handlePrepEnd(ctx context.Context, c *cycleInstance) {
+	i.prepCh = nil
+	c.prepared = true
+	if c.dropped {
+		i.discard(c)
+	}
+	i.advance(ctx)
+}
+
+// announce makes c, the oldest cycle in the pipeline, the current cycle.
+func (i *Instance) announce(c *cycleInstance) {
+	c.started = true
+	c.cycle = Cycle{
+		Instance:  i.Index,
+		MachineID: i.Machine.ID,
+		Iter:      i.Machine.shared.nextCycle(),
+		Start:     c.start,
+	}
+	OnCycle(CycleStartMessage(c.cycle), i.Observers...)
+	c.buf.rec.release()
+}
+
+// startRun starts running the prepared cycle c.
+func (i *Instance) startRun(ctx context.Context, c *cycleInstance) {
+	if err := i.refreshStages(c.buf); err != nil {
+		i.handleCycleEnd(ctx, cycleResult{cycle: c.cycle, err: err})
+		return
+	}
+	stages := c.buf.run
+	ch := make(chan cycleResult)
+	go func() {
+		err := c.run(ctx, stages)
+		select {
+		case <-ctx.Done():
+		case ch <- cycleResult{cycle: c.cycle, err: err}:
+		}
+		close(ch)
+	}()
+
+	i.cycleCh = ch
+}
+
+// retire removes the oldest cycle from the pipeline, freeing its buffer.
+func (i *Instance) retire() {
+	c := i.cycles[0]
+	i.cycles = i.cycles[1:]
+	i.free = append(i.free, c.buf)
+}
+
+// abandon waits for any preparing or running cycles to notice that the instance's context has closed.
+func (i *Instance) abandon() {
+	if i.prepCh != nil {
+		for range i.prepCh {
+		}
+	}
+	if i.cycleCh != nil {
+		for range i.cycleCh {
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/mutation"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+)
+
+// TestInstance_lookahead tests that an instance prepares the next cycle while running the current one, runs cycles in
+// the order it prepared them, and holds back each cycle's preparation observations until that cycle starts.
+func TestInstance_lookahead(t *testing.T) {
+	t.Parallel()
+
+	const ncycles = 4
+
+	var (
+		obs eventObserver
+		mu  sync.Mutex
+		// nprep counts the cycles that have started preparing.
+		nprep int64
+		// prepping receives the number of each cycle as it starts preparing.
+		prepping = make(chan int64, ncycles+1)
+	)
+	sm := fakeStages{
+		prep: func(_ context.Context, b *buffer, p *plan.Plan) error {
+			mu.Lock()
+			nprep++
+			p.Metadata.Seed = nprep
+			mu.Unlock()
+
+			prepping <- p.Metadata.Seed
+			b.rec.OnPerturb(perturber.Message{})
+			return nil
+		},
+		run: func(ctx context.Context, _ *buffer, p *plan.Plan) error {
+			// We don't let a cycle finish until the next cycle has started preparing, which can only happen with
+			// lookahead.  The last cycle has nothing after it, as the instance runs out of cycles.
+			if p.Metadata.Seed < ncycles {
+				for n := range prepping {
+					if n == p.Metadata.Seed+1 {
+						break
+					}
+				}
+			}
+			obs.record(fmt.Sprint("run ", p.Metadata.Seed))
+			return nil
+		},
+	}
+	qs := quantity.MachineSet{Lookahead: 1, Limits: quantity.LimitSet{Cycles: ncycles}}
+	runTestInstance(t, newTestInstance(t, qs, &sm, &obs))
+
+	var want []string
+	for j := 1; j <= ncycles; j++ {
+		want = append(want, "start", "perturb", fmt.Sprint("run ", j), "finish")
+	}
+	assert.Equal(t, want, obs.filter("start", "perturb", "finish", "run 1", "run 2", "run 3", "run 4"),
+		"wrong order of events")
+}
+
+// cancelObserver is an event observer that cancels a context, and then waits for the instance's stages to notice, the
+// second time it sees a mutant change.
+//
+// As this happens inside the instance's main loop, the instance can only notice the cancellation once its preparing and
+// running cycles have already given up.
+type cancelObserver struct {
+	eventObserver
+
+	cancel context.CancelFunc
+	// stopped is done once the instance's stages have noticed the cancellation.
+	stopped *sync.WaitGroup
+	// nmutants counts the mutant changes so far.
+	nmutants int
+}
+
+func (o *cancelObserver) OnInstance(m InstanceMessage) {
+	o.eventObserver.OnInstance(m)
+	if m.Kind != KindInstanceMutant {
+		return
+	}
+	if o.nmutants++; o.nmutants != 2 {
+		return
+	}
+	o.cancel()
+	o.stopped.Wait()
+	// Give the cycle goroutines time to close their channels.
+	time.Sleep(10 * time.Millisecond)
+}
+
+// TestInstance_cancel tests that an instance cancelled while it is both preparing and running cycles stops cleanly,
+// without mistaking the abandoned cycles for finished ones.
+//
+// Which of the closed context and the abandoned cycles the instance notices first is random, so we repeat the test.
+func TestInstance_cancel(t *testing.T) {
+	t.Parallel()
+
+	for j := 0; j < 20; j++ {
+		var (
+			// inflight receives a value as each of the running cycle and the cycle behind it starts.
+			inflight = make(chan struct{}, 2)
+			stopped  sync.WaitGroup
+			mu       sync.Mutex
+			nprep    int64
+		)
+		stopped.Add(2)
+		// block waits for the context to close, then tells the observer that the stage has stopped.
+		block := func(ctx context.Context) error {
+			inflight <- struct{}{}
+			<-ctx.Done()
+			stopped.Done()
+			return ctx.Err()
+		}
+		sm := fakeStages{
+			prep: func(ctx context.Context, _ *buffer, p *plan.Plan) error {
+				mu.Lock()
+				nprep++
+				p.Metadata.Seed = nprep
+				mu.Unlock()
+
+				if p.Metadata.Seed != 2 {
+					return nil
+				}
+				return block(ctx)
+			},
+			run: func(ctx context.Context, _ *buffer, _ *plan.Plan) error {
+				return block(ctx)
+			},
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		qs := quantity.MachineSet{Lookahead: 1, Limits: quantity.LimitSet{Cycles: 10}}
+		obs := cancelObserver{cancel: cancel, stopped: &stopped}
+		i := newTestInstance(t, qs, &sm, &obs)
+
+		mutants := make(chan mutation.Mutant, 1)
+		i.mutantCh = mutants
+		mutants <- mutation.Mutant{}
+		go func() {
+			<-inflight
+			<-inflight
+			mutants <- mutation.Mutant{}
+		}()
+		require.ErrorIs(t, i.mainLoop(ctx), context.Canceled, "instance should stop with the context")
+		require.Empty(t, obs.filter("finish"), "no cycle should finish")
+		cancel()
+	}
+}
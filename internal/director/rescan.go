@@ -0,0 +1,294 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/stage/planner"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+)
+
+// rescan is the result of a rescan of the director's input paths.
+type rescan struct {
+	// added contains the new subjects.
+	added corpus.Corpus
+	// rejected contains the paths of new input files that couldn't be probed, and of the output directories of any
+	// generators that failed.
+	rejected []string
+}
+
+// rescanner periodically rescans the director's input paths for new litmus tests, probing them and sending the
+// resulting subjects to each instance.
+type rescanner struct {
+	// inputs contains the input paths, as given to the director.
+	inputs []string
+	// prober probes new input files.
+	prober planner.SubjectProber
+	// interval is the interval between rescans.
+	interval time.Duration
+	// known contains every input file that we have probed successfully, whether or not it made it into the corpus.
+	known stringhelp.Set
+	// failed maps each input file that failed to probe to the state of the file at the time; we retry the file once
+	// its state changes.
+	failed map[string]fileStamp
+	// names contains the names of every subject in the corpus so far.
+	names stringhelp.Set
+	// hashes contains the canonical hashes of every subject in the corpus so far, so that we can skip duplicates.
+	hashes stringhelp.Set
+	// mu guards sinks.
+	mu sync.Mutex
+	// sinks contains one sink for each instance that hasn't yet closed.
+	sinks []*rescanSink
+	// gens, if non-nil, contains corpus generators to rerun into fresh directories under genRoot before each rescan.
+	gens *generators
+	// genRoot is the directory under which generators write their tests.
+	genRoot string
+}
+
+// newRescanner makes a rescanner over inputs, knowing about the files in files and the subjects in c.
+func newRescanner(inputs, files []string, c corpus.Corpus, prober planner.SubjectProber, interval time.Duration) *rescanner {
+	r := rescanner{
+		inputs:   inputs,
+		prober:   prober,
+		interval: interval,
+		known:    stringhelp.NewSet(files...),
+		failed:   map[string]fileStamp{},
+		names:    stringhelp.NewSet(c.Names()...),
+		hashes:   stringhelp.NewSet(),
+	}
+	for _, s := range c {
+		if h := subjectHash(s); h != "" {
+			r.hashes.Add(h)
+		}
+	}
+	return &r
+}
+
+// subjectHash gets the canonical hash of s's source, computing it if needed; it returns "" if there is no such hash.
+func subjectHash(s subject.Subject) string {
+	if s.Source.Hash != "" {
+		return s.Source.Hash
+	}
+	h, _ := litmus.CanonicalHashOfFile(s.Source.Filepath())
+	return h
+}
+
+// rescanSink is the channel on which the rescanner sends rescans to one instance.
+type rescanSink struct {
+	// ch receives rescans.
+	ch chan rescan
+	// done closes when the instance closes, and so stops receiving rescans.
+	done chan struct{}
+}
+
+// sink makes a new sink for an instance, returning the channel on which the rescanner will send rescans and a channel
+// that the instance must close when it closes.
+func (r *rescanner) sink() (<-chan rescan, chan<- struct{}) {
+	s := &rescanSink{ch: make(chan rescan), done: make(chan struct{})}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sinks = append(r.sinks, s)
+	return s.ch, s.done
+}
+
+// liveSinks gets the sinks of instances that haven't yet closed, forgetting the others.
+func (r *rescanner) liveSinks() []*rescanSink {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	live := r.sinks[:0]
+	for _, s := range r.sinks {
+		select {
+		case <-s.done:
+		default:
+			live = append(live, s)
+		}
+	}
+	r.sinks = live
+	return append([]*rescanSink(nil), live...)
+}
+
+// run rescans at every interval until ctx closes.
+func (r *rescanner) run(ctx context.Context) error {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if err := r.rescanAndSend(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (r *rescanner) rescanAndSend(ctx context.Context) error {
+	rs, err := r.rescan(ctx)
+	if err != nil || (len(rs.added) == 0 && len(rs.rejected) == 0) {
+		return err
+	}
+	// An instance may close at any time (for instance, when it reaches its machine's campaign limits); we mustn't
+	// wait on it if so, or the other instances would stop getting rescans.
+	for _, s := range r.liveSinks() {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.done:
+		case s.ch <- rs:
+		}
+	}
+	return nil
+}
+
+// rescan reruns any generators, then finds and probes any input files that have appeared since the last scan.
+//
+// Failing to probe a file doesn't stop the rescan, as an input file may be malformed or not yet fully written; we
+// report the file as rejected, and try it again once its size or modification time changes.
+func (r *rescanner) rescan(ctx context.Context) (rescan, error) {
+	rs := rescan{added: corpus.Corpus{}}
+	gdirs, fdirs, err := r.regenerate(ctx, &rs)
+	if err != nil || ctx.Err() != nil {
+		return rs, err
+	}
+	files, err := planner.ExpandLitmusInputs(append(append([]string{}, r.inputs...), gdirs...))
+	if err != nil {
+		return rs, err
+	}
+	if err := r.probeAll(ctx, files, &rs); err != nil || ctx.Err() != nil {
+		return rs, err
+	}
+	return rs, r.prune(append(gdirs, fdirs...), rs.added)
+}
+
+// probeAll probes each of files that we haven't yet seen (or that has changed since it was rejected), adding the
+// results to rs.
+func (r *rescanner) probeAll(ctx context.Context, files []string, rs *rescan) error {
+	for _, f := range files {
+		if _, ok := r.known[f]; ok {
+			continue
+		}
+		// We take the stamp before probing, so that any writes that race with the probe show up as a change.
+		st, ok := r.changed(f)
+		if !ok {
+			continue
+		}
+		s, err := r.prober.ProbeSubject(ctx, f)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			r.failed[f] = st
+			rs.rejected = append(rs.rejected, f)
+			continue
+		}
+		delete(r.failed, f)
+		r.known.Add(f)
+		r.add(f, s, rs)
+	}
+	return nil
+}
+
+// prune removes those generator directories in gdirs from which nothing was added to the corpus, forgetting about
+// their files.
+func (r *rescanner) prune(gdirs []string, added corpus.Corpus) error {
+	removed, err := pruneGenerated(gdirs, added)
+	for _, d := range removed {
+		for f := range r.known {
+			if inDir(d, f) {
+				delete(r.known, f)
+			}
+		}
+		for f := range r.failed {
+			if inDir(d, f) {
+				delete(r.failed, f)
+			}
+		}
+	}
+	return err
+}
+
+// fileStamp records the size and modification time of an input file.
+type fileStamp struct {
+	size  int64
+	mtime time.Time
+}
+
+// changed gets the current stamp of input file f, and whether we should (re)try probing it: that is, whether it
+// hasn't failed before, or has changed since it last failed.  If we can't stat f, we try probing it anyway, so that
+// the prober can report the problem.
+func (r *rescanner) changed(f string) (fileStamp, bool) {
+	var st fileStamp
+	if fi, err := os.Stat(f); err == nil {
+		st = fileStamp{size: fi.Size(), mtime: fi.ModTime()}
+	}
+	old, failed := r.failed[f]
+	return st, !failed || old != st
+}
+
+// regenerate reruns the generators, returning the directories of the generators that succeeded and of those that
+// failed.
+//
+// As with probing, a failing generator doesn't stop the rescan; we report its directory as rejected, and try it again
+// next time.  We don't scan the directory of a failing generator, and so prune removes it.
+func (r *rescanner) regenerate(ctx context.Context, rs *rescan) (gdirs, fdirs []string, err error) {
+	if r.gens == nil {
+		return nil, nil, nil
+	}
+	for _, c := range r.gens.configs {
+		dir, err := r.gens.generateOne(ctx, c, r.genRoot)
+		switch {
+		case err == nil:
+			gdirs = append(gdirs, dir)
+		case dir == "":
+			return nil, nil, err
+		default:
+			fdirs = append(fdirs, dir)
+			if ctx.Err() == nil {
+				rs.rejected = append(rs.rejected, dir)
+			}
+		}
+	}
+	return gdirs, fdirs, nil
+}
+
+// add adds s, probed from file f, to rs, unless it duplicates a subject already in the corpus.
+func (r *rescanner) add(f string, s *subject.Named, rs *rescan) {
+	h := subjectHash(s.Subject)
+	if _, ok := r.hashes[h]; ok && h != "" {
+		return
+	}
+	// We can't have two different subjects with the same name, so we reject the latecomer.
+	if _, ok := r.names[s.Name]; ok {
+		rs.rejected = append(rs.rejected, f)
+		return
+	}
+	if h != "" {
+		r.hashes.Add(h)
+	}
+	r.names.Add(s.Name)
+	rs.added[s.Name] = s.Subject
+}
+
+// handleRescan merges the subjects from rs into this instance's initial plan, and announces the rescan.
+func (i *Instance) handleRescan(rs rescan) {
+	if len(rs.added) != 0 {
+		// We copy the corpus, as cycles in flight and other instances may share the original.
+		c := i.Machine.InitialPlan.Corpus.Copy()
+		for n, s := range rs.added {
+			c[n] = s
+		}
+		i.Machine.InitialPlan.Corpus = c
+	}
+	OnInstance(InstanceRescanMessage(rs.added.Names(), rs.rejected), i.Observers...)
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package director
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/generator"
+	"github.com/c4-project/c4t/internal/model/service/mocks"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+)
+
+// fakeProber is a subject prober that rejects empty files, and otherwise names each subject from its header line, as the
+// real prober does.
+type fakeProber struct {
+	// probed counts how many times each file has been probed.
+	probed map[string]int
+}
+
+func (p *fakeProber) ProbeSubject(_ context.Context, f string) (*subject.Named, error) {
+	p.probed[f]++
+	bs, err := os.ReadFile(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) == 0 {
+		return nil, errors.New("empty litmus test")
+	}
+	header := strings.Fields(strings.SplitN(string(bs), "\n", 2)[0])
+	if len(header) < 2 {
+		return nil, errors.New("malformed litmus header")
+	}
+	s := subject.NewOrPanic(litmus.NewOrPanic(f))
+	return &subject.Named{Name: header[1], Subject: *s}, nil
+}
+
+// TestRescanner_rescan_retry tests that the rescanner retries a rejected file only once it changes.
+func TestRescanner_rescan_retry(t *testing.T) {
+	dir := t.TempDir()
+	f := filepath.Join(dir, "foo.litmus")
+	require.NoError(t, os.WriteFile(f, nil, 0644), "writing half-written test")
+
+	p := fakeProber{probed: map[string]int{}}
+	r := newRescanner([]string{dir}, nil, corpus.Corpus{}, &p, time.Minute)
+
+	rs, err := r.rescan(context.Background())
+	require.NoError(t, err, "first rescan")
+	assert.Equal(t, []string{f}, rs.rejected, "first rescan should reject the half-written test")
+	assert.Empty(t, rs.added, "first rescan shouldn't add anything")
+
+	rs, err = r.rescan(context.Background())
+	require.NoError(t, err, "second rescan")
+	assert.Empty(t, rs.rejected, "second rescan shouldn't reject the unchanged test again")
+	assert.Equal(t, 1, p.probed[f], "second rescan shouldn't probe the unchanged test")
+
+	require.NoError(t, os.WriteFile(f, []byte("C foo\n"), 0644), "finishing test")
+	rs, err = r.rescan(context.Background())
+	require.NoError(t, err, "third rescan")
+	assert.Empty(t, rs.rejected, "third rescan shouldn't reject the finished test")
+	assert.Contains(t, rs.added, "foo", "third rescan should add the finished test")
+
+	rs, err = r.rescan(context.Background())
+	require.NoError(t, err, "fourth rescan")
+	assert.Empty(t, rs.added, "fourth rescan shouldn't add the test again")
+	assert.Equal(t, 2, p.probed[f], "fourth rescan shouldn't probe the added test")
+}
+
+// TestRescanner_rescanAndSend_closed tests that rescans still reach open instances after another instance has closed.
+func TestRescanner_rescanAndSend_closed(t *testing.T) {
+	dir := t.TempDir()
+	p := fakeProber{probed: map[string]int{}}
+	r := newRescanner([]string{dir}, nil, corpus.Corpus{}, &p, time.Minute)
+
+	closedCh, closedDone := r.sink()
+	openCh, _ := r.sink()
+	close(closedDone)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The tests need different bodies, as the rescanner skips tests that differ only in name.
+	for _, n := range []string{"foo", "bar"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, n+".litmus"), []byte("C "+n+"\n{ "+n+" = 1; }\n"), 0644), "writing test")
+
+		errCh := make(chan error)
+		go func() { errCh <- r.rescanAndSend(ctx) }()
+		select {
+		case rs := <-openCh:
+			assert.Contains(t, rs.added, n, "open instance should receive the new test")
+		case <-ctx.Done():
+			t.Fatal("rescan didn't reach the open instance")
+		}
+		require.NoError(t, <-errCh, "rescanning")
+	}
+
+	select {
+	case <-closedCh:
+		t.Error("closed instance shouldn't receive rescans")
+	default:
+	}
+}
+
+// TestRescanner_rescan_generated tests that the rescanner gives generated tests names unique to each run, and removes
+// the directories of runs from which nothing made it into the corpus.
+func TestRescanner_rescan_generated(t *testing.T) {
+	root := t.TempDir()
+
+	// The generator always names its test the same way, but only changes its body on the second run.
+	bodies := []string{"{ x = 1; }", "{ x = 2; }", "{ x = 1; }"}
+	r := new(mocks.Runner)
+	for _, b := range bodies {
+		b := b
+		r.On("Run", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			dir := args.Get(1).(service.RunInfo).Args[0]
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "test_0.litmus"), []byte("C test_0\n"+b+"\n"), 0644))
+		}).Return(nil).Once()
+	}
+
+	p := fakeProber{probed: map[string]int{}}
+	rs := newRescanner(nil, nil, corpus.Corpus{}, generatedProber{SubjectProber: &p, root: root}, time.Minute)
+	rs.gens = &generators{
+		configs: []generator.Config{{Name: "gen", Run: service.RunInfo{Cmd: "gen", Args: []string{"${outdir}"}}}},
+		runner:  r,
+	}
+	rs.genRoot = root
+
+	var names []string
+	for i := range bodies {
+		res, err := rs.rescan(context.Background())
+		require.NoError(t, err, "rescan %d", i)
+		assert.Empty(t, res.rejected, "rescan %d shouldn't reject anything", i)
+		names = append(names, res.added.Names()...)
+	}
+	r.AssertExpectations(t)
+
+	require.Len(t, names, 2, "the last run duplicates the first, and so should add nothing")
+	assert.NotEqual(t, names[0], names[1], "runs should add tests with different names")
+	for _, n := range names {
+		assert.True(t, strings.HasPrefix(n, "gen_"), "name %s should start with the generator's directory", n)
+	}
+
+	dirs, err := os.ReadDir(root)
+	require.NoError(t, err, "reading generator root")
+	assert.Len(t, dirs, 2, "the directory of the last run should have been removed")
+}
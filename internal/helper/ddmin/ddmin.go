@@ -0,0 +1,70 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package ddmin contains a simple implementation of delta-debugging minimisation.
+package ddmin
+
+import "context"
+
+// Tester is the type of functions that check whether a candidate subset of items still has some property.
+// The candidate is given as an ascending list of indices into the original item list.
+type Tester func(ctx context.Context, keep []int) (bool, error)
+
+// Minimise finds a subset of the n items, indexed 0 to n-1, for which test holds, and from which no single item can be
+// removed without test failing.
+//
+// Minimise assumes, but doesn't check, that test holds for the full set of items.  It tries removing progressively
+// finer chunks of the current subset, keeping any removal for which test still holds.  Errors from test are fatal.
+func Minimise(ctx context.Context, n int, test Tester) ([]int, error) {
+	keep := make([]int, n)
+	for i := range keep {
+		keep[i] = i
+	}
+
+	for nchunks := 2; 0 < len(keep); {
+		if len(keep) < nchunks {
+			nchunks = len(keep)
+		}
+		cand, err := removeChunk(ctx, keep, nchunks, test)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case cand != nil:
+			keep = cand
+			if 2 < nchunks {
+				nchunks--
+			}
+		case nchunks < len(keep):
+			nchunks *= 2
+		default:
+			return keep, nil
+		}
+	}
+	return keep, nil
+}
+
+// removeChunk tries removing each of nchunks chunks from keep in turn, returning the first candidate passing test.
+// It returns nil if no removal passes.
+func removeChunk(ctx context.Context, keep []int, nchunks int, test Tester) ([]int, error) {
+	size := (len(keep) + nchunks - 1) / nchunks
+	for from := 0; from < len(keep); from += size {
+		to := from + size
+		if len(keep) < to {
+			to = len(keep)
+		}
+		cand := make([]int, 0, len(keep)-(to-from))
+		cand = append(append(cand, keep[:from]...), keep[to:]...)
+
+		ok, err := test(ctx, cand)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return cand, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package ddmin_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/ddmin"
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/stretchr/testify/assert"
+)
+
+// ExampleMinimise is a runnable example for Minimise.
+func ExampleMinimise() {
+	// We want the smallest subset of 10 items that contains both item 3 and item 7.
+	keep, err := ddmin.Minimise(context.Background(), 10, func(_ context.Context, keep []int) (bool, error) {
+		return contains(keep, 3) && contains(keep, 7), nil
+	})
+	fmt.Println(keep, err)
+
+	// Output:
+	// [3 7] <nil>
+}
+
+// TestMinimise tests Minimise on various properties.
+func TestMinimise(t *testing.T) {
+	t.Parallel()
+
+	oops := errors.New("oops")
+
+	cases := map[string]struct {
+		n    int
+		test ddmin.Tester
+		want []int
+		err  error
+	}{
+		"empty": {
+			n:    0,
+			test: func(context.Context, []int) (bool, error) { return true, nil },
+			want: []int{},
+		},
+		"always": {
+			n:    5,
+			test: func(context.Context, []int) (bool, error) { return true, nil },
+			want: []int{},
+		},
+		"never": {
+			n:    5,
+			test: func(context.Context, []int) (bool, error) { return false, nil },
+			want: []int{0, 1, 2, 3, 4},
+		},
+		"one": {
+			n:    9,
+			test: func(_ context.Context, keep []int) (bool, error) { return contains(keep, 8), nil },
+			want: []int{8},
+		},
+		"either": {
+			// Either item 1 or item 4 will do; we should end up with exactly one of them.
+			n:    6,
+			test: func(_ context.Context, keep []int) (bool, error) { return contains(keep, 1) || contains(keep, 4), nil },
+			want: []int{4},
+		},
+		"error": {
+			n:    4,
+			test: func(context.Context, []int) (bool, error) { return false, oops },
+			err:  oops,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ddmin.Minimise(context.Background(), c.n, c.test)
+			if !testhelp.ExpectErrorIs(t, err, c.err, "minimising") || err != nil {
+				return
+			}
+			assert.Equal(t, c.want, got, "wrong minimal subset")
+		})
+	}
+}
+
+func contains(keep []int, i int) bool {
+	for _, k := range keep {
+		if k == i {
+			return true
+		}
+	}
+	return false
+}
@@ -19,6 +19,8 @@ const (
 	ArchFamilyAArch64 = "aarch64"
 	// ArchFamilyPPC is the tag representing the PowerPC architecture family.
 	ArchFamilyPPC = "ppc"
+	// ArchFamilyRISCV is the tag representing the RISC-V architecture family.
+	ArchFamilyRISCV = "riscv"
 
 	// ArchVariantArm7 is the tag representing the arm7(-a) Arm variant.
 	ArchVariantArm7 = "7"
@@ -44,6 +46,17 @@ const (
 	// ArchSubVariantPPCPOWER9 is the tag representing the POWER9 PPC sub-variant.
 	ArchSubVariantPPCPOWER9 = "power9"
 
+	// ArchVariantRISCV32 is the tag representing the 32-bit RISC-V variant.
+	ArchVariantRISCV32 = "32"
+	// ArchVariantRISCV64 is the tag representing the 64-bit RISC-V variant.
+	ArchVariantRISCV64 = "64"
+
+	// ArchSubVariantRISCVGC is the tag representing RISC-V with the general-purpose (IMAFD) and compressed extensions.
+	// This is the baseline for most Linux-capable RISC-V boards.
+	ArchSubVariantRISCVGC = "gc"
+	// ArchSubVariantRISCVGCV is the tag representing RISC-V with the general-purpose, compressed, and vector extensions.
+	ArchSubVariantRISCVGCV = "gcv"
+
 	// ArchVariantX8664 is the tag representing the 64-bit x86 variant.
 	ArchVariantX8664 = "64"
 
@@ -95,6 +108,21 @@ var (
 	// ArchPPCPOWER9 is the architecture ID for POWER9.
 	ArchPPCPOWER9 = ID{repr: ArchPPC64LE.repr + SepTag + ArchSubVariantPPCPOWER9}
 
+	// ArchRISCV is the architecture ID for RISC-V (generic).
+	ArchRISCV = ID{repr: ArchFamilyRISCV}
+	// ArchRISCV32 is the architecture ID for 32-bit RISC-V.
+	ArchRISCV32 = ID{repr: ArchFamilyRISCV + SepTag + ArchVariantRISCV32}
+	// ArchRISCV64 is the architecture ID for 64-bit RISC-V.
+	ArchRISCV64 = ID{repr: ArchFamilyRISCV + SepTag + ArchVariantRISCV64}
+	// ArchRISCV64GC is the architecture ID for 64-bit RISC-V with the G and C extensions (RV64GC).
+	ArchRISCV64GC = ID{repr: ArchRISCV64.repr + SepTag + ArchSubVariantRISCVGC}
+	// ArchRISCV64GCV is the architecture ID for 64-bit RISC-V with the G, C, and V extensions (RV64GCV).
+	ArchRISCV64GCV = ID{repr: ArchRISCV64.repr + SepTag + ArchSubVariantRISCVGCV}
+
 	// CStyleGCC is the compiler style ID for GCC.
 	CStyleGCC = ID{repr: "gcc"}
+	// CStyleClang is the compiler style ID for Clang.
+	CStyleClang = ID{repr: "clang"}
+	// CStyleTemplated is the compiler style ID for compilers described by argument templates in their configuration.
+	CStyleTemplated = ID{repr: "templated"}
 )
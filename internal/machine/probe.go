@@ -46,9 +46,21 @@ func (localProber) NCores() (int, error) {
 	return runtime.NumCPU(), nil
 }
 
-// Arch gets the architecture using GOARCH.
+// Arch gets the architecture using GOARCH, refining it using /proc/cpuinfo if possible.
+//
+// Without refinement, some architectures (such as RISC-V) would miss out on the compiler options that the machine
+// supports.
 func (localProber) Arch() (id.ID, error) {
-	return ArchOfGOARCH(runtime.GOARCH)
+	arch, err := ArchOfGOARCH(runtime.GOARCH)
+	if err != nil {
+		return id.ID{}, err
+	}
+	// As with remote probing, not every machine has /proc/cpuinfo.
+	cpuinfo, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return arch, nil
+	}
+	return RefineArch(arch, string(cpuinfo)), nil
 }
 
 // ErrUnsupportedGOARCH occurs when a GOARCH not supported by the prober occurs
@@ -68,6 +80,8 @@ func ArchOfGOARCH(goarch string) (id.ID, error) {
 		return id.ArchAArch64, nil
 	case "ppc64le":
 		return id.ArchPPC64LE, nil
+	case "riscv64":
+		return id.ArchRISCV64, nil
 	default:
 		return id.ID{}, fmt.Errorf("%w: %s", ErrUnsupportedGOARCH, goarch)
 	}
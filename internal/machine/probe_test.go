@@ -8,6 +8,7 @@ package machine_test
 import (
 	"testing"
 
+	"github.com/c4-project/c4t/internal/helper/testhelp"
 	"github.com/c4-project/c4t/internal/id"
 
 	"github.com/c4-project/c4t/internal/machine"
@@ -38,3 +39,31 @@ func TestProbe(t *testing.T) {
 
 	m.AssertExpectations(t)
 }
+
+// TestArchOfGOARCH tests ArchOfGOARCH on various Go architectures.
+func TestArchOfGOARCH(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		want id.ID
+		err  error
+	}{
+		"amd64":   {want: id.ArchX8664},
+		"arm64":   {want: id.ArchAArch64},
+		"ppc64le": {want: id.ArchPPC64LE},
+		"riscv64": {want: id.ArchRISCV64},
+		"s390x":   {err: machine.ErrUnsupportedGOARCH},
+	}
+
+	for goarch, c := range cases {
+		goarch, c := goarch, c
+		t.Run(goarch, func(t *testing.T) {
+			t.Parallel()
+			got, err := machine.ArchOfGOARCH(goarch)
+			if !testhelp.ExpectErrorIs(t, err, c.err, "ArchOfGOARCH") || err != nil {
+				return
+			}
+			assert.Equal(t, c.want, got, "wrong arch")
+		})
+	}
+}
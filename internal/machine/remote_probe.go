@@ -0,0 +1,215 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+)
+
+// ErrUnsupportedUname occurs when a 'uname -m' output not supported by the prober occurs.
+var ErrUnsupportedUname = errors.New("machine type not supported by prober")
+
+// CommandProber gets a prober that probes the machine on which r runs commands, using context ctx.
+//
+// This is mainly useful for probing remote machines, given a remote service runner.
+func CommandProber(ctx context.Context, r service.Runner) Prober {
+	return commandProber{ctx: ctx, runner: r}
+}
+
+// commandProber implements Prober by running POSIX commands and reading /proc.
+type commandProber struct {
+	// ctx is the context used for each command.
+	// The Prober interface predates contexts in the probing code, so we keep it here rather than threading it through.
+	ctx context.Context
+	// runner runs the probing commands.
+	runner service.Runner
+}
+
+// Hostname gets the hostname reported by 'hostname'.
+func (c commandProber) Hostname() (string, error) {
+	return c.output("hostname")
+}
+
+// NCores gets the number of cores reported by 'nproc'.
+func (c commandProber) NCores() (int, error) {
+	out, err := c.output("nproc")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(out)
+}
+
+// Arch gets the architecture reported by 'uname -m', refined by whatever we can find in /proc/cpuinfo.
+func (c commandProber) Arch() (id.ID, error) {
+	m, err := c.output("uname", "-m")
+	if err != nil {
+		return id.ID{}, err
+	}
+	arch, err := ArchOfUname(m)
+	if err != nil {
+		return id.ID{}, err
+	}
+	// Not every machine has /proc/cpuinfo, so failing to read it just means we can't refine the architecture.
+	cpuinfo, err := c.output("cat", "/proc/cpuinfo")
+	if err != nil {
+		return arch, nil
+	}
+	return RefineArch(arch, cpuinfo), nil
+}
+
+func (c commandProber) output(cmd string, args ...string) (string, error) {
+	out, err := service.RunAndCaptureStdout(c.ctx, c.runner, *service.NewRunInfo(cmd, args...))
+	return strings.TrimSpace(out), err
+}
+
+// ArchOfUname maps from the machine type m reported by 'uname -m' to a C4 architecture id.
+// It fails with ErrUnsupportedUname if the architecture isn't supported by C4.
+func ArchOfUname(m string) (id.ID, error) {
+	switch m {
+	case "i386", "i486", "i586", "i686":
+		return id.ArchX86, nil
+	case "x86_64", "amd64":
+		return id.ArchX8664, nil
+	case "armv7l":
+		return id.ArchArm7, nil
+	case "armv8l":
+		return id.ArchArm8, nil
+	case "aarch64", "arm64":
+		return id.ArchAArch64, nil
+	case "ppc64le":
+		return id.ArchPPC64LE, nil
+	case "riscv32":
+		return id.ArchRISCV32, nil
+	case "riscv64":
+		return id.ArchRISCV64, nil
+	default:
+		return id.ID{}, fmt.Errorf("%w: %s", ErrUnsupportedUname, m)
+	}
+}
+
+// RefineArch tries to narrow arch down to a more specific subvariant using the contents of /proc/cpuinfo.
+// It returns arch unchanged if cpuinfo doesn't tell us anything more.
+func RefineArch(arch id.ID, cpuinfo string) id.ID {
+	info := parseCPUInfo(cpuinfo)
+	switch arch {
+	case id.ArchX8664:
+		return refineX8664(info)
+	case id.ArchArm7, id.ArchArm8:
+		return refineArm(arch, info)
+	case id.ArchAArch64:
+		return refineAArch64(info)
+	case id.ArchPPC64LE:
+		return refinePPC64LE(info)
+	case id.ArchRISCV64:
+		return refineRISCV64(info)
+	default:
+		return arch
+	}
+}
+
+func refineX8664(info map[string]string) id.ID {
+	flags := fieldSet(info["flags"])
+	switch {
+	case flags["clflushopt"] && flags["xsavec"]:
+		return id.ArchX86Skylake
+	case flags["adx"] && flags["rdseed"]:
+		return id.ArchX86Broadwell
+	default:
+		return id.ArchX8664
+	}
+}
+
+func refineArm(arch id.ID, info map[string]string) id.ID {
+	if info["CPU part"] == "0xd08" {
+		return id.ArchArmCortexA72
+	}
+	switch info["CPU architecture"] {
+	case "7":
+		return id.ArchArm7
+	case "8":
+		return id.ArchArm8
+	default:
+		return arch
+	}
+}
+
+func refineAArch64(info map[string]string) id.ID {
+	feats, ok := info["Features"]
+	if !ok {
+		return id.ArchAArch64
+	}
+	// The 'atomics' feature is LSE, which arrived in Armv8.1.
+	if fieldSet(feats)["atomics"] {
+		return id.ArchAArch6481
+	}
+	return id.ArchAArch648
+}
+
+func refinePPC64LE(info map[string]string) id.ID {
+	cpu := strings.ToUpper(info["cpu"])
+	switch {
+	case strings.HasPrefix(cpu, "POWER9"):
+		return id.ArchPPCPOWER9
+	case strings.HasPrefix(cpu, "POWER8"):
+		return id.ArchPPCPOWER8
+	case strings.HasPrefix(cpu, "POWER7"):
+		return id.ArchPPCPOWER7
+	default:
+		return id.ArchPPC64LE
+	}
+}
+
+func refineRISCV64(info map[string]string) id.ID {
+	isa := strings.ToLower(info["isa"])
+	if !strings.HasPrefix(isa, "rv64") {
+		return id.ArchRISCV64
+	}
+	// Multi-letter extensions start after the first underscore; we only care about the single-letter ones.
+	exts := strings.SplitN(strings.TrimPrefix(isa, "rv64"), "_", 2)[0]
+	if strings.Contains(exts, "g") {
+		exts += "imafd"
+	}
+	for _, e := range "imafdc" {
+		if !strings.ContainsRune(exts, e) {
+			return id.ArchRISCV64
+		}
+	}
+	if strings.ContainsRune(exts, 'v') {
+		return id.ArchRISCV64GCV
+	}
+	return id.ArchRISCV64GC
+}
+
+// parseCPUInfo parses the first processor block of /proc/cpuinfo-style text into a key-value map.
+func parseCPUInfo(cpuinfo string) map[string]string {
+	info := map[string]string{}
+	for _, line := range strings.Split(cpuinfo, "\n") {
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k := strings.TrimSpace(kv[0])
+		if _, ok := info[k]; !ok {
+			info[k] = strings.TrimSpace(kv[1])
+		}
+	}
+	return info
+}
+
+func fieldSet(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, f := range strings.Fields(s) {
+		set[f] = true
+	}
+	return set
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package machine_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/machine"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ExampleRefineArch is a runnable example for RefineArch.
+func ExampleRefineArch() {
+	fmt.Println(machine.RefineArch(id.ArchPPC64LE, "processor\t: 0\ncpu\t\t: POWER9 (raw), altivec supported\n"))
+	fmt.Println(machine.RefineArch(id.ArchRISCV64, "processor\t: 0\nisa\t\t: rv64imafdc\n"))
+	fmt.Println(machine.RefineArch(id.ArchAArch64, "processor\t: 0\nFeatures\t: fp asimd atomics crc32\n"))
+	fmt.Println(machine.RefineArch(id.ArchX8664, ""))
+
+	// Output:
+	// ppc.64le.power9
+	// riscv.64.gc
+	// aarch64.8.1
+	// x86.64
+}
+
+// TestArchOfUname tests ArchOfUname on various machine types.
+func TestArchOfUname(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		want id.ID
+		err  error
+	}{
+		"x86_64":  {want: id.ArchX8664},
+		"i686":    {want: id.ArchX86},
+		"aarch64": {want: id.ArchAArch64},
+		"armv7l":  {want: id.ArchArm7},
+		"ppc64le": {want: id.ArchPPC64LE},
+		"riscv64": {want: id.ArchRISCV64},
+		"s390x":   {err: machine.ErrUnsupportedUname},
+	}
+
+	for m, c := range cases {
+		m, c := m, c
+		t.Run(m, func(t *testing.T) {
+			t.Parallel()
+			got, err := machine.ArchOfUname(m)
+			if !testhelp.ExpectErrorIs(t, err, c.err, "ArchOfUname") || err != nil {
+				return
+			}
+			assert.Equal(t, c.want, got, "wrong arch")
+		})
+	}
+}
+
+// TestRefineArch tests RefineArch on various cpuinfo snippets.
+func TestRefineArch(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		arch    id.ID
+		cpuinfo string
+		want    id.ID
+	}{
+		"x86-skylake":   {arch: id.ArchX8664, cpuinfo: "flags : fpu adx rdseed clflushopt xsavec", want: id.ArchX86Skylake},
+		"x86-broadwell": {arch: id.ArchX8664, cpuinfo: "flags : fpu adx rdseed", want: id.ArchX86Broadwell},
+		"x86-old":       {arch: id.ArchX8664, cpuinfo: "flags : fpu sse2", want: id.ArchX8664},
+		"aarch64-8":     {arch: id.ArchAArch64, cpuinfo: "Features : fp asimd", want: id.ArchAArch648},
+		"aarch64-none":  {arch: id.ArchAArch64, want: id.ArchAArch64},
+		"arm-a72":       {arch: id.ArchArm8, cpuinfo: "CPU architecture: 8\nCPU part : 0xd08", want: id.ArchArmCortexA72},
+		"arm-7":         {arch: id.ArchArm8, cpuinfo: "CPU architecture: 7", want: id.ArchArm7},
+		"power8":        {arch: id.ArchPPC64LE, cpuinfo: "cpu : POWER8E (raw)", want: id.ArchPPCPOWER8},
+		"riscv-gcv":     {arch: id.ArchRISCV64, cpuinfo: "isa : rv64imafdcv_zicsr", want: id.ArchRISCV64GCV},
+		"riscv-g":       {arch: id.ArchRISCV64, cpuinfo: "isa : rv64gc", want: id.ArchRISCV64GC},
+		"riscv-ima":     {arch: id.ArchRISCV64, cpuinfo: "isa : rv64imac", want: id.ArchRISCV64},
+		"first-block": {
+			arch:    id.ArchPPC64LE,
+			cpuinfo: "processor : 0\ncpu : POWER9\n\nprocessor : 1\ncpu : POWER7\n",
+			want:    id.ArchPPCPOWER9,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, c.want, machine.RefineArch(c.arch, c.cpuinfo), "wrong refined arch")
+		})
+	}
+}
+
+// TestCommandProber tests probing a machine through a scripted service runner.
+func TestCommandProber(t *testing.T) {
+	t.Parallel()
+
+	script := map[string]string{
+		"hostname":          "hoare.example.com\n",
+		"nproc":             "16\n",
+		"uname -m":          "x86_64\n",
+		"cat /proc/cpuinfo": "flags : adx rdseed\n",
+	}
+	p := machine.CommandProber(context.Background(), scriptRunner{script: script})
+
+	hname, err := p.Hostname()
+	require.NoError(t, err, "probing hostname")
+	assert.Equal(t, "hoare.example.com", hname, "wrong hostname")
+
+	var c machine.Config
+	require.NoError(t, c.Probe(p), "probing machine")
+	assert.Equal(t, 16, c.Cores, "wrong cores")
+	assert.Equal(t, id.ArchX86Broadwell, c.Arch, "wrong arch")
+
+	delete(script, "cat /proc/cpuinfo")
+	arch, err := p.Arch()
+	require.NoError(t, err, "probing arch without cpuinfo")
+	assert.Equal(t, id.ArchX8664, arch, "unreadable cpuinfo should give unrefined arch")
+}
+
+// scriptRunner is a service runner that prints canned output for each command line.
+type scriptRunner struct {
+	script map[string]string
+	w      io.Writer
+}
+
+func (s scriptRunner) WithStdout(w io.Writer) service.Runner {
+	s.w = w
+	return s
+}
+
+func (s scriptRunner) WithStderr(io.Writer) service.Runner {
+	return s
+}
+
+func (s scriptRunner) WithGrace(time.Duration) service.Runner {
+	return s
+}
+
+func (s scriptRunner) Run(_ context.Context, r service.RunInfo) error {
+	out, ok := s.script[strings.Join(r.Invocation(), " ")]
+	if !ok {
+		return errors.New("command not found")
+	}
+	if s.w == nil {
+		return nil
+	}
+	_, err := io.WriteString(s.w, out)
+	return err
+}
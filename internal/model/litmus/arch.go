@@ -33,6 +33,9 @@ var archMap = map[string]map[string]string{
 	id.ArchFamilyPPC: {
 		"": "PPC",
 	},
+	id.ArchFamilyRISCV: {
+		"": "RISCV",
+	},
 	id.ArchFamilyX86: {
 		"":                  "X86", // 32-bit
 		id.ArchVariantX8664: "X86_64",
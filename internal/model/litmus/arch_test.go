@@ -34,11 +34,15 @@ func ExampleArchToLitmus() {
 	a4, _ := litmus.ArchToLitmus(id.ArchC)
 	fmt.Println(a4)
 
+	a5, _ := litmus.ArchToLitmus(id.ArchRISCV64GC)
+	fmt.Println(a5)
+
 	// Output:
 	// AArch64
 	// PPC
 	// X86_64
 	// C
+	// RISCV
 }
 
 // ExampleArchOfLitmus gives a few testable examples of ArchOfLitmus.
@@ -55,11 +59,15 @@ func ExampleArchOfLitmus() {
 	a4, _ := litmus.ArchOfLitmus("C")
 	fmt.Println(a4)
 
+	a5, _ := litmus.ArchOfLitmus("RISCV")
+	fmt.Println(a5)
+
 	// Output:
 	// aarch64
 	// ppc
 	// x86.64
 	// c
+	// riscv
 }
 
 // TestArchToLitmus_errors tests various failing cases of ArchOfLitmus.
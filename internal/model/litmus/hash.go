@@ -0,0 +1,60 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package litmus
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+)
+
+// CanonicalHash computes a hash of the litmus test read from r that ignores whitespace and the name of the test.
+//
+// Two litmus tests with the same hash differ only in layout and name, and so are most likely duplicates.
+func CanonicalHash(r io.Reader) (string, error) {
+	h := sha256.New()
+	s := bufio.NewScanner(r)
+	s.Split(bufio.ScanWords)
+	for i := 0; s.Scan(); i++ {
+		// The test header is the architecture followed by the test name; we skip the latter.
+		if i == 1 {
+			continue
+		}
+		// Writes to hashes never fail.
+		_, _ = h.Write(s.Bytes())
+		// We drop all whitespace from the body, so that, for instance, '{x' and '{ x' hash the same;
+		// the architecture still needs separating from the body, though.
+		if i == 0 {
+			_, _ = h.Write([]byte{' '})
+		}
+	}
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CanonicalHashOfFile computes the CanonicalHash of the litmus test at file fpath.
+func CanonicalHashOfFile(fpath string) (string, error) {
+	r, err := os.Open(fpath)
+	if err != nil {
+		return "", err
+	}
+	hash, herr := CanonicalHash(r)
+	cerr := r.Close()
+	return hash, errhelp.FirstError(herr, cerr)
+}
+
+// PopulateHashFromFile sets this litmus test's hash to that from calling CanonicalHashOfFile over its defined Filepath.
+func (l *Litmus) PopulateHashFromFile() error {
+	var err error
+	l.Hash, err = CanonicalHashOfFile(l.Filepath())
+	return err
+}
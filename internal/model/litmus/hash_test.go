@@ -0,0 +1,75 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package litmus_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ExampleCanonicalHash is a runnable example for CanonicalHash.
+func ExampleCanonicalHash() {
+	h1, _ := litmus.CanonicalHash(strings.NewReader("C foo\n\n{ x = 0; }\n"))
+	h2, _ := litmus.CanonicalHash(strings.NewReader("C bar\n{\n    x = 0;\n}"))
+	h3, _ := litmus.CanonicalHash(strings.NewReader("C foo\n\n{ x = 1; }\n"))
+	fmt.Println(h1 == h2)
+	fmt.Println(h1 == h3)
+
+	// Output:
+	// true
+	// false
+}
+
+// TestCanonicalHash tests CanonicalHash on various pairs of litmus tests.
+func TestCanonicalHash(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		a, b string
+		same bool
+	}{
+		"identical":        {a: "C foo { x = 0; }", b: "C foo { x = 0; }", same: true},
+		"renamed":          {a: "C foo { x = 0; }", b: "C bar { x = 0; }", same: true},
+		"whitespace":       {a: "C foo\n{ x = 0; }", b: "C   foo\t{\n\tx   =  0;\n}\n", same: true},
+		"different-body":   {a: "C foo { x = 0; }", b: "C foo { x = 1; }"},
+		"different-arch":   {a: "C foo { x = 0; }", b: "X86 foo { x = 0; }"},
+		"word-boundaries":  {a: "C foo { x = 0; }", b: "C foo {x=0;}", same: true},
+		"punctuation":      {a: "C foo P0(atomic_int *x) {}", b: "C foo P0 (atomic_int * x) { }", same: true},
+		"name-not-in-body": {a: "C foo { foo = 0; }", b: "C bar { bar = 0; }"},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ha, err := litmus.CanonicalHash(strings.NewReader(c.a))
+			require.NoError(t, err, "hashing a")
+			hb, err := litmus.CanonicalHash(strings.NewReader(c.b))
+			require.NoError(t, err, "hashing b")
+			assert.Equal(t, c.same, ha == hb, "wrong hash equality")
+		})
+	}
+}
+
+// TestReadHashFromFile tests that ReadHashFromFile populates the hash of a litmus test.
+func TestReadHashFromFile(t *testing.T) {
+	t.Parallel()
+
+	l, err := litmus.New(filepath.Join("testdata", "c.litmus"), litmus.ReadHashFromFile())
+	require.NoError(t, err, "reading litmus test")
+	want, err := litmus.CanonicalHashOfFile(filepath.Join("testdata", "c.litmus"))
+	require.NoError(t, err, "hashing litmus test")
+	assert.Equal(t, want, l.Hash, "wrong hash")
+	assert.NotEmpty(t, l.Hash, "hash should be populated")
+
+	_, err = litmus.New(filepath.Join("testdata", "nonexistent.litmus"), litmus.ReadHashFromFile())
+	assert.Error(t, err, "hashing a missing file should fail")
+}
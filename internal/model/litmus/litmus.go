@@ -31,6 +31,9 @@ type Litmus struct {
 
 	// Stats contains, if available, the statistics set for this litmus test.
 	Stats *Statset `json:"stats,omitempty"`
+
+	// Hash contains, if available, the canonical hash of this litmus test; see CanonicalHash.
+	Hash string `json:"hash,omitempty"`
 }
 
 // New constructs a litmus record for slashpath path and options os.
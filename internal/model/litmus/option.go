@@ -39,6 +39,11 @@ func ReadArchFromFile() Option {
 	return (*Litmus).PopulateArchFromFile
 }
 
+// ReadHashFromFile is an option that causes the litmus test to populate its canonical hash from its given file.
+func ReadHashFromFile() Option {
+	return (*Litmus).PopulateHashFromFile
+}
+
 // WithArch is an option that forces the litmus test's architecture to be id.
 func WithArch(id id.ID) Option {
 	return func(l *Litmus) error {
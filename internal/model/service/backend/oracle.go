@@ -0,0 +1,46 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package backend
+
+import (
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+)
+
+// DefaultOracleModel is the memory model that the oracle asks its backend to use if none is configured.
+const DefaultOracleModel = "c11.cat"
+
+// OracleConfig configures the reference-model oracle.
+type OracleConfig struct {
+	// Disabled, if set true, disables the oracle stage in the main tester.
+	Disabled bool `toml:"disabled,omitempty"`
+
+	// Backend, if given, overrides the specification of the backend used as the reference model.
+	// By default, the oracle uses herd7.
+	Backend *Spec `toml:"backend,omitempty"`
+
+	// Model, if given, overrides the name of the memory model file passed to the reference model.
+	Model string `toml:"model,omitempty"`
+}
+
+// Spec gets the backend specification for the reference model, including the model argument.
+func (c *OracleConfig) Spec() Spec {
+	s := Spec{Style: id.FromString("herdtools.herd")}
+	model := DefaultOracleModel
+	if c != nil {
+		if c.Backend != nil {
+			s = *c.Backend
+		}
+		if c.Model != "" {
+			model = c.Model
+		}
+	}
+
+	r := service.RunInfo{Args: []string{"-model", model}}
+	r.OverrideIfNotNil(s.Run)
+	s.Run = &r
+	return s
+}
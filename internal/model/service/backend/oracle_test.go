@@ -0,0 +1,32 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package backend_test
+
+import (
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/backend"
+)
+
+// ExampleOracleConfig_Spec is a runnable example for OracleConfig.Spec.
+func ExampleOracleConfig_Spec() {
+	var c *backend.OracleConfig
+	s := c.Spec()
+	fmt.Println(s.Style, s.Run)
+
+	c = &backend.OracleConfig{
+		Backend: &backend.Spec{Style: id.FromString("herdtools.herd"), Run: &service.RunInfo{Cmd: "herd", Args: []string{"-speedcheck", "true"}}},
+		Model:   "rc11.cat",
+	}
+	s = c.Spec()
+	fmt.Println(s.Style, s.Run)
+
+	// Output:
+	// herdtools.herd  -model c11.cat
+	// herdtools.herd herd -model rc11.cat -speedcheck true
+}
@@ -37,6 +37,12 @@ type Compiler struct {
 
 	// Opt contains information on the optimisation levels to select for the compiler.
 	Opt *optlevel.Selection `toml:"opt,omitempty" json:"opt,omitempty"`
+
+	// Flags contains a pool of extra flags from which to select when perturbing the compiler.
+	Flags *FlagPool `toml:"flags,omitempty" json:"flags,omitempty"`
+
+	// Template contains the argument template for compilers using the templated style.
+	Template *Template `toml:"template,omitempty" json:"template,omitempty"`
 }
 
 // Config denotes raw configuration for a Compiler.
@@ -0,0 +1,111 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// DefaultFlagProbability is the probability with which the perturber selects an optional flag, or a flag from a
+// group, that has no probability set.
+const DefaultFlagProbability = 0.5
+
+// ErrBadFlagProbability occurs when a flag pool contains a probability outside the range [0, 1].
+var ErrBadFlagProbability = errors.New("flag probability must be between 0 and 1")
+
+// FlagPool is a pool of extra flags from which the perturber can randomly select when configuring a compiler.
+type FlagPool struct {
+	// Optional maps each independently selectable flag to the probability of selecting it.
+	// A probability of zero stands for DefaultFlagProbability.
+	Optional map[string]float64 `toml:"optional,omitempty" json:"optional,omitempty"`
+
+	// Groups contains groups of mutually exclusive flags.
+	Groups []FlagGroup `toml:"groups,omitempty" json:"groups,omitempty"`
+}
+
+// FlagGroup is a group of mutually exclusive flags; the perturber selects at most one flag from each group.
+type FlagGroup struct {
+	// Flags contains the flags in this group.
+	Flags []string `toml:"flags" json:"flags"`
+
+	// Probability is the probability of selecting any flag from this group; the perturber then picks one of the flags
+	// uniformly.  A probability of zero stands for DefaultFlagProbability.
+	Probability float64 `toml:"probability,omitzero" json:"probability,omitempty"`
+}
+
+// Check checks that every probability in p is in range.
+func (p *FlagPool) Check() error {
+	if p == nil {
+		return nil
+	}
+	for f, prob := range p.Optional {
+		if err := checkFlagProbability(prob); err != nil {
+			return fmt.Errorf("flag %q: %w", f, err)
+		}
+	}
+	for i, g := range p.Groups {
+		if err := checkFlagProbability(g.Probability); err != nil {
+			return fmt.Errorf("flag group %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func checkFlagProbability(prob float64) error {
+	if prob < 0 || 1 < prob {
+		return fmt.Errorf("%w: %g", ErrBadFlagProbability, prob)
+	}
+	return nil
+}
+
+// OptionalNames gets the names of the optional flags in p, in sorted order.
+func (p *FlagPool) OptionalNames() []string {
+	if p == nil {
+		return nil
+	}
+	fs := make([]string, 0, len(p.Optional))
+	for f := range p.Optional {
+		fs = append(fs, f)
+	}
+	sort.Strings(fs)
+	return fs
+}
+
+// OptionalProbability gets the effective probability of selecting optional flag f.
+func (p *FlagPool) OptionalProbability(f string) float64 {
+	return effectiveFlagProbability(p.Optional[f])
+}
+
+// EffectiveProbability gets the effective probability of selecting a flag from g.
+func (g FlagGroup) EffectiveProbability() float64 {
+	return effectiveFlagProbability(g.Probability)
+}
+
+func effectiveFlagProbability(prob float64) float64 {
+	if prob == 0 {
+		return DefaultFlagProbability
+	}
+	return prob
+}
+
+// flagsTag gets a short ID tag summarising the flag selection fs, or the empty string if fs is empty.
+//
+// The tag is a hash rather than the flags themselves, as flags often contain characters that can't appear in IDs.
+func flagsTag(fs []string) string {
+	if len(fs) == 0 {
+		return ""
+	}
+	sorted := append([]string(nil), fs...)
+	sort.Strings(sorted)
+	h := fnv.New32a()
+	// Writes to hashes never fail.
+	_, _ = h.Write([]byte(strings.Join(sorted, "\x00")))
+	return fmt.Sprintf("f%08x", h.Sum32())
+}
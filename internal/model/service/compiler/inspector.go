@@ -6,9 +6,12 @@
 package compiler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	"github.com/c4-project/c4t/internal/model/service"
+
 	"github.com/c4-project/c4t/internal/helper/stringhelp"
 
 	"github.com/c4-project/c4t/internal/model/service/compiler/optlevel"
@@ -85,3 +88,11 @@ func filterLevels(choices stringhelp.Set, all map[string]optlevel.Level) (map[st
 
 	return chosen, nil
 }
+
+// PassController is the interface of types that can list and disable the individual optimisation passes of a compiler.
+type PassController interface {
+	// OptPasses uses sr to list the names of the passes that c enables at its selected optimisation level.
+	OptPasses(ctx context.Context, c *Instance, sr service.Runner) ([]string, error)
+	// DisablePasses modifies c so that compiling with it disables each of passes, which should come from OptPasses.
+	DisablePasses(c *Instance, passes []string) error
+}
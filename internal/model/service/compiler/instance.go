@@ -36,6 +36,8 @@ type Instance struct {
 	SelectedMOpt string `json:"selected_mopt,omitempty"`
 	// SelectedOpt refers to an optimisation level chosen using the compiler's configured optimisation selection.
 	SelectedOpt *optlevel.Named `json:"selected_opt,omitempty"`
+	// SelectedFlags contains any extra flags chosen from the compiler's configured flag pool.
+	SelectedFlags []string `json:"selected_flags,omitempty"`
 	// ConfigTime captures the time at which this compiler configuration was generated.
 	//
 	// An example of when this may be useful is when using a compiler with run-time mutations enabled; we can use the
@@ -44,6 +46,8 @@ type Instance struct {
 	ConfigTime time.Time `json:"config_time,omitempty"`
 	// Mutant captures any mutant ID attached to this compiler instance.
 	Mutant mutation.Mutant `json:"mutant,omitempty"`
+	// Version is the version of the compiler, if it has been probed.
+	Version *Version `json:"version,omitempty"`
 	Compiler
 }
 
@@ -85,6 +89,11 @@ func (c Instance) stringErr() (string, error) {
 			return "", err
 		}
 	}
+	if len(c.SelectedFlags) != 0 {
+		if _, err := fmt.Fprintf(&sb, " flags %q", c.SelectedFlags); err != nil {
+			return "", err
+		}
+	}
 
 	return sb.String(), nil
 }
@@ -56,3 +56,11 @@ func (j *Job) SelectedMOptName() string {
 	}
 	return j.Compiler.SelectedMOpt
 }
+
+// SelectedFlags gets this job's compiler's selected extra flags, if present; else, nil.
+func (j *Job) SelectedFlags() []string {
+	if j.Compiler == nil {
+		return nil
+	}
+	return j.Compiler.SelectedFlags
+}
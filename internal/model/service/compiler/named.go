@@ -26,6 +26,8 @@ func (c Instance) AddName(name id.ID) *Named {
 
 // FullID gets a fully qualified identifier for this configuration, consisting of the compiler name, followed by
 // 'oOpt' where 'Opt' is its selected optimisation name, and 'mMopt' where 'Mopt' is its selected machine profile.
+// If the instance has any extra flags selected, a further tag 'fHash' follows, where 'Hash' is a short hash of the
+// flag set.
 //
 // Where Opt or Mopt contain '.', these become '_'.  This behaviour may change.
 func (n Named) FullID() (id.ID, error) {
@@ -36,5 +38,9 @@ func (n Named) FullID() (id.ID, error) {
 
 	// We don't append in the config time, which means that this ID doesn't fully capture the compiler specification;
 	// that said, maybe the config time being a part of the specification is a rare enough case that we needn't worry.
-	return id.New(append(n.ID.Tags(), "o"+o, "m"+m)...)
+	tags := append(n.ID.Tags(), "o"+o, "m"+m)
+	if f := flagsTag(n.SelectedFlags); f != "" {
+		tags = append(tags, f)
+	}
+	return id.New(tags...)
 }
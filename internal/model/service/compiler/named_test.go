@@ -56,3 +56,17 @@ func ExampleNamed_FullID_dotarch() {
 	// Output:
 	// gcc.8.o.march=armv8_1-a
 }
+
+// ExampleNamed_FullID_flags is a runnable example for Named.FullID where the instance has extra flags.
+func ExampleNamed_FullID_flags() {
+	c1 := compiler.Instance{SelectedMOpt: "arch=skylake", SelectedFlags: []string{"-fwrapv", "-fno-inline"}}
+	c2 := compiler.Instance{SelectedMOpt: "arch=skylake", SelectedFlags: []string{"-fno-inline", "-fwrapv"}}
+	i1, _ := c1.AddName(id.FromString("gcc")).FullID()
+	i2, _ := c2.AddName(id.FromString("gcc")).FullID()
+	fmt.Println(i1)
+	fmt.Println(i1.Equal(i2))
+
+	// Output:
+	// gcc.o.march=skylake.fca8fbe5a
+	// true
+}
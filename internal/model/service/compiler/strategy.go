@@ -0,0 +1,94 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PerturbConfig configures how the perturber chooses compiler instances from each configured compiler.
+type PerturbConfig struct {
+	// Strategy is the strategy the perturber uses to pick optimisation levels and machine profiles.
+	Strategy Strategy `toml:"strategy,omitzero" json:"strategy,omitempty"`
+
+	// Exploration, if nonzero, overrides the exploration constant used by the bandit strategy.
+	// Higher values make the bandit more willing to try configurations that have not yet yielded much.
+	Exploration float64 `toml:"exploration,omitzero" json:"exploration,omitempty"`
+}
+
+// Strategy is an enumeration of perturbation strategies for compilers.
+type Strategy uint8
+
+const (
+	// StrategyRandom picks one random optimisation level and machine profile for each compiler on each cycle.
+	StrategyRandom Strategy = iota
+	// StrategyMatrix walks through every combination of optimisation level and machine profile, across cycles.
+	StrategyMatrix
+	// StrategyPairwise walks through a covering array in which every pair of choices from different configuration
+	// axes appears at least once, across cycles.
+	StrategyPairwise
+	// StrategyBandit weights its choice of optimisation level and machine profile by how many interesting results
+	// each combination has yielded in the past, while still exploring combinations that have rarely been tried.
+	StrategyBandit
+	// NumStrategy marks the number of strategy members.
+	NumStrategy
+)
+
+var (
+	// ErrBadStrategy occurs when we try to marshal/unmarshal a strategy that doesn't exist.
+	ErrBadStrategy = errors.New("no such perturbation strategy")
+
+	strategyStrings = [NumStrategy]string{
+		"random",
+		"matrix",
+		"pairwise",
+		"bandit",
+	}
+)
+
+// StrategyOfString tries to get the strategy corresponding to s.
+func StrategyOfString(s string) (Strategy, error) {
+	for i := StrategyRandom; i < NumStrategy; i++ {
+		if strings.EqualFold(strategyStrings[i], s) {
+			return i, nil
+		}
+	}
+	return StrategyRandom, fmt.Errorf("%w: %s", ErrBadStrategy, s)
+}
+
+// String converts this strategy into a human-readable string.
+func (s Strategy) String() string {
+	ts, err := s.tryString()
+	if err != nil {
+		return "(ERROR)"
+	}
+	return ts
+}
+
+// MarshalText tries to marshal this strategy into text.
+func (s Strategy) MarshalText() ([]byte, error) {
+	ts, err := s.tryString()
+	if err != nil {
+		return []byte{}, err
+	}
+	return []byte(ts), nil
+}
+
+func (s Strategy) tryString() (string, error) {
+	if NumStrategy <= s {
+		return "", fmt.Errorf("%w: #%d", ErrBadStrategy, s)
+	}
+	return strategyStrings[s], nil
+}
+
+// UnmarshalText tries to unmarshal text into a Strategy.
+func (s *Strategy) UnmarshalText(text []byte) error {
+	var err error
+	*s, err = StrategyOfString(string(text))
+	return err
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package compiler_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/stretchr/testify/assert"
+)
+
+// ExampleStrategyOfString is a runnable example for StrategyOfString.
+func ExampleStrategyOfString() {
+	s, _ := compiler.StrategyOfString("Pairwise")
+	fmt.Println(s)
+	_, err := compiler.StrategyOfString("bogo")
+	fmt.Println(err)
+
+	// Output:
+	// pairwise
+	// no such perturbation strategy: bogo
+}
+
+// TestStrategy_UnmarshalText tests round-tripping strategies through text.
+func TestStrategy_UnmarshalText(t *testing.T) {
+	t.Parallel()
+
+	for i := compiler.StrategyRandom; i < compiler.NumStrategy; i++ {
+		want := i
+		t.Run(want.String(), func(t *testing.T) {
+			t.Parallel()
+			bs, err := want.MarshalText()
+			if !testhelp.ExpectErrorIs(t, err, nil, "marshalling") {
+				return
+			}
+			var got compiler.Strategy
+			if !testhelp.ExpectErrorIs(t, got.UnmarshalText(bs), nil, "unmarshalling") {
+				return
+			}
+			assert.Equal(t, want, got, "round trip changed strategy")
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package compiler
+
+import (
+	"strings"
+
+	"github.com/c4-project/c4t/internal/model/service/compiler/optlevel"
+)
+
+// Template describes how to invoke a compiler whose argument layout comes from configuration rather than code.
+//
+// Templates are used by the 'templated' compiler style.
+type Template struct {
+	// Args is the list of argument templates.
+	//
+	// Each argument can refer to the variables ${in}, ${out}, ${opt}, ${mopt}, ${kind}, and ${flags}, as well as any of
+	// the instance interpolations (such as ${mutant}).  An argument that is exactly ${in} or ${flags} expands to one
+	// argument per input file or selected flag; an argument mentioning ${opt}, ${mopt}, ${kind}, or ${flags} is dropped
+	// if that variable is empty for the job.
+	Args []string `toml:"args,omitempty" json:"args,omitempty"`
+
+	// Kinds maps lowercased compile target names ('exe', 'obj') to the value of ${kind} for that target.
+	// If nil, DefaultKinds is used.
+	Kinds map[string]string `toml:"kinds,omitempty" json:"kinds,omitempty"`
+
+	// OptLevels declares the optimisation levels the compiler supports, by the value they give to ${opt}.
+	// All of these levels are enabled by default.
+	OptLevels map[string]optlevel.Level `toml:"opt_levels,omitempty" json:"opt_levels,omitempty"`
+
+	// MOpts declares the machine optimisation profiles enabled by default, by the value they give to ${mopt}.
+	MOpts []string `toml:"mopts,omitempty" json:"mopts,omitempty"`
+}
+
+// DefaultKinds is the mapping from compile target names to ${kind} values used if a template doesn't specify one.
+// It follows the near-universal Unix convention of '-c' for object files.
+var DefaultKinds = map[string]string{"obj": "-c"}
+
+// KindArg gets the value this template gives to ${kind} for target k.
+func (t Template) KindArg(k Target) string {
+	kinds := t.Kinds
+	if kinds == nil {
+		kinds = DefaultKinds
+	}
+	return kinds[strings.ToLower(k.String())]
+}
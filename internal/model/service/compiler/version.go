@@ -0,0 +1,64 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package compiler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/c4-project/c4t/internal/model/service"
+)
+
+// ErrBadVersion occurs when we can't parse a compiler version.
+var ErrBadVersion = errors.New("can't parse compiler version")
+
+// versionRegexp matches dotted version numbers with at least a major and minor component.
+var versionRegexp = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// Version is a compiler version.
+type Version struct {
+	// Major is the major version number.
+	Major int `json:"major"`
+	// Minor is the minor version number.
+	Minor int `json:"minor"`
+	// Patch is the patch version number, if any.
+	Patch int `json:"patch,omitempty"`
+}
+
+// FindVersion finds and parses the first dotted version number in s.
+func FindVersion(s string) (*Version, error) {
+	m := versionRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("%w: no version number in %q", ErrBadVersion, s)
+	}
+	var (
+		v   Version
+		err error
+	)
+	for i, dst := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if m[i+1] == "" {
+			continue
+		}
+		if *dst, err = strconv.Atoi(m[i+1]); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrBadVersion, err)
+		}
+	}
+	return &v, nil
+}
+
+// String formats v as a dotted version number.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// VersionProber is the interface of types that can find out the version of a compiler.
+type VersionProber interface {
+	// ProbeVersion uses sr to find out the version of compiler c.
+	ProbeVersion(ctx context.Context, c *Compiler, sr service.Runner) (*Version, error)
+}
@@ -0,0 +1,26 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package fuzzer
+
+import "context"
+
+// ReplayJob contains information on how to replay a fuzzer trace against a litmus file.
+type ReplayJob struct {
+	// In is the slashpath to the original, unfuzzed litmus file.
+	In string
+
+	// Trace is the slashpath to the trace file to replay.
+	Trace string
+
+	// OutLitmus is the slashpath to the litmus file that should be outputted by the replay.
+	OutLitmus string
+}
+
+// Replayer is the interface of things that can replay fuzzer traces.
+type Replayer interface {
+	// Replay replays the trace in j against its input, deterministically reproducing a fuzzer output.
+	Replay(ctx context.Context, j ReplayJob) error
+}
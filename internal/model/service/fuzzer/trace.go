@@ -0,0 +1,159 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package fuzzer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+)
+
+// ErrBadTrace occurs when a trace file isn't a well-formed list of fuzzer actions.
+var ErrBadTrace = errors.New("malformed trace")
+
+// Trace is a fuzzer trace, split into its top-level steps.
+//
+// Traces are s-expressions whose top-level list contains one element per fuzzer action; we don't interpret the
+// actions themselves, only split them so that we can replay subsets of a trace.
+type Trace struct {
+	// Steps contains the verbatim text of each action in the trace.
+	Steps []string
+}
+
+// ReadTrace reads a trace from r.
+func ReadTrace(r io.Reader) (*Trace, error) {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTrace(string(bs))
+}
+
+// ReadTraceFile reads a trace from the file at path.
+func ReadTraceFile(path string) (*Trace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := ReadTrace(f)
+	cerr := f.Close()
+	return t, errhelp.FirstError(err, cerr)
+}
+
+// ParseTrace splits the trace text s into its top-level steps.
+func ParseTrace(s string) (*Trace, error) {
+	body := strings.TrimSpace(s)
+	if !strings.HasPrefix(body, "(") || !strings.HasSuffix(body, ")") {
+		return nil, fmt.Errorf("%w: trace should be a parenthesised list", ErrBadTrace)
+	}
+	steps, err := splitSexps(body[1 : len(body)-1])
+	if err != nil {
+		return nil, err
+	}
+	return &Trace{Steps: steps}, nil
+}
+
+// splitSexps splits s into its top-level s-expressions.
+func splitSexps(s string) ([]string, error) {
+	var (
+		steps []string
+		depth int
+		start = -1
+		quote bool
+		esc   bool
+	)
+	for i, r := range s {
+		switch {
+		case esc:
+			esc = false
+			continue
+		case quote && r == '\\':
+			esc = true
+			continue
+		case quote:
+			quote = r != '"'
+			if quote {
+				continue
+			}
+		case r == '"':
+			quote = true
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth == 0 {
+				return nil, fmt.Errorf("%w: unbalanced ')' at offset %d", ErrBadTrace, i)
+			}
+			depth--
+		}
+		if start < 0 && !unicode.IsSpace(r) {
+			start = i
+		}
+		if start >= 0 && depth == 0 && !quote && (r == ')' || endOfAtom(s, i)) {
+			steps = append(steps, s[start:i+1])
+			start = -1
+		}
+	}
+	if depth != 0 || quote {
+		return nil, fmt.Errorf("%w: unterminated step", ErrBadTrace)
+	}
+	return steps, nil
+}
+
+// endOfAtom checks whether offset i in s is the last character of a bare atom.
+func endOfAtom(s string, i int) bool {
+	if s[i] == '(' || s[i] == ')' || unicode.IsSpace(rune(s[i])) {
+		return false
+	}
+	return i+1 == len(s) || s[i+1] == '(' || s[i+1] == ')' || unicode.IsSpace(rune(s[i+1]))
+}
+
+// Len gets the number of steps in the trace.
+func (t *Trace) Len() int {
+	return len(t.Steps)
+}
+
+// Select makes a copy of this trace containing only the steps at the given ascending indices.
+func (t *Trace) Select(is []int) *Trace {
+	steps := make([]string, len(is))
+	for i, j := range is {
+		steps[i] = t.Steps[j]
+	}
+	return &Trace{Steps: steps}
+}
+
+// Write writes this trace to w, one step per line.
+func (t *Trace) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, "("); err != nil {
+		return err
+	}
+	for i, s := range t.Steps {
+		sep := "\n "
+		if i == 0 {
+			sep = ""
+		}
+		if _, err := io.WriteString(w, sep+s); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, ")\n")
+	return err
+}
+
+// WriteFile writes this trace to the file at path.
+func (t *Trace) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	werr := t.Write(f)
+	cerr := f.Close()
+	return errhelp.FirstError(werr, cerr)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package fuzzer_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/stretchr/testify/assert"
+)
+
+// ExampleTrace_Select is a runnable example for Trace.Select.
+func ExampleTrace_Select() {
+	t, err := fuzzer.ParseTrace(`((var.make (name x)) (var.make (name y)) (store (dst x) (src 1)) nop)`)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(t.Len(), "steps")
+	_ = t.Select([]int{0, 3}).Write(os.Stdout)
+
+	// Output:
+	// 4 steps
+	// ((var.make (name x))
+	//  nop)
+}
+
+// TestParseTrace tests ParseTrace on various well- and ill-formed traces.
+func TestParseTrace(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		in    string
+		steps []string
+		err   error
+	}{
+		"empty":    {in: "()", steps: nil},
+		"atoms":    {in: " (a b  c) ", steps: []string{"a", "b", "c"}},
+		"nested":   {in: "((a (b c)) (d))", steps: []string{"(a (b c))", "(d)"}},
+		"strings":  {in: `((a "b)") "c (d" e)`, steps: []string{`(a "b)")`, `"c (d"`, "e"}},
+		"escapes":  {in: `((a "\")"))`, steps: []string{`(a "\")")`}},
+		"not-list": {in: "a b", err: fuzzer.ErrBadTrace},
+		"unclosed": {in: "((a b)", err: fuzzer.ErrBadTrace},
+		"extra":    {in: "((a b)))", err: fuzzer.ErrBadTrace},
+		"quote":    {in: `("a)`, err: fuzzer.ErrBadTrace},
+	}
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tr, err := fuzzer.ParseTrace(c.in)
+			if !testhelp.ExpectErrorIs(t, err, c.err, "parsing trace") || err != nil {
+				return
+			}
+			assert.Equal(t, c.steps, tr.Steps, "steps")
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package generator considers corpus generators (such as memalloy, or scripts that emit litmus tests) as services.
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/1set/gut/ystring"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+)
+
+// VarOutDir is the interpolation variable that expands to the directory into which a generator should write its tests.
+const VarOutDir = "outdir"
+
+var (
+	// ErrNameBlank occurs when a generator has no name.
+	ErrNameBlank = errors.New("generator name blank")
+
+	// ErrCmdBlank occurs when a generator has no command.
+	ErrCmdBlank = errors.New("generator command blank")
+)
+
+// Config configures a corpus generator.
+type Config struct {
+	// Name is the name of the generator; it must be a valid ID, and names the directories holding its output.
+	Name string `toml:"name"`
+
+	// Disabled, if set true, stops the director from running the generator.
+	Disabled bool `toml:"disabled,omitempty"`
+
+	// Run is the command to run; any ${outdir} in its arguments or environment expands to the output directory.
+	Run service.RunInfo `toml:"run"`
+}
+
+// Check makes sure that this generator config is well-formed.
+func (c Config) Check() error {
+	if ystring.IsBlank(c.Name) {
+		return ErrNameBlank
+	}
+	if _, err := id.TryFromString(c.Name); err != nil {
+		return fmt.Errorf("bad generator name %q: %w", c.Name, err)
+	}
+	if ystring.IsBlank(c.Run.Cmd) {
+		return fmt.Errorf("%w: %s", ErrCmdBlank, c.Name)
+	}
+	return nil
+}
+
+// Generate uses r to run this generator, asking it to write its tests into dir.
+func (c Config) Generate(ctx context.Context, r service.Runner, dir string) error {
+	// Interpolate makes a new argument slice and environment, so this doesn't affect the config.
+	ri := c.Run
+	if err := ri.Interpolate(map[string]string{VarOutDir: dir}); err != nil {
+		return fmt.Errorf("expanding generator %s: %w", c.Name, err)
+	}
+	if err := r.Run(ctx, ri); err != nil {
+		return fmt.Errorf("running generator %s: %w", c.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package generator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/generator"
+	"github.com/c4-project/c4t/internal/model/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfig_Check tests Config.Check on various configs.
+func TestConfig_Check(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		cfg generator.Config
+		err error
+	}{
+		"ok":         {cfg: generator.Config{Name: "memalloy.sc", Run: service.RunInfo{Cmd: "memalloy"}}},
+		"blank-name": {cfg: generator.Config{Run: service.RunInfo{Cmd: "memalloy"}}, err: generator.ErrNameBlank},
+		"bad-name":   {cfg: generator.Config{Name: "memalloy..sc", Run: service.RunInfo{Cmd: "memalloy"}}, err: id.ErrTagEmpty},
+		"blank-cmd":  {cfg: generator.Config{Name: "memalloy"}, err: generator.ErrCmdBlank},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			testhelp.ExpectErrorIs(t, c.cfg.Check(), c.err, "checking generator config")
+		})
+	}
+}
+
+// TestConfig_Generate tests that Config.Generate expands the output directory into the run information.
+func TestConfig_Generate(t *testing.T) {
+	t.Parallel()
+
+	cfg := generator.Config{
+		Name: "gen",
+		Run: service.RunInfo{
+			Cmd:  "gen.sh",
+			Args: []string{"-o", "${outdir}", "-n", "10"},
+			Env:  map[string]string{"GEN_OUT": "${outdir}/tests"},
+		},
+	}
+
+	r := new(mocks.Runner)
+	r.Test(t)
+	r.On("Run", mock.Anything, service.RunInfo{
+		Cmd:  "gen.sh",
+		Args: []string{"-o", "out", "-n", "10"},
+		Env:  map[string]string{"GEN_OUT": "out/tests"},
+	}).Return(nil).Once()
+
+	require.NoError(t, cfg.Generate(context.Background(), r, "out"), "running mock generator")
+	r.AssertExpectations(t)
+	assert.Equal(t, []string{"-o", "${outdir}", "-n", "10"}, cfg.Run.Args, "generate shouldn't modify config")
+}
+
+// TestConfig_Generate_error tests that Config.Generate propagates runner errors.
+func TestConfig_Generate_error(t *testing.T) {
+	t.Parallel()
+
+	cfg := generator.Config{Name: "gen", Run: service.RunInfo{Cmd: "gen.sh"}}
+	want := errors.New("oops")
+
+	r := new(mocks.Runner)
+	r.Test(t)
+	r.On("Run", mock.Anything, mock.Anything).Return(want).Once()
+
+	err := cfg.Generate(context.Background(), r, "out")
+	testhelp.ExpectErrorIs(t, err, want, "running failing generator")
+	r.AssertExpectations(t)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package transformer considers external transformers (such as C-level source rewriters or normalisers) as services.
+package transformer
+
+import (
+	"errors"
+
+	"github.com/1set/gut/ystring"
+	"github.com/c4-project/c4t/internal/model/service"
+)
+
+const (
+	// VarIn is the interpolation variable that expands to the path of the litmus test to transform.
+	VarIn = "in"
+	// VarOut is the interpolation variable that expands to the path to which the transformer should write its output.
+	VarOut = "out"
+)
+
+// ErrCmdBlank occurs when a transformer has no command.
+var ErrCmdBlank = errors.New("transformer command blank")
+
+// Config configures an external transformer.
+type Config struct {
+	// Disabled, if set true, disables the transformer stage in the main tester.
+	Disabled bool `toml:"disabled,omitempty"`
+
+	// Run is the command to run on each subject.
+	// Any ${in} and ${out} in its arguments or environment expand to the input and output litmus test paths.
+	Run service.RunInfo `toml:"run"`
+}
+
+// Check makes sure that this transformer config is well-formed.
+func (c *Config) Check() error {
+	if ystring.IsBlank(c.Run.Cmd) {
+		return ErrCmdBlank
+	}
+	return nil
+}
+
+// Job gets the run information for transforming the litmus test at in into a litmus test at out.
+func (c *Config) Job(in, out string) (service.RunInfo, error) {
+	// Interpolate makes a new argument slice and environment, so this doesn't affect the config.
+	ri := c.Run
+	err := ri.Interpolate(map[string]string{VarIn: in, VarOut: out})
+	return ri, err
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package transformer_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/transformer"
+)
+
+// ExampleConfig_Job is a runnable example for Config.Job.
+func ExampleConfig_Job() {
+	c := transformer.Config{Run: service.RunInfo{
+		Cmd:  "normalise",
+		Args: []string{"-i", "${in}", "-o", "${out}"},
+	}}
+	ri, _ := c.Job("foo.litmus", "out/foo.litmus")
+	fmt.Println(ri.String())
+	fmt.Println(c.Run.String())
+
+	// Output:
+	// normalise -i foo.litmus -o out/foo.litmus
+	// normalise -i ${in} -o ${out}
+}
+
+// TestConfig_Check tests Config.Check on various configs.
+func TestConfig_Check(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		cfg transformer.Config
+		err error
+	}{
+		"ok":        {cfg: transformer.Config{Run: service.RunInfo{Cmd: "normalise"}}},
+		"blank-cmd": {cfg: transformer.Config{Run: service.RunInfo{Args: []string{"${in}"}}}, err: transformer.ErrCmdBlank},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			testhelp.ExpectErrorIs(t, c.cfg.Check(), c.err, "checking transformer config")
+		})
+	}
+}
@@ -92,7 +92,8 @@ func (h SelectionAnalysis) Hit() bool {
 	return 0 < h.NumHits
 }
 
-// Killed gets whether this selection resulted in a kill (hit at least once and resulted in a flagged status).
+// Killed gets whether this selection resulted in a kill (hit at least once and resulted in a flagged or forbidden
+// status).
 func (h SelectionAnalysis) Killed() bool {
-	return h.Hit() && h.Status == status.Flagged
+	return h.Hit() && (h.Status == status.Flagged || h.Status == status.Forbidden)
 }
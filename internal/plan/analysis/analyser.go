@@ -152,7 +152,16 @@ func (a *analyser) build(ctx context.Context, ch <-chan subjectAnalysis) error {
 }
 
 func (a *analyser) apply(r subjectAnalysis) {
+	// Failed subjects never reach the compilation stages, so they don't have statuses.
+	if r.sub.HasFailed() {
+		a.applyFailure(r)
+		return
+	}
+
 	a.analysis.Flags |= r.flags
+	for v, n := range r.confirms {
+		a.analysis.Confirmations[v] += n
+	}
 	a.applyCompilers(r)
 	a.applyTimes(r)
 	a.applyMutants(r)
@@ -186,6 +195,14 @@ func (a *analyser) applyByStatus(s status.Status, r subjectAnalysis) {
 	a.analysis.ByStatus[s][r.sub.Name] = r.sub.Subject
 }
 
+func (a *analyser) applyFailure(r subjectAnalysis) {
+	f := r.sub.Failure
+	if _, ok := a.analysis.StageFailures[f.Stage]; !ok {
+		a.analysis.StageFailures[f.Stage] = make(corpus.Corpus)
+	}
+	a.analysis.StageFailures[f.Stage][r.sub.Name] = r.sub.Subject
+}
+
 func (a *analyser) applyCompilerStatusCount(s status.Status, cf status.Flag, cid id.ID) {
 	if !cf.MatchesStatus(s) {
 		return
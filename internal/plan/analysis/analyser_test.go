@@ -7,6 +7,7 @@ package analysis_test
 
 import (
 	"context"
+	"errors"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -19,12 +20,15 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/c4-project/c4t/internal/subject/confirm"
 	"github.com/c4-project/c4t/internal/subject/status"
 
 	"github.com/c4-project/c4t/internal/helper/testhelp"
 	"github.com/c4-project/c4t/internal/subject/corpus"
 
 	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/subject"
 )
 
 // TestAnalyse_errors tests various errors while analysing plans.
@@ -97,6 +101,46 @@ func TestAnalyse_mock(t *testing.T) {
 	}
 }
 
+// TestAnalyse_confirmed tests that analysing a plan with confirmed runs counts their verdicts.
+func TestAnalyse_confirmed(t *testing.T) {
+	t.Parallel()
+
+	m := plan.Mock()
+	m.Corpus = m.Corpus.Copy()
+	confirmRun(t, m.Corpus, "baz", "gcc", confirm.Reproducible)
+	confirmRun(t, m.Corpus, "baz", "icc", confirm.Intermittent)
+	confirmRun(t, m.Corpus, "barbaz", "msvc", confirm.Reproducible)
+
+	crp, err := analysis.Analyse(context.Background(), m)
+	require.NoError(t, err, "unexpected error analysing")
+
+	assert.Equal(t, map[confirm.Verdict]int{confirm.Reproducible: 2, confirm.Intermittent: 1}, crp.Confirmations)
+}
+
+func confirmRun(t *testing.T, c corpus.Corpus, sname, cid string, v confirm.Verdict) {
+	t.Helper()
+	s := c[sname]
+	require.NoError(t, s.AddConfirmation(id.FromString(cid), v), "confirming mock run")
+	c[sname] = s
+}
+
+// TestAnalyse_failed tests that analysing a plan with failed subjects collates them by stage.
+func TestAnalyse_failed(t *testing.T) {
+	t.Parallel()
+
+	m := plan.Mock()
+	m.Corpus = m.Corpus.Copy()
+	fail := subject.NewFailure(stage.Lift, errors.New("lifter crashed"), false)
+	m.Corpus["failed"] = subject.Subject{Source: m.Corpus["foo"].Source, Failure: &fail}
+
+	crp, err := analysis.Analyse(context.Background(), m)
+	require.NoError(t, err, "unexpected error analysing")
+
+	assert.Equal(t, []string{"failed"}, crp.StageFailures[stage.Lift].Names(), "wrong lift failures")
+	assert.Empty(t, crp.StageFailures[stage.Fuzz], "shouldn't be any fuzz failures")
+	assert.Equal(t, []string{"foo"}, crp.ByStatus[status.Ok].Names(), "failed subject shouldn't have a status")
+}
+
 // TestAnalyse_filtered tests that adding a filtered plan situation to the mock plan works properly.
 func TestAnalyse_filtered(t *testing.T) {
 	t.Parallel()
@@ -14,7 +14,9 @@ import (
 	"github.com/c4-project/c4t/internal/mutation"
 
 	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
 
+	"github.com/c4-project/c4t/internal/subject/confirm"
 	"github.com/c4-project/c4t/internal/subject/status"
 
 	"github.com/c4-project/c4t/internal/model/service/compiler"
@@ -33,11 +35,18 @@ type Analysis struct {
 	// Compilers maps each compiler ID (or full-ID, depending on configuration) to an analysis of that compiler.
 	Compilers map[id.ID]Compiler
 
+	// Confirmations counts the runs given each confirmation verdict.
+	// Runs that have not been through confirmation are not counted.
+	Confirmations map[confirm.Verdict]int
+
 	// Flags aggregates all flags found during the analysis.
 	Flags status.Flag
 
 	// Mutation, if non-nil, contains information about mutation testing done over this plan.
 	Mutation mutation.Analysis
+
+	// StageFailures maps each stage to the corpus of subjects that it failed to process.
+	StageFailures map[stage.Stage]corpus.Corpus
 }
 
 // Compiler represents information about a compiler in a corpus analysis.
@@ -62,10 +71,12 @@ type Compiler struct {
 
 func newAnalysis(p *plan.Plan) *Analysis {
 	return &Analysis{
-		Plan:      p,
-		ByStatus:  make(map[status.Status]corpus.Corpus, status.Last),
-		Compilers: make(map[id.ID]Compiler, len(p.Compilers)),
-		Mutation:  make(mutation.Analysis),
+		Plan:          p,
+		ByStatus:      make(map[status.Status]corpus.Corpus, status.Last),
+		Compilers:     make(map[id.ID]Compiler, len(p.Compilers)),
+		Confirmations: make(map[confirm.Verdict]int),
+		Mutation:      make(mutation.Analysis),
+		StageFailures: make(map[stage.Stage]corpus.Corpus),
 	}
 }
 
@@ -89,6 +100,18 @@ func (a *Analysis) String() string {
 		_, _ = fmt.Fprintf(&sb, "%d %s", l, i.String())
 	}
 
+	for i := stage.Plan; i <= stage.Last; i++ {
+		l := len(a.StageFailures[i])
+		if l == 0 {
+			continue
+		}
+		if !first {
+			sb.WriteString(", ")
+		}
+		first = false
+		_, _ = fmt.Fprintf(&sb, "%d failed at %s", l, i.String())
+	}
+
 	return sb.String()
 }
 
@@ -97,12 +120,17 @@ func (a *Analysis) HasFlagged() bool {
 	return a.Flags.MatchesStatus(status.Flagged)
 }
 
+// HasForbidden tests whether an analysis has cases exhibiting states forbidden by the reference model.
+func (a *Analysis) HasForbidden() bool {
+	return a.Flags.MatchesStatus(status.Forbidden)
+}
+
 // HasFailures tests whether an analysis has failure cases.
 func (a *Analysis) HasFailures() bool {
 	return a.Flags.MatchesAny(status.FlagFail)
 }
 
-// HasBadOutcomes tests whether an analysis has any bad (flagged, failed, or timed-out) cases.
+// HasBadOutcomes tests whether an analysis has any bad (flagged, forbidden, failed, or timed-out) cases.
 func (a *Analysis) HasBadOutcomes() bool {
 	return a.Flags.MatchesAny(status.FlagBad)
 }
@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/plan/stage"
 
 	"github.com/c4-project/c4t/internal/subject/status"
 
@@ -27,11 +28,15 @@ func ExampleAnalysis_String() {
 			status.RunFail:        corpus.New("foobaz", "barbaz"),
 			status.RunTimeout:     corpus.New(),
 		},
+		StageFailures: map[stage.Stage]corpus.Corpus{
+			stage.Fuzz: corpus.New("foo_1", "bar_2"),
+			stage.Lift: corpus.New("baz"),
+		},
 	}
 	fmt.Println(&c)
 
 	// Output:
-	// 4 Ok, 5 Filtered, 1 Flagged, 3 CompileFail, 2 RunFail
+	// 4 Ok, 5 Filtered, 1 Flagged, 3 CompileFail, 2 RunFail, 2 failed at Fuzz, 1 failed at Lift
 }
 
 // ExampleAnalysis_HasFlagged is a runnable example for Analysis.HasFlagged.
@@ -52,6 +57,23 @@ func ExampleAnalysis_HasFlagged() {
 	// flagged: true
 }
 
+// ExampleAnalysis_HasForbidden is a runnable example for Analysis.HasForbidden.
+func ExampleAnalysis_HasForbidden() {
+	var empty analysis.Analysis
+	fmt.Println("empty:", empty.HasForbidden())
+
+	flagged := analysis.Analysis{Flags: status.FlagFlagged}
+	fmt.Println("flagged:", flagged.HasForbidden())
+
+	forbidden := analysis.Analysis{Flags: status.FlagForbidden}
+	fmt.Println("forbidden:", forbidden.HasForbidden())
+
+	// Output:
+	// empty: false
+	// flagged: false
+	// forbidden: true
+}
+
 // ExampleAnalysis_HasFailures is a runnable example for Analysis.HasFailures.
 func ExampleAnalysis_HasFailures() {
 	var empty analysis.Analysis
@@ -105,6 +127,9 @@ func ExampleAnalysis_HasBadOutcomes() {
 	flags := analysis.Analysis{Flags: status.FlagFlagged}
 	fmt.Println("flagged:", flags.HasBadOutcomes())
 
+	forbs := analysis.Analysis{Flags: status.FlagForbidden}
+	fmt.Println("forbidden:", forbs.HasBadOutcomes())
+
 	filts := analysis.Analysis{Flags: status.FlagFiltered}
 	fmt.Println("filtered:", filts.HasBadOutcomes())
 
@@ -115,5 +140,6 @@ func ExampleAnalysis_HasBadOutcomes() {
 	// compiler timeouts: true
 	// run timeouts: true
 	// flagged: true
+	// forbidden: true
 	// filtered: false
 }
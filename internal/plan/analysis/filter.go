@@ -27,7 +27,8 @@ import (
 type Filter struct {
 	// Style is a glob identifier that selects a particular compiler style.
 	Style id.ID `yaml:"style"`
-	// MajorVersionBelow is a lower bound on the major version of the compiler, if set to a positive number.
+	// MajorVersionBelow, if set to a positive number, restricts the filter to compilers whose major version is below it.
+	// Compilers with unknown versions always satisfy this restriction.
 	MajorVersionBelow int `yaml:"major_version_below,omitempty"`
 	// ErrorPattern is an uncompiled regexp that selects a particular phrase in a compiler error.
 	ErrorPattern string `yaml:"error_pattern,omitempty"`
@@ -101,10 +102,21 @@ func (f Filter) Filter(ci compiler.Instance, log string) (bool, error) {
 	if err != nil || !styleMatch {
 		return false, err
 	}
-	// TODO(@MattWindsor91): compiler versions
+	if !f.filterVersion(ci.Version) {
+		return false, nil
+	}
 	return f.filterCompilerLog(log)
 }
 
+// filterVersion checks whether v satisfies this filter's version bound.
+// If we don't know v, we assume it does, so that filters keep working on plans made without version probing.
+func (f Filter) filterVersion(v *compiler.Version) bool {
+	if f.MajorVersionBelow <= 0 || v == nil {
+		return true
+	}
+	return v.Major < f.MajorVersionBelow
+}
+
 func (f Filter) filterCompilerLog(log string) (bool, error) {
 	if f.compiledPattern == nil {
 		return false, errors.New("filter was not compiled")
@@ -76,6 +76,18 @@ func TestFilterSet_FilteredStatus(t *testing.T) {
 			inStatus: status.Ok,
 			want:     status.Filtered,
 		},
+		"filtering on a new enough compiler": {
+			inComp:   newGcc(4),
+			inLog:    "foo error: invalid memory model for ‘__atomic_exchange’ bar",
+			inStatus: status.CompileFail,
+			want:     status.CompileFail,
+		},
+		"filtering on an old compiler": {
+			inComp:   newGcc(3),
+			inLog:    "foo error: invalid memory model for ‘__atomic_exchange’ bar",
+			inStatus: status.CompileFail,
+			want:     status.Filtered,
+		},
 		"no filters": {
 			fsOverride: analysis.FilterSet{},
 			inComp:     compiler.MockX86Gcc(),
@@ -117,3 +129,10 @@ func TestFilterSet_FilteredStatus(t *testing.T) {
 		})
 	}
 }
+
+// newGcc makes a mock GCC instance with the given major version.
+func newGcc(major int) compiler.Instance {
+	c := compiler.MockX86Gcc()
+	c.Version = &compiler.Version{Major: major, Minor: 1}
+	return c
+}
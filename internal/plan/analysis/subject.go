@@ -16,6 +16,7 @@ import (
 	"github.com/c4-project/c4t/internal/model/service/compiler"
 
 	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/confirm"
 
 	"github.com/c4-project/c4t/internal/subject/status"
 
@@ -25,6 +26,7 @@ import (
 // subjectAnalysis holds the result of performing a single analysis on one subject.
 type subjectAnalysis struct {
 	flags        status.Flag
+	confirms     map[confirm.Verdict]int
 	sub          subject.Named
 	cflags       map[id.ID]status.Flag
 	ctimes       map[id.ID][]time.Duration
@@ -35,12 +37,13 @@ type subjectAnalysis struct {
 
 func newSubjectAnalysis(s subject.Named) subjectAnalysis {
 	return subjectAnalysis{
-		flags:  0,
-		cflags: map[id.ID]status.Flag{},
-		clogs:  map[id.ID]string{},
-		ctimes: map[id.ID][]time.Duration{},
-		rtimes: map[id.ID][]time.Duration{},
-		sub:    s,
+		flags:    0,
+		confirms: map[confirm.Verdict]int{},
+		cflags:   map[id.ID]status.Flag{},
+		clogs:    map[id.ID]string{},
+		ctimes:   map[id.ID][]time.Duration{},
+		rtimes:   map[id.ID][]time.Duration{},
+		sub:      s,
 	}
 }
 
@@ -92,6 +95,9 @@ func (c *subjectAnalysis) classifyRun(cid id.ID, r *compilation.RunResult) {
 	if !(c.cflags[cid].MatchesStatus(status.Filtered)) {
 		c.logCompileStatus(cid, r.Status)
 	}
+	if r.Confirmation != confirm.Unchecked {
+		c.confirms[r.Confirmation]++
+	}
 
 	c.rspan.Union(r.Timespan)
 	if d := r.Timespan.Duration(); d != 0 && r.Status.CountsForTiming() {
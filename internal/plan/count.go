@@ -8,12 +8,14 @@ package plan
 // MaxNumRecipes counts the upper bound on the number of recipes that need producing for this plan.
 // The actual number of recipes may be lower if there is sharing between architectures (which, at time of writing,
 // is not yet implemented).
+//
+// Subjects that a stage has failed to process don't count, as no further stage processes them.
 func (p *Plan) MaxNumRecipes() int {
-	return len(p.Arches()) * len(p.Corpus)
+	return len(p.Arches()) * len(p.Corpus.Viable())
 }
 
 // NumExpCompilations counts the expected amount of compilations that will be produced on this plan.
-// It does not actually count the number of compilations present in the plan.
+// It does not actually count the number of compilations present in the plan, and skips failed subjects.
 func (p *Plan) NumExpCompilations() int {
-	return len(p.Compilers) * len(p.Corpus)
+	return len(p.Compilers) * len(p.Corpus.Viable())
 }
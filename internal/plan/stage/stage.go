@@ -46,6 +46,12 @@ const (
 	// Run is a sub-stage of Mach, corresponding to running the compiled binaries in a plan.
 	Run
 
+	// Oracle is the optional stage corresponding to checking run observations against a reference model.
+	Oracle
+
+	// Confirm is the optional stage corresponding to re-running bad results to see whether they reproduce.
+	Confirm
+
 	// Analyse is the optional stage corresponding to post-processing an invoked plan.
 	// Unlike other stages, it isn't logged in the plan file, and can be repeated.
 	Analyse
@@ -53,8 +59,20 @@ const (
 	// SetCompiler is the stage corresponding to manually setting a compiler.
 	SetCompiler
 
+	// Reduce is the stage corresponding to shrinking a bad subject by replaying cut-down fuzzer traces.
+	Reduce
+
+	// Bisect is the stage corresponding to finding the optimisation passes needed to reproduce a bad run.
+	Bisect
+
+	// Regress is the optional stage corresponding to mixing previously saved subjects back into a corpus.
+	Regress
+
+	// Transform is the optional stage corresponding to running an external transformer over each subject in a corpus.
+	Transform
+
 	// Last points to the last stage in the enumeration.
-	Last = SetCompiler
+	Last = Transform
 )
 
 //go:generate stringer -type Stage
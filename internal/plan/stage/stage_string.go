@@ -17,13 +17,19 @@ func _() {
 	_ = x[Mach-6]
 	_ = x[Compile-7]
 	_ = x[Run-8]
-	_ = x[Analyse-9]
-	_ = x[SetCompiler-10]
+	_ = x[Oracle-9]
+	_ = x[Confirm-10]
+	_ = x[Analyse-11]
+	_ = x[SetCompiler-12]
+	_ = x[Reduce-13]
+	_ = x[Bisect-14]
+	_ = x[Regress-15]
+	_ = x[Transform-16]
 }
 
-const _Stage_name = "UnknownPlanPerturbFuzzLiftInvokeMachCompileRunAnalyseSetCompiler"
+const _Stage_name = "UnknownPlanPerturbFuzzLiftInvokeMachCompileRunOracleConfirmAnalyseSetCompilerReduceBisectRegressTransform"
 
-var _Stage_index = [...]uint8{0, 7, 11, 18, 22, 26, 32, 36, 43, 46, 53, 64}
+var _Stage_index = [...]uint8{0, 7, 11, 18, 22, 26, 32, 36, 43, 46, 52, 59, 66, 77, 83, 89, 96, 105}
 
 func (i Stage) String() string {
 	if i >= Stage(len(_Stage_index)-1) {
@@ -42,9 +42,15 @@ func ExampleStage_String() {
 	// Mach
 	// Compile
 	// Run
+	// Oracle
+	// Confirm
 	// Analyse
 	// SetCompiler
-	// Stage(11)
+	// Reduce
+	// Bisect
+	// Regress
+	// Transform
+	// Stage(17)
 }
 
 // ExampleStage_MarshalJSON is a runnable example for MarshalJSON.
@@ -63,8 +69,14 @@ func ExampleStage_MarshalJSON() {
 	// "Mach"
 	// "Compile"
 	// "Run"
+	// "Oracle"
+	// "Confirm"
 	// "Analyse"
 	// "SetCompiler"
+	// "Reduce"
+	// "Bisect"
+	// "Regress"
+	// "Transform"
 }
 
 // TestStage_MarshalJSON_roundTrip tests Op's marshalling and unmarshalling by round-trip.
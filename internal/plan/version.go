@@ -11,10 +11,34 @@ type Version uint32
 // CurrentVer is the current plan version.
 // It changes when the interface between various bits of the tester (generally manifested within the plan version)
 // changes.
-const CurrentVer Version = 2021_02_19
+const CurrentVer Version = 2021_03_22
 
 // Version history since 2020_05_29:
 //
+// 2021_03_22: New optional Regress stage, which replaces a share of the corpus with subjects from the machine's
+//             regression corpus; these have names starting with "regress_", and paths pointing into the regression
+//             store.
+// 2021_03_21: Compilers can carry a "flags" key containing a pool of "optional" flags and flag "groups".  Compiler
+//             instances can carry a "selected_flags" key listing the extra flags that the perturber picked from that
+//             pool.
+// 2021_03_20: Compiler instances can carry a "version" key, containing the "major", "minor", and "patch" version probed
+//             from the compiler on the machine node.
+// 2021_03_19: New Bisect stage, recorded by plans containing the rerun of a compilation with its smallest reproducing
+//             set of optimisation passes.
+// 2021_03_18: New Reduce stage, recorded by plans that the reducer has shrunk.
+// 2021_03_17: New optional Confirm stage.  Machine quantities have a new "confirm" set.  Run results can carry a
+//             "confirmation" key containing the verdict ("NotReproduced", "Intermittent", or "Reproducible") of
+//             rerunning them.
+// 2021_03_16: Machine-node quantities have a new "repeats" key.  Run results can carry a "repeats" key listing the
+//             status of each repeat of a repeated run; their observation is then the merger of every repeat's
+//             observation, and their status the most severe repeat status.
+// 2021_03_15: Subjects can carry a "failure" key recording the stage (at present, Fuzz or Lift) that failed to process
+//             them, whether it timed out, and its error.  Later stages skip failed subjects.  Machine quantities have
+//             a new "lift" set, and fuzz and lift quantities have a per-subject "timeout".
+// 2021_03_08: New optional Transform stage.  Subjects can carry a "transform" key containing the litmus test produced by
+//             an external transformer; when present, this is the subject's best litmus test.
+// 2021_03_01: New optional Oracle stage.  Subjects can carry an "oracle" key containing the observation produced by a
+//             reference model, and runs exhibiting states that the model forbids have the new "Forbidden" status.
 // 2021_02_19: Everything tracking time plus duration has been standardised to take a "time_span" key; this contains a
 //             "start" time and an "end" time.  Currently, both can be in different timezones.
 //             Mutation analysis has changed to associate selections, hits, and kills with such timespans.
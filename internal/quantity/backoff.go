@@ -0,0 +1,68 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity
+
+import (
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+)
+
+// BackoffSet contains configurable quantities for how the director recovers from errored cycles on a machine.
+type BackoffSet struct {
+	// Initial is the delay after the first of a run of errored cycles; each consecutive error doubles it.
+	Initial Timeout `toml:"initial,omitzero" json:"initial,omitempty"`
+
+	// Max, if active, caps the delay between errored cycles.
+	Max Timeout `toml:"max,omitzero" json:"max,omitempty"`
+
+	// Jitter is the proportion, between 0 and 1, of each delay that may be randomly shaved off, so that machines that
+	// fail together don't retry in lockstep.
+	Jitter float64 `toml:"jitter,omitzero" json:"jitter,omitempty"`
+
+	// BreakAfter is the number of consecutive errored cycles after which the director parks the machine.
+	// If non-positive, the director never parks machines, and keeps retrying at the maximum delay.
+	BreakAfter int `toml:"break_after,omitzero" json:"break_after,omitempty"`
+
+	// ProbeInterval is the interval at which the director probes the health of a parked machine.
+	ProbeInterval Timeout `toml:"probe_interval,omitzero" json:"probe_interval,omitempty"`
+}
+
+// Override substitutes any quantities in new that are non-zero for those in this set.
+func (q *BackoffSet) Override(new BackoffSet) {
+	GenericOverride(q, new)
+}
+
+// Log logs q to l.
+func (q *BackoffSet) Log(l *log.Logger) {
+	if q.Initial.IsActive() {
+		l.Printf("backing off from %s", q.Initial)
+	}
+	if q.Max.IsActive() {
+		l.Printf("backing off by at most %s", q.Max)
+	}
+	if 0 < q.BreakAfter {
+		l.Println("parking machine after", stringhelp.PluralQuantity(q.BreakAfter, "errored cycle", "", "s"))
+		l.Printf("probing parked machine every %s", q.ProbeInterval)
+	}
+}
+
+// Delay gets the delay to wait after the nerrs-th consecutive errored cycle, using rng (if non-nil) to apply jitter.
+func (q *BackoffSet) Delay(nerrs int, rng *rand.Rand) time.Duration {
+	d := time.Duration(q.Initial)
+	for i := 1; i < nerrs && (!q.Max.IsActive() || d < time.Duration(q.Max)); i++ {
+		d *= 2
+	}
+	if q.Max.IsActive() && time.Duration(q.Max) < d {
+		d = time.Duration(q.Max)
+	}
+	if rng != nil && 0 < q.Jitter {
+		d -= time.Duration(float64(d) * q.Jitter * rng.Float64())
+	}
+	return d
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c4-project/c4t/internal/quantity"
+)
+
+// ExampleBackoffSet_Delay is a runnable example for BackoffSet.Delay.
+func ExampleBackoffSet_Delay() {
+	q := quantity.BackoffSet{
+		Initial: quantity.Timeout(5 * time.Second),
+		Max:     quantity.Timeout(time.Minute),
+	}
+	for i := 1; i <= 6; i++ {
+		fmt.Println(i, q.Delay(i, nil))
+	}
+
+	// Output:
+	// 1 5s
+	// 2 10s
+	// 3 20s
+	// 4 40s
+	// 5 1m0s
+	// 6 1m0s
+}
+
+// TestBackoffSet_Delay_jitter tests that jittered delays stay within the expected bounds.
+func TestBackoffSet_Delay_jitter(t *testing.T) {
+	t.Parallel()
+
+	q := quantity.BackoffSet{
+		Initial: quantity.Timeout(10 * time.Second),
+		Max:     quantity.Timeout(time.Minute),
+		Jitter:  0.5,
+	}
+	rng := rand.New(rand.NewSource(0))
+	for i := 1; i <= 100; i++ {
+		nerrs := i%5 + 1
+		want := q.Delay(nerrs, nil)
+		got := q.Delay(nerrs, rng)
+		assert.LessOrEqual(t, int64(got), int64(want), "jitter shouldn't lengthen delay")
+		assert.GreaterOrEqual(t, int64(got), int64(want/2), "jitter shouldn't shorten delay by more than its proportion")
+	}
+}
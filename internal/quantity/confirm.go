@@ -0,0 +1,33 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity
+
+import (
+	"log"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+)
+
+// ConfirmSet contains configurable quantities for the confirmer.
+type ConfirmSet struct {
+	// NRepeats is the number of times each bad run should be re-run to confirm it.
+	// If zero, no confirmation takes place.
+	NRepeats int `toml:"repeats,omitzero" json:"repeats,omitempty"`
+}
+
+// Override substitutes any quantities in new that are non-zero for those in this set.
+func (q *ConfirmSet) Override(new ConfirmSet) {
+	GenericOverride(q, new)
+}
+
+// Log logs q to l.
+func (q *ConfirmSet) Log(l *log.Logger) {
+	if q.NRepeats <= 0 {
+		l.Println("not confirming bad runs")
+		return
+	}
+	l.Println("re-running each bad run", stringhelp.PluralQuantity(q.NRepeats, "time", "", "s"))
+}
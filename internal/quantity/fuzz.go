@@ -22,6 +22,10 @@ type FuzzSet struct {
 
 	// NWorkers is the number of workers to use when fuzzing.
 	NWorkers int `toml:"workers,omitzero" json:"num_workers,omitempty"`
+
+	// Timeout is the timeout for each individual fuzzing of a subject.
+	// A subject whose fuzzing times out is marked as failed, rather than failing the whole fuzz stage.
+	Timeout Timeout `toml:"timeout,omitzero" json:"timeout,omitempty"`
 }
 
 // Override substitutes any quantities in new that are non-zero for those in this set.
@@ -34,4 +38,5 @@ func (q *FuzzSet) Log(l *log.Logger) {
 	LogWorkers(l, q.NWorkers)
 	l.Println("fuzzing each subject", stringhelp.PluralQuantity(q.SubjectCycles, "time", "", "s"))
 	l.Println("target corpus size:", stringhelp.PluralQuantity(q.CorpusSize, "subject", "", "s"))
+	q.Timeout.Log(l)
 }
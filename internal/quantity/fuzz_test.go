@@ -7,6 +7,7 @@ package quantity_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/c4-project/c4t/internal/quantity"
 )
@@ -19,13 +20,16 @@ func ExampleFuzzSet_Override() {
 	}
 	q2 := quantity.FuzzSet{
 		SubjectCycles: 42,
+		Timeout:       quantity.Timeout(10 * time.Second),
 	}
 	q1.Override(q2)
 
 	fmt.Println("corpus size:   ", q1.CorpusSize)
 	fmt.Println("subject cycles:", q1.SubjectCycles)
+	fmt.Println("timeout:       ", q1.Timeout)
 
 	// Output:
 	// corpus size:    27
 	// subject cycles: 42
+	// timeout:        10s
 }
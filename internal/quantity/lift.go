@@ -0,0 +1,29 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity
+
+import "log"
+
+// LiftSet represents the part of a configuration that holds various tunable parameters for the lifter.
+type LiftSet struct {
+	// NWorkers is the number of workers to use when lifting.
+	NWorkers int `toml:"workers,omitzero" json:"num_workers,omitempty"`
+
+	// Timeout is the timeout for each individual lifting of a subject to a particular architecture.
+	// A subject whose lifting times out is marked as failed, rather than failing the whole lift stage.
+	Timeout Timeout `toml:"timeout,omitzero" json:"timeout,omitempty"`
+}
+
+// Override substitutes any quantities in new that are non-zero for those in this set.
+func (q *LiftSet) Override(new LiftSet) {
+	GenericOverride(q, new)
+}
+
+// Log logs q to l.
+func (q *LiftSet) Log(l *log.Logger) {
+	LogWorkers(l, q.NWorkers)
+	q.Timeout.Log(l)
+}
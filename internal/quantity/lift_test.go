@@ -0,0 +1,32 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c4-project/c4t/internal/quantity"
+)
+
+// ExampleLiftSet_Override is a runnable example for LiftSet.Override.
+func ExampleLiftSet_Override() {
+	q1 := quantity.LiftSet{
+		NWorkers: 20,
+		Timeout:  quantity.Timeout(1 * time.Minute),
+	}
+	q2 := quantity.LiftSet{
+		Timeout: quantity.Timeout(2 * time.Minute),
+	}
+	q1.Override(q2)
+
+	fmt.Println("workers:", q1.NWorkers)
+	fmt.Println("timeout:", q1.Timeout)
+
+	// Output:
+	// workers: 20
+	// timeout: 2m0s
+}
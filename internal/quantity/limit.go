@@ -0,0 +1,46 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity
+
+import (
+	"log"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+)
+
+// LimitSet contains configurable limits on how long the director tests a machine.
+//
+// Once a machine reaches any of its limits, the director stops starting new cycles on it, but lets any cycle already
+// in progress finish.
+type LimitSet struct {
+	// Budget is the wall-clock time, from the start of the campaign, after which the director stops testing the machine.
+	Budget Timeout `toml:"budget,omitzero" json:"budget,omitempty"`
+
+	// Cycles is the number of cycles after which the director stops testing the machine.
+	// Errored cycles count towards this limit, so a machine whose cycles keep erroring still stops.
+	Cycles int `toml:"cycles,omitzero" json:"cycles,omitempty"`
+
+	// Flagged is the number of flagged or forbidden subjects after which the director stops testing the machine.
+	Flagged int `toml:"flagged,omitzero" json:"flagged,omitempty"`
+}
+
+// Override substitutes any quantities in new that are non-zero for those in this set.
+func (q *LimitSet) Override(new LimitSet) {
+	GenericOverride(q, new)
+}
+
+// Log logs q to l.
+func (q *LimitSet) Log(l *log.Logger) {
+	if q.Budget.IsActive() {
+		l.Printf("stopping after %s", q.Budget)
+	}
+	if 0 < q.Cycles {
+		l.Println("stopping after", stringhelp.PluralQuantity(q.Cycles, "cycle", "", "s"))
+	}
+	if 0 < q.Flagged {
+		l.Println("stopping after", stringhelp.PluralQuantity(q.Flagged, "flagged or forbidden subject", "", "s"))
+	}
+}
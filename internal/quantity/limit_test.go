@@ -0,0 +1,35 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package quantity_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c4-project/c4t/internal/quantity"
+)
+
+// ExampleLimitSet_Override is a runnable example for LimitSet.Override.
+func ExampleLimitSet_Override() {
+	q1 := quantity.LimitSet{
+		Budget: quantity.Timeout(24 * time.Hour),
+		Cycles: 1000,
+	}
+	q2 := quantity.LimitSet{
+		Cycles:  50,
+		Flagged: 10,
+	}
+	q1.Override(q2)
+
+	fmt.Println("budget: ", q1.Budget)
+	fmt.Println("cycles: ", q1.Cycles)
+	fmt.Println("flagged:", q1.Flagged)
+
+	// Output:
+	// budget:  24h0m0s
+	// cycles:  50
+	// flagged: 10
+}
@@ -7,32 +7,82 @@ package quantity
 
 import (
 	"log"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
 )
 
 // MachineSet contains overridable quantities for each stage operating on a particular machine.
 // Often, but not always, these quantities will be shared between machines.
 type MachineSet struct {
+	// Instances is the number of instances the director runs concurrently against each machine.
+	// Each instance runs its own cycles, with its own scratch directories; zero means one instance.
+	Instances int `toml:"instances,omitzero" json:"instances,omitempty"`
+	// Lookahead is the number of cycles each instance may prepare (perturb, fuzz, and lift) while the machine runs
+	// an earlier cycle.  Each cycle of lookahead needs its own scratch buffer; zero means no pipelining.
+	//
+	// A cycle makes its choices (including bandit and source-weighted choices, which draw on past yields) when it
+	// starts preparing, and so doesn't see the results of the up to Lookahead cycles ahead of it.
+	Lookahead int `toml:"lookahead,omitzero" json:"lookahead,omitempty"`
+	// Backoff is the quantity set for the director's handling of errored cycles.
+	Backoff BackoffSet `toml:"backoff,omitzero" json:"backoff,omitempty"`
+	// Confirm is the quantity set for the confirm stage.
+	Confirm ConfirmSet `toml:"confirm,omitzero" json:"confirm,omitempty"`
 	// Fuzz is the quantity set for the fuzz stage.
 	Fuzz FuzzSet `toml:"fuzz,omitzero" json:"fuzz,omitempty"`
+	// Limits is the quantity set for the director's campaign limits.
+	Limits LimitSet `toml:"limits,omitzero" json:"limits,omitempty"`
+	// Lift is the quantity set for the lift stage.
+	Lift LiftSet `toml:"lift,omitzero" json:"lift,omitempty"`
 	// Mach is the quantity set for the machine-local stage, as well as any machine-local stages run remotely.
 	Mach MachNodeSet `toml:"mach,omitzero" json:"mach,omitempty"`
+	// Oracle is the quantity set for the oracle stage.
+	Oracle BatchSet `toml:"oracle,omitzero" json:"oracle,omitempty"`
 	// Perturb is the quantity set for the planner stage.
 	Perturb PerturbSet `toml:"perturb,omitzero" json:"perturb,omitempty"`
 }
 
 // Log logs q to l.
 func (q *MachineSet) Log(l *log.Logger) {
+	if 0 < q.Instances {
+		l.Println("[Instances]")
+		l.Println("running", stringhelp.PluralQuantity(q.Instances, "instance", "", "s"), "per machine")
+	}
+	if 0 < q.Lookahead {
+		l.Println("[Lookahead]")
+		l.Println("preparing up to", stringhelp.PluralQuantity(q.Lookahead, "cycle", "", "s"), "ahead")
+	}
 	l.Println("[Perturb]")
 	q.Perturb.Log(l)
 	l.Println("[Fuzz]")
 	q.Fuzz.Log(l)
+	l.Println("[Lift]")
+	q.Lift.Log(l)
 	l.Println("[Mach]")
 	q.Mach.Log(l)
+	l.Println("[Oracle]")
+	q.Oracle.Log(l)
+	l.Println("[Confirm]")
+	q.Confirm.Log(l)
+	l.Println("[Backoff]")
+	q.Backoff.Log(l)
+	l.Println("[Limits]")
+	q.Limits.Log(l)
 }
 
 // Override substitutes any quantities in new that are non-zero for those in this set.
 func (q *MachineSet) Override(new MachineSet) {
+	if new.Instances != 0 {
+		q.Instances = new.Instances
+	}
+	if new.Lookahead != 0 {
+		q.Lookahead = new.Lookahead
+	}
 	q.Perturb.Override(new.Perturb)
 	q.Fuzz.Override(new.Fuzz)
+	q.Lift.Override(new.Lift)
 	q.Mach.Override(new.Mach)
+	q.Oracle.Override(new.Oracle)
+	q.Confirm.Override(new.Confirm)
+	q.Backoff.Override(new.Backoff)
+	q.Limits.Override(new.Limits)
 }
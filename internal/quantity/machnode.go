@@ -7,6 +7,8 @@ package quantity
 
 import (
 	"log"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
 )
 
 // MachNodeSet contains the tunable quantities for both batch-compiler and batch-runner.
@@ -40,11 +42,19 @@ type BatchSet struct {
 	// NWorkers is the number of parallel run workers that should be spawned.
 	// Anything less than or equal to 1 will sequentialise the run.
 	NWorkers int `toml:"workers,omitzero" json:"workers,omitempty"`
+
+	// NRepeats is the number of times each job should be repeated.
+	// This is only meaningful for runners, where the observations of each repeat are merged.
+	// Anything less than or equal to 1 will run each job once.
+	NRepeats int `toml:"repeats,omitzero" json:"repeats,omitempty"`
 }
 
 // Log logs this quantity set to l.
 func (q *BatchSet) Log(l *log.Logger) {
 	LogWorkers(l, q.NWorkers)
+	if 1 < q.NRepeats {
+		l.Println("repeating each job", stringhelp.PluralQuantity(q.NRepeats, "time", "", "s"))
+	}
 	q.Timeout.Log(l)
 }
 
@@ -54,4 +64,7 @@ func (q *BatchSet) Override(new BatchSet) {
 	if new.NWorkers != 0 {
 		q.NWorkers = new.NWorkers
 	}
+	if new.NRepeats != 0 {
+		q.NRepeats = new.NRepeats
+	}
 }
@@ -26,6 +26,7 @@ func ExampleMachNodeSet_Log() {
 		Runner: quantity.BatchSet{
 			Timeout:  quantity.Timeout(2 * time.Minute),
 			NWorkers: 1,
+			NRepeats: 10,
 		},
 	}
 
@@ -38,6 +39,7 @@ func ExampleMachNodeSet_Log() {
 	// timeout at 1m0s
 	// [Runner]
 	// running across 1 worker
+	// repeating each job 10 times
 	// timeout at 2m0s
 }
 
@@ -81,6 +83,7 @@ func TestMachNodeSet_Override(t *testing.T) {
 				Runner: quantity.BatchSet{
 					Timeout:  quantity.Timeout(1 * time.Minute),
 					NWorkers: 42,
+					NRepeats: 5,
 				},
 			},
 			want: quantity.MachNodeSet{
@@ -91,6 +94,7 @@ func TestMachNodeSet_Override(t *testing.T) {
 				Runner: quantity.BatchSet{
 					Timeout:  quantity.Timeout(1 * time.Minute),
 					NWorkers: 42,
+					NRepeats: 5,
 				},
 			},
 		},
@@ -18,6 +18,27 @@ type PerturbSet struct {
 	// If nonzero, the corpus will be sampled if larger than the size, and an error occurs if the final size is below
 	// that requested.
 	CorpusSize int `toml:"corpus_size,omitzero" json:"corpus_size,omitempty"`
+
+	// CompilerInstances is the maximum number of instances the perturber makes from each compiler on each cycle, when
+	// using a strategy that can make more than one.
+	// If zero, the matrix strategy makes at most DefaultMatrixCompilerInstances, the bandit strategy makes one, and the
+	// pairwise strategy has no maximum, covering every outstanding combination on each cycle.
+	CompilerInstances int `toml:"compiler_instances,omitzero" json:"compiler_instances,omitempty"`
+}
+
+// DefaultMatrixCompilerInstances is the maximum number of instances the matrix strategy makes from each compiler on
+// each cycle if CompilerInstances is zero.
+//
+// The matrix strategy walks the product of every choice, which grows exponentially with the size of any flag pool.
+const DefaultMatrixCompilerInstances = 32
+
+// MatrixCompilerInstances gets the maximum number of instances the matrix strategy makes from each compiler on each
+// cycle.
+func (q *PerturbSet) MatrixCompilerInstances() int {
+	if q.CompilerInstances <= 0 {
+		return DefaultMatrixCompilerInstances
+	}
+	return q.CompilerInstances
 }
 
 // Override substitutes any quantities in new that are non-zero for those in this set.
@@ -26,6 +47,19 @@ func (q *PerturbSet) Override(new PerturbSet) {
 }
 
 // Log logs q to l.
+//
+// How many compiler instances the perturber makes depends on its strategy, so it logs that itself (see
+// LogCompilerInstances).
 func (q *PerturbSet) Log(l *log.Logger) {
 	l.Println("target corpus size:", stringhelp.PluralQuantity(q.CorpusSize, "subject", "", "s"))
 }
+
+// LogCompilerInstances dumps the maximum number of instances per compiler per cycle, n, to the logger l.
+// If n is zero, there is no maximum.
+func LogCompilerInstances(l *log.Logger, n int) {
+	if n <= 0 {
+		l.Println("compiler instances per cycle: every outstanding combination")
+		return
+	}
+	l.Println("compiler instances per cycle: at most", n)
+}
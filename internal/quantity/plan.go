@@ -13,6 +13,10 @@ import (
 type PlanSet struct {
 	// NWorkers is the number of workers to use when probing the corpus.
 	NWorkers int `toml:"workers,omitzero"`
+
+	// RescanInterval is the interval at which the director rescans its input paths for new litmus tests.
+	// If non-positive, the director plans its corpus once at start-up and never rescans.
+	RescanInterval Timeout `toml:"rescan_interval,omitzero"`
 }
 
 // Override substitutes any quantities in new that are non-zero for those in this set.
@@ -23,4 +27,7 @@ func (q *PlanSet) Override(new PlanSet) {
 // Log logs q to l.
 func (q *PlanSet) Log(l *log.Logger) {
 	LogWorkers(l, q.NWorkers)
+	if q.RescanInterval.IsActive() {
+		l.Printf("rescanning inputs every %s", q.RescanInterval)
+	}
 }
@@ -17,10 +17,17 @@ import (
 func ExampleRootSet_Log() {
 	qs := quantity.RootSet{
 		MachineSet: quantity.MachineSet{
+			Instances: 4,
+			Lookahead: 1,
 			Fuzz: quantity.FuzzSet{
 				CorpusSize:    10,
 				SubjectCycles: 5,
 				NWorkers:      4,
+				Timeout:       quantity.Timeout(30 * time.Second),
+			},
+			Lift: quantity.LiftSet{
+				NWorkers: 8,
+				Timeout:  quantity.Timeout(1 * time.Minute),
 			},
 			Mach: quantity.MachNodeSet{
 				Compiler: quantity.BatchSet{
@@ -32,12 +39,30 @@ func ExampleRootSet_Log() {
 					NWorkers: 7,
 				},
 			},
+			Oracle: quantity.BatchSet{
+				Timeout:  quantity.Timeout(1 * time.Minute),
+				NWorkers: 5,
+			},
 			Perturb: quantity.PerturbSet{
 				CorpusSize: 80,
 			},
+			Confirm: quantity.ConfirmSet{
+				NRepeats: 3,
+			},
+			Backoff: quantity.BackoffSet{
+				Initial:       quantity.Timeout(10 * time.Second),
+				Max:           quantity.Timeout(5 * time.Minute),
+				BreakAfter:    5,
+				ProbeInterval: quantity.Timeout(1 * time.Minute),
+			},
+			Limits: quantity.LimitSet{
+				Budget:  quantity.Timeout(12 * time.Hour),
+				Flagged: 100,
+			},
 		},
 		Plan: quantity.PlanSet{
-			NWorkers: 9,
+			NWorkers:       9,
+			RescanInterval: quantity.Timeout(10 * time.Minute),
 		},
 	}
 
@@ -46,12 +71,21 @@ func ExampleRootSet_Log() {
 	// Output:
 	// [Plan]
 	// running across 9 workers
+	// rescanning inputs every 10m0s
+	// [Instances]
+	// running 4 instances per machine
+	// [Lookahead]
+	// preparing up to 1 cycle ahead
 	// [Perturb]
 	// target corpus size: 80 subjects
 	// [Fuzz]
 	// running across 4 workers
 	// fuzzing each subject 5 times
 	// target corpus size: 10 subjects
+	// timeout at 30s
+	// [Lift]
+	// running across 8 workers
+	// timeout at 1m0s
 	// [Mach]
 	// [Compiler]
 	// running across 6 workers
@@ -59,4 +93,17 @@ func ExampleRootSet_Log() {
 	// [Runner]
 	// running across 7 workers
 	// timeout at 2m0s
+	// [Oracle]
+	// running across 5 workers
+	// timeout at 1m0s
+	// [Confirm]
+	// re-running each bad run 3 times
+	// [Backoff]
+	// backing off from 10s
+	// backing off by at most 5m0s
+	// parking machine after 5 errored cycles
+	// probing parked machine every 1m0s
+	// [Limits]
+	// stopping after 12h0m0s
+	// stopping after 100 flagged or forbidden subjects
 }
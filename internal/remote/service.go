@@ -0,0 +1,82 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package remote
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/alessio/shellescape"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/c4-project/c4t/internal/model/service"
+)
+
+// ServiceRunner runs services on a remote machine, by opening a new SSH session for each run.
+type ServiceRunner struct {
+	mr   *MachineRunner
+	outw io.Writer
+	errw io.Writer
+}
+
+// ServiceRunner gets a service runner that runs services through this MachineRunner.
+func (r *MachineRunner) ServiceRunner() *ServiceRunner {
+	return &ServiceRunner{mr: r}
+}
+
+// WithStdout returns a new ServiceRunner with standard output rerouted to w.
+func (s ServiceRunner) WithStdout(w io.Writer) service.Runner {
+	s.outw = w
+	return s
+}
+
+// WithStderr returns a new ServiceRunner with standard error rerouted to w.
+func (s ServiceRunner) WithStderr(w io.Writer) service.Runner {
+	s.errw = w
+	return s
+}
+
+// WithGrace returns s unchanged, as we kill remote commands outright when their context closes.
+func (s ServiceRunner) WithGrace(time.Duration) service.Runner {
+	return s
+}
+
+// Run runs r remotely on context ctx.
+func (s ServiceRunner) Run(ctx context.Context, r service.RunInfo) error {
+	sess, err := s.mr.NewSession()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sess.Close() }()
+
+	sess.Stdout = s.outw
+	sess.Stderr = s.errw
+	if err := sess.Start(Command(r)); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- sess.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		_ = sess.Signal(ssh.SIGKILL)
+		return ctx.Err()
+	}
+}
+
+// Command gets the shell command line that runs r remotely.
+//
+// As many SSH servers refuse to let clients set environment variables, we pass any environment through 'env'.
+func Command(r service.RunInfo) string {
+	inv := r.Invocation()
+	if env := r.EnvStrings(); len(env) != 0 {
+		inv = append(append([]string{"env"}, env...), inv...)
+	}
+	return shellescape.QuoteCommand(inv)
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package remote_test
+
+import (
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/remote"
+)
+
+// ExampleCommand is a runnable example for Command.
+func ExampleCommand() {
+	fmt.Println(remote.Command(*service.NewRunInfo("gcc", "--version")))
+	fmt.Println(remote.Command(service.RunInfo{
+		Cmd:  "c4t-mach",
+		Args: []string{"-d", "my dir"},
+		Env:  map[string]string{"TMPDIR": "/tmp"},
+	}))
+
+	// Output:
+	// gcc --version
+	// env TMPDIR=/tmp c4t-mach -d 'my dir'
+}
@@ -28,8 +28,8 @@ var (
 	// ErrUnknownStyle occurs when we ask the resolver for a backend style of which it isn't aware.
 	ErrUnknownStyle = errors.New("unknown backend style")
 
-	herdArches   = []id.ID{id.ArchC, id.ArchAArch64, id.ArchArm, id.ArchX8664, id.ArchX86, id.ArchPPC}
-	litmusArches = []id.ID{id.ArchC, id.ArchAArch64, id.ArchArm, id.ArchX8664, id.ArchX86, id.ArchPPC}
+	herdArches   = []id.ID{id.ArchC, id.ArchAArch64, id.ArchArm, id.ArchX8664, id.ArchX86, id.ArchPPC, id.ArchRISCV}
+	litmusArches = []id.ID{id.ArchC, id.ArchAArch64, id.ArchArm, id.ArchX8664, id.ArchX86, id.ArchPPC, id.ArchRISCV}
 	// TODO(@MattWindsor91): rmem supports more than this, but needs more work on sanitising/model selection
 	rmemArches = []id.ID{id.ArchAArch64}
 
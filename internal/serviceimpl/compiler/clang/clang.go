@@ -0,0 +1,128 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package clang implements the Clang compiler style.
+//
+// Clang accepts most of GCC's command-line interface, but differs in its optimisation levels, its machine optimisation
+// names on some architectures, and its ability to cross-compile to any supported target triple from one binary.
+package clang
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/machine"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/gcc"
+)
+
+// Clang represents Clang-style compilers, including AppleClang.
+type Clang service.ExtClass
+
+// RunCompiler compiles j using a Clang-friendly invocation.
+func (c Clang) RunCompiler(ctx context.Context, j compiler.Job, sr service.Runner) error {
+	return sr.Run(ctx, c.makeRunInfo(j))
+}
+
+func (c Clang) makeRunInfo(j compiler.Job) service.RunInfo {
+	run := c.DefaultRunInfo
+	if nr := j.CompilerRun(); nr != nil {
+		run.Override(*nr)
+	}
+	run.AppendArgs(Args(j, localHost())...)
+	return run
+}
+
+// Host describes the machine on which Clang runs.
+type Host struct {
+	// GOOS is the Go name of the machine's operating system.
+	GOOS string
+	// Arch is the architecture of the machine, if known.
+	Arch id.ID
+}
+
+var (
+	// hostOnce guards the probing of host.
+	hostOnce sync.Once
+	// host caches the result of localHost.
+	host Host
+)
+
+// localHost gets the host for the local machine, probing it the first time.
+//
+// Compilers run inside the machine-dependent stage, which always runs on the machine under test, so this is the
+// machine that the compiled tests will run on.
+func localHost() Host {
+	hostOnce.Do(func() {
+		host.GOOS = runtime.GOOS
+		// If we can't probe the architecture, we'll just have to target each compiler explicitly.
+		host.Arch, _ = machine.LocalProber().Arch()
+	})
+	return host
+}
+
+// Args computes the arguments to pass to Clang for running job j on host h.
+// It does not take j's run info into consideration, and assumes this has already been done.
+func Args(j compiler.Job, h Host) []string {
+	var args []string
+	if j.Compiler != nil {
+		args = AddTargetArg(args, j.Compiler.Arch, h)
+	}
+	args = gcc.AddStringArg(args, "O", j.SelectedOptName())
+	args = gcc.AddStringArg(args, "m", j.SelectedMOptName())
+	args = append(args, j.SelectedFlags()...)
+	args = gcc.AddKindArg(args, j.Kind)
+	args = append(args, "-o", j.Out)
+	return append(args, j.In...)
+}
+
+// AddTargetArg adds to args a '--target' argument for the architecture arch on host h, if we know the target triple
+// for that combination and it differs from that of the host itself; else, it returns args.
+//
+// Clang targets its host by default, so we only need '--target' when cross-compiling.
+func AddTargetArg(args []string, arch id.ID, h Host) []string {
+	t, ok := Triple(arch, h.GOOS)
+	if !ok {
+		return args
+	}
+	if ht, ok := Triple(h.Arch, h.GOOS); ok && ht == t {
+		return args
+	}
+	return append(args, "--target="+t)
+}
+
+// Probe probes for Clang-style compilers, adding them to target.
+func (c Clang) Probe(ctx context.Context, sr service.Runner, classId id.ID, target compiler.ConfigMap) error {
+	candidates := service.ExtClass(c).ProbeByVersionCommand(ctx, sr, "--version")
+	for k, out := range candidates {
+		v, err := ParseVersion(out)
+		if err != nil {
+			// This is probably a different compiler masquerading as clang (eg, 'cc' pointing to gcc).
+			continue
+		}
+		cid, cfg, err := c.expandProbedCommand(classId, c.DefaultRunInfo.NewIfDifferent(k), v)
+		if err != nil {
+			return err
+		}
+		target[cid.String()] = cfg
+	}
+	return nil
+}
+
+func (c Clang) expandProbedCommand(classId id.ID, run *service.RunInfo, v *Version) (id.ID, compiler.Config, error) {
+	cfg := compiler.Config{Style: classId, Run: run, Arch: v.Arch()}
+	cid, err := c.makeID(run)
+	return cid, cfg, err
+}
+
+func (c Clang) makeID(run *service.RunInfo) (id.ID, error) {
+	if run == nil {
+		return id.TryFromString(c.DefaultRunInfo.Cmd)
+	}
+	return run.SystematicID()
+}
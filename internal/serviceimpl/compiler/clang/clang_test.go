@@ -0,0 +1,176 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package clang_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/model/service/compiler/optlevel"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/clang"
+)
+
+// ExampleArgs is a runnable example for Args.
+func ExampleArgs() {
+	args := clang.Args(
+		*compiler.NewJob(
+			compiler.Exe,
+			&compiler.Instance{
+				SelectedMOpt: "cpu=pwr9",
+				SelectedOpt:  &optlevel.Named{Name: "z"},
+				Compiler:     compiler.Compiler{Arch: id.ArchPPCPOWER9},
+			},
+			"a.out",
+			"foo.c", "bar.c",
+		),
+		clang.Host{GOOS: "linux", Arch: id.ArchX8664},
+	)
+	for _, arg := range args {
+		fmt.Println(arg)
+	}
+
+	// Output:
+	// --target=powerpc64le-linux-gnu
+	// -Oz
+	// -mcpu=pwr9
+	// -o
+	// a.out
+	// foo.c
+	// bar.c
+}
+
+// TestArgs tests Args on various jobs and hosts.
+func TestArgs(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		job  compiler.Job
+		host clang.Host
+		out  []string
+	}{
+		"default": {
+			job:  *compiler.NewJob(compiler.Exe, nil, "a.out", "foo.c"),
+			host: clang.Host{GOOS: "linux"},
+			out:  []string{"-o", "a.out", "foo.c"},
+		},
+		"obj": {
+			job:  *compiler.NewJob(compiler.Obj, nil, "foo.o", "foo.c"),
+			host: clang.Host{GOOS: "linux"},
+			out:  []string{"-c", "-o", "foo.o", "foo.c"},
+		},
+		"cross-arm": {
+			job: *compiler.NewJob(
+				compiler.Exe,
+				&compiler.Instance{SelectedMOpt: "arch=armv7-a", Compiler: compiler.Compiler{Arch: id.ArchArm7}},
+				"a.out",
+				"foo.c",
+			),
+			host: clang.Host{GOOS: "linux"},
+			out:  []string{"--target=armv7a-linux-gnueabihf", "-march=armv7-a", "-o", "a.out", "foo.c"},
+		},
+		"native": {
+			job: *compiler.NewJob(
+				compiler.Exe,
+				&compiler.Instance{SelectedMOpt: "arch=skylake", Compiler: compiler.Compiler{Arch: id.ArchX86Skylake}},
+				"a.out",
+				"foo.c",
+			),
+			host: clang.Host{GOOS: "linux", Arch: id.ArchX8664},
+			out:  []string{"-march=skylake", "-o", "a.out", "foo.c"},
+		},
+		"darwin-arm64": {
+			job: *compiler.NewJob(
+				compiler.Exe,
+				&compiler.Instance{
+					SelectedOpt: &optlevel.Named{Name: "3"},
+					Compiler:    compiler.Compiler{Arch: id.ArchAArch6481},
+				},
+				"a.out",
+				"foo.c",
+			),
+			host: clang.Host{GOOS: "darwin", Arch: id.ArchX8664},
+			out:  []string{"--target=arm64-apple-darwin", "-O3", "-o", "a.out", "foo.c"},
+		},
+		"unknown-os": {
+			job: *compiler.NewJob(
+				compiler.Exe,
+				&compiler.Instance{Compiler: compiler.Compiler{Arch: id.ArchX8664}},
+				"a.out",
+				"foo.c",
+			),
+			host: clang.Host{GOOS: "plan9"},
+			out:  []string{"-o", "a.out", "foo.c"},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, c.out, clang.Args(c.job, c.host))
+		})
+	}
+}
+
+// TestClang_Probe tests probing on a runner that simulates Clang on one command and GCC on another.
+func TestClang_Probe(t *testing.T) {
+	t.Parallel()
+
+	c := clang.Clang{
+		DefaultRunInfo: service.RunInfo{Cmd: "clang"},
+		AltCommands:    []string{"cc"},
+	}
+	sr := versionRunner{versions: map[string]string{
+		"clang": "Ubuntu clang version 14.0.0-1ubuntu1\nTarget: aarch64-unknown-linux-gnu\nThread model: posix\n",
+		"cc":    "cc (Ubuntu 11.2.0-19ubuntu1) 11.2.0\n",
+	}}
+
+	got := compiler.ConfigMap{}
+	require.NoError(t, c.Probe(context.Background(), sr, id.CStyleClang, got), "probing")
+	assert.Equal(t, compiler.ConfigMap{
+		"clang": {Style: id.CStyleClang, Arch: id.ArchAArch64},
+	}, got)
+}
+
+// versionRunner is a service runner that writes a fixed version string for each known command to its stdout.
+type versionRunner struct {
+	versions map[string]string
+	w        io.Writer
+}
+
+func (v versionRunner) WithStdout(w io.Writer) service.Runner {
+	v.w = w
+	return v
+}
+
+func (v versionRunner) WithStderr(io.Writer) service.Runner {
+	return v
+}
+
+func (v versionRunner) WithGrace(time.Duration) service.Runner {
+	return v
+}
+
+func (v versionRunner) Run(_ context.Context, r service.RunInfo) error {
+	ver, ok := v.versions[r.Cmd]
+	if !ok {
+		return errors.New("command not found")
+	}
+	if v.w != nil {
+		_, _ = io.WriteString(v.w, ver)
+	}
+	return nil
+}
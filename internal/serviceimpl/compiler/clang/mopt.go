@@ -0,0 +1,56 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package clang
+
+import (
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/gcc"
+)
+
+// DefaultMOpts adapts the Clang mopts calculation to the interface needed for a compiler.
+func (Clang) DefaultMOpts(c *compiler.Compiler) (stringhelp.Set, error) {
+	return MOpts(c.Arch)
+}
+
+// MOpts gets the default 'm' invocations (march, mcpu, etc.) to consider for compilers with archID arch.
+//
+// Clang understands GCC's march and mcpu names on x86 and Arm, so we defer to GCC's tables there; PowerPC CPU names
+// differ, so we use our own table.
+func MOpts(arch id.ID) (stringhelp.Set, error) {
+	family, variant, subvar := arch.Triple()
+	if family == id.ArchFamilyPPC {
+		return ppcMOpts(variant, subvar)
+	}
+	return gcc.MOpts(arch)
+}
+
+func ppcMOpts(variant string, subvar id.ID) (stringhelp.Set, error) {
+	if variant != id.ArchVariantPPC64LE {
+		return nil, fmt.Errorf("%w: unknown variant: %s", gcc.ErrUnsupportedVariant, variant)
+	}
+
+	set := stringhelp.NewSet("")
+	switch subvar.String() {
+	case id.ArchSubVariantPPCPOWER9:
+		set.Add("cpu=pwr9")
+		fallthrough
+	case id.ArchSubVariantPPCPOWER8:
+		set.Add("cpu=pwr8")
+		fallthrough
+	case id.ArchSubVariantPPCPOWER7:
+		set.Add("cpu=pwr7")
+		fallthrough
+	case "":
+		set.Add("cpu=ppc64le", "cpu=native")
+	default:
+		return nil, fmt.Errorf("%w: unknown subvariant: %s", gcc.ErrUnsupportedVariant, subvar)
+	}
+	return set, nil
+}
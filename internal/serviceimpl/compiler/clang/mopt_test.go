@@ -0,0 +1,61 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package clang_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/clang"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/gcc"
+)
+
+// TestMOpts tests MOpts on various architectures.
+func TestMOpts(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		arch id.ID
+		out  stringhelp.Set
+		err  error
+	}{
+		"power9": {
+			arch: id.ArchPPCPOWER9,
+			out:  stringhelp.NewSet("", "cpu=pwr9", "cpu=pwr8", "cpu=pwr7", "cpu=ppc64le", "cpu=native"),
+		},
+		"ppc64le": {
+			arch: id.ArchPPC64LE,
+			out:  stringhelp.NewSet("", "cpu=ppc64le", "cpu=native"),
+		},
+		"ppc-bad-variant": {
+			arch: id.ArchPPC,
+			err:  gcc.ErrUnsupportedVariant,
+		},
+		"x86-64": {
+			arch: id.ArchX8664,
+			out:  stringhelp.NewSet("", "arch=x86-64", "arch=native"),
+		},
+		"empty": {
+			err: gcc.ErrMalformedArchId,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			out, err := clang.MOpts(c.arch)
+			if !testhelp.ExpectErrorIs(t, err, c.err, "getting mopts") || err != nil {
+				return
+			}
+			assert.Equal(t, c.out, out, "wrong mopts")
+		})
+	}
+}
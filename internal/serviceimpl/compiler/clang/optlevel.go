@@ -0,0 +1,92 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package clang
+
+import (
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/model/service/compiler/optlevel"
+)
+
+var (
+	// OptLevels contains the optimisation levels known to exist on Clang and AppleClang.
+	OptLevels = map[string]optlevel.Level{
+		// no optimisation
+		"0": {
+			Optimises:       false,
+			Bias:            optlevel.BiasDebug,
+			BreaksStandards: false,
+		},
+		// mild optimisation
+		"1": {
+			Optimises:       true,
+			Bias:            optlevel.BiasSpeed,
+			BreaksStandards: false,
+		},
+		// moderate optimisation
+		"2": {
+			Optimises:       true,
+			Bias:            optlevel.BiasSpeed,
+			BreaksStandards: false,
+		},
+		// heavy optimisation
+		"3": {
+			Optimises:       true,
+			Bias:            optlevel.BiasSpeed,
+			BreaksStandards: false,
+		},
+		// standards-bending optimisation
+		"fast": {
+			Optimises:       true,
+			Bias:            optlevel.BiasSpeed,
+			BreaksStandards: true,
+		},
+		// optimise for size
+		"s": {
+			Optimises:       true,
+			Bias:            optlevel.BiasSize,
+			BreaksStandards: false,
+		},
+		// optimise aggressively for size; unlike GCC, Clang has always had this
+		"z": {
+			Optimises:       true,
+			Bias:            optlevel.BiasSize,
+			BreaksStandards: false,
+		},
+		// on Clang, currently the same as -O1
+		"g": {
+			Optimises:       true,
+			Bias:            optlevel.BiasDebug,
+			BreaksStandards: false,
+		},
+		// no -O flag at all; Clang doesn't optimise by default
+		"": {
+			Optimises:       false,
+			Bias:            optlevel.BiasDebug,
+			BreaksStandards: false,
+		},
+	}
+
+	// OptLevelNames is a consistently named list of the optimisation levels in OptLevels.
+	OptLevelNames = []string{"", "0", "1", "2", "3", "fast", "s", "z", "g"}
+
+	// OptLevelDisabledNames contains optimisation levels that are disabled by default, as they are redundant.
+	OptLevelDisabledNames = []string{"", "0", "g"}
+)
+
+// DefaultOptLevels gets the default level set for Clang.
+func (Clang) DefaultOptLevels(_ *compiler.Compiler) (stringhelp.Set, error) {
+	sel := optlevel.Selection{
+		Enabled:  OptLevelNames,
+		Disabled: OptLevelDisabledNames,
+	}
+	return sel.Override(nil), nil
+}
+
+// OptLevels gets the full level set for Clang.
+func (Clang) OptLevels(_ *compiler.Compiler) (map[string]optlevel.Level, error) {
+	return OptLevels, nil
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package clang_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/clang"
+)
+
+// TestOptLevelNames_consistency makes sure OptLevelNames is consistent with OptLevels in both directions.
+func TestOptLevelNames_consistency(t *testing.T) {
+	t.Parallel()
+	for _, n := range clang.OptLevelNames {
+		assert.Contains(t, clang.OptLevels, n, "name not in levels", n)
+	}
+	for n := range clang.OptLevels {
+		assert.Contains(t, clang.OptLevelNames, n, "level not in names", n)
+	}
+}
+
+// TestClang_DefaultOptLevels tests that DefaultOptLevels enables 'z', unlike GCC, and disables the redundant levels.
+func TestClang_DefaultOptLevels(t *testing.T) {
+	t.Parallel()
+
+	dl, err := clang.Clang{}.DefaultOptLevels(nil)
+	require.NoError(t, err)
+
+	assert.Contains(t, dl, "z", "size level should be enabled")
+	for _, d := range clang.OptLevelDisabledNames {
+		assert.NotContains(t, dl, d, "disabled opt level in defaults", d)
+	}
+}
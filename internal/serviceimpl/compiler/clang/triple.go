@@ -0,0 +1,83 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package clang
+
+import (
+	"strings"
+
+	"github.com/c4-project/c4t/internal/id"
+)
+
+// linuxTriples maps architecture families and variants to the target triples Clang uses for them on Linux.
+var linuxTriples = map[string]string{
+	id.ArchFamilyX86:             "i686-linux-gnu",
+	id.ArchX8664.String():        "x86_64-linux-gnu",
+	id.ArchFamilyAArch64:         "aarch64-linux-gnu",
+	id.ArchArm7.String():         "armv7a-linux-gnueabihf",
+	id.ArchArm8.String():         "armv8a-linux-gnueabihf",
+	id.ArchArmCortexA72.String(): "armv8a-linux-gnueabihf",
+	id.ArchPPC64LE.String():      "powerpc64le-linux-gnu",
+	id.ArchRISCV32.String():      "riscv32-unknown-linux-gnu",
+	id.ArchRISCV64.String():      "riscv64-unknown-linux-gnu",
+}
+
+// darwinTriples maps architecture families and variants to the target triples Clang uses for them on macOS.
+var darwinTriples = map[string]string{
+	id.ArchX8664.String(): "x86_64-apple-darwin",
+	id.ArchFamilyAArch64:  "arm64-apple-darwin",
+}
+
+// Triple gets the Clang target triple for the architecture arch on the operating system with Go name goos.
+//
+// Triple looks up the most specific part of arch for which it knows a triple, so, for instance, 'x86.64.skylake'
+// maps to the triple for 'x86.64'.  It returns false if there is no known triple, in which case Clang should be left
+// to target its default.
+func Triple(arch id.ID, goos string) (string, bool) {
+	var table map[string]string
+	switch goos {
+	case "linux":
+		table = linuxTriples
+	case "darwin":
+		table = darwinTriples
+	default:
+		return "", false
+	}
+
+	family, variant, _ := arch.Triple()
+	if variant != "" {
+		if t, ok := table[family+id.SepTag+variant]; ok {
+			return t, true
+		}
+	}
+	t, ok := table[family]
+	return t, ok
+}
+
+// ArchOfTriple gets the most specific architecture ID we can deduce from the Clang target triple t.
+// It returns false if the triple's architecture isn't one we know.
+func ArchOfTriple(t string) (id.ID, bool) {
+	march := strings.SplitN(t, "-", 2)[0]
+	switch {
+	case march == "x86_64":
+		return id.ArchX8664, true
+	case march == "i386" || march == "i686":
+		return id.ArchX86, true
+	case march == "aarch64" || march == "arm64":
+		return id.ArchAArch64, true
+	case strings.HasPrefix(march, "armv7"):
+		return id.ArchArm7, true
+	case strings.HasPrefix(march, "armv8"):
+		return id.ArchArm8, true
+	case march == "powerpc64le" || march == "ppc64le":
+		return id.ArchPPC64LE, true
+	case march == "riscv32":
+		return id.ArchRISCV32, true
+	case march == "riscv64":
+		return id.ArchRISCV64, true
+	default:
+		return id.ID{}, false
+	}
+}
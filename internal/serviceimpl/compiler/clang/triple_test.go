@@ -0,0 +1,62 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package clang_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/clang"
+)
+
+// ExampleTriple is a runnable example for Triple.
+func ExampleTriple() {
+	fmt.Println(clang.Triple(id.ArchX86Skylake, "linux"))
+	fmt.Println(clang.Triple(id.ArchArmCortexA72, "linux"))
+	fmt.Println(clang.Triple(id.ArchAArch6481, "darwin"))
+	fmt.Println(clang.Triple(id.ArchRISCV64GC, "linux"))
+	fmt.Println(clang.Triple(id.ArchC, "linux"))
+
+	// Output:
+	// x86_64-linux-gnu true
+	// armv8a-linux-gnueabihf true
+	// arm64-apple-darwin true
+	// riscv64-unknown-linux-gnu true
+	//  false
+}
+
+// TestArchOfTriple tests ArchOfTriple on various target triples.
+func TestArchOfTriple(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		arch id.ID
+		ok   bool
+	}{
+		"x86_64-pc-linux-gnu":          {arch: id.ArchX8664, ok: true},
+		"i686-pc-linux-gnu":            {arch: id.ArchX86, ok: true},
+		"arm64-apple-darwin21.1.0":     {arch: id.ArchAArch64, ok: true},
+		"aarch64-unknown-linux-gnu":    {arch: id.ArchAArch64, ok: true},
+		"armv7l-unknown-linux-gnueabi": {arch: id.ArchArm7, ok: true},
+		"powerpc64le-unknown-linux":    {arch: id.ArchPPC64LE, ok: true},
+		"riscv64-unknown-linux-gnu":    {arch: id.ArchRISCV64, ok: true},
+		"s390x-ibm-linux":              {ok: false},
+		"":                             {ok: false},
+	}
+
+	for in, c := range cases {
+		in, c := in, c
+		t.Run(in, func(t *testing.T) {
+			t.Parallel()
+			arch, ok := clang.ArchOfTriple(in)
+			assert.Equal(t, c.ok, ok, "wrong ok")
+			assert.Equal(t, c.arch, arch, "wrong arch")
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package clang
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+)
+
+// ErrNotClang occurs when a version string doesn't look like it came from Clang.
+var ErrNotClang = errors.New("not a clang version string")
+
+// versionRegexp matches the version line of Clang and AppleClang's '--version' output.
+var versionRegexp = regexp.MustCompile(`clang version (\S+)`)
+
+// Version holds the information parsed from the output of 'clang --version'.
+type Version struct {
+	compiler.Version
+	// Target is the default target triple, if one was reported.
+	Target string
+}
+
+// ProbeVersion asks Clang, through sr, for the version of c.
+func (c Clang) ProbeVersion(ctx context.Context, cc *compiler.Compiler, sr service.Runner) (*compiler.Version, error) {
+	run := c.DefaultRunInfo
+	run.OverrideIfNotNil(cc.Run)
+	run.AppendArgs("--version")
+
+	out, err := service.RunAndCaptureStdout(ctx, sr, run)
+	if err != nil {
+		return nil, err
+	}
+	v, err := ParseVersion(out)
+	if err != nil {
+		return nil, err
+	}
+	return &v.Version, nil
+}
+
+// ParseVersion parses the output s of 'clang --version'.
+func ParseVersion(s string) (*Version, error) {
+	var v Version
+	found := false
+
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		line := sc.Text()
+		if t := strings.TrimPrefix(line, "Target:"); t != line {
+			v.Target = strings.TrimSpace(t)
+			continue
+		}
+		if found {
+			continue
+		}
+		if m := versionRegexp.FindStringSubmatch(line); m != nil {
+			cv, err := compiler.FindVersion(m[1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrNotClang, err)
+			}
+			v.Version = *cv
+			found = true
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotClang
+	}
+	return &v, nil
+}
+
+// Arch gets the architecture ID corresponding to v's default target, or the empty ID if we don't know it.
+func (v Version) Arch() id.ID {
+	arch, _ := ArchOfTriple(v.Target)
+	return arch
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package clang_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/clang"
+)
+
+// ExampleParseVersion is a runnable example for ParseVersion.
+func ExampleParseVersion() {
+	v, _ := clang.ParseVersion(`Apple clang version 13.0.0 (clang-1300.0.29.3)
+Target: arm64-apple-darwin21.1.0
+Thread model: posix
+InstalledDir: /Library/Developer/CommandLineTools/usr/bin`)
+	fmt.Println(v)
+	fmt.Println(v.Target)
+	fmt.Println(v.Arch())
+
+	// Output:
+	// 13.0.0
+	// arm64-apple-darwin21.1.0
+	// aarch64
+}
+
+// TestParseVersion tests ParseVersion on various version strings.
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		in  string
+		out clang.Version
+		err error
+	}{
+		"llvm": {
+			in:  "clang version 15.0.7\nTarget: x86_64-pc-linux-gnu\n",
+			out: clang.Version{Version: compiler.Version{Major: 15, Minor: 0, Patch: 7}, Target: "x86_64-pc-linux-gnu"},
+		},
+		"ubuntu": {
+			in:  "Ubuntu clang version 14.0.0-1ubuntu1\nTarget: aarch64-unknown-linux-gnu\n",
+			out: clang.Version{Version: compiler.Version{Major: 14, Minor: 0, Patch: 0}, Target: "aarch64-unknown-linux-gnu"},
+		},
+		"no-target": {
+			in:  "clang version 11.1.0",
+			out: clang.Version{Version: compiler.Version{Major: 11, Minor: 1, Patch: 0}},
+		},
+		"gcc": {
+			in:  "gcc (Ubuntu 11.2.0-19ubuntu1) 11.2.0\nCopyright (C) 2021 Free Software Foundation, Inc.\n",
+			err: clang.ErrNotClang,
+		},
+		"empty": {
+			err: clang.ErrNotClang,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			v, err := clang.ParseVersion(c.in)
+			if !testhelp.ExpectErrorIs(t, err, c.err, "parsing version") || err != nil {
+				return
+			}
+			assert.Equal(t, c.out, *v, "wrong version")
+		})
+	}
+}
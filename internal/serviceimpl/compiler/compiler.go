@@ -19,7 +19,9 @@ import (
 
 	"github.com/c4-project/c4t/internal/model/service/compiler/optlevel"
 
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/clang"
 	"github.com/c4-project/c4t/internal/serviceimpl/compiler/gcc"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/templated"
 
 	mdl "github.com/c4-project/c4t/internal/model/service/compiler"
 
@@ -31,16 +33,20 @@ var (
 	ErrNil = errors.New("compiler nil")
 	// ErrUnknownStyle occurs when we ask the resolver for a compiler style of which it isn't aware.
 	ErrUnknownStyle = errors.New("unknown compiler style")
+	// ErrNoPassControl occurs when we ask the resolver to control the passes of a compiler style that can't do so.
+	ErrNoPassControl = errors.New("compiler style can't control individual optimisation passes")
+	// ErrNoVersionProbe occurs when we ask the resolver to probe the version of a compiler style that can't do so.
+	ErrNoVersionProbe = errors.New("compiler style can't probe compiler versions")
 
 	// CResolve is a pre-populated compiler resolver.
 	CResolve = Resolver{Compilers: map[id.ID]Compiler{
 		id.CStyleGCC: gcc.GCC{
 			DefaultRunInfo: service.RunInfo{Cmd: "gcc", Args: []string{"-pthread", "-std=gnu11"}},
-			AltCommands: []string{
-				// non-exhaustive, add more as we need them
-				"clang",
-			},
 		},
+		id.CStyleClang: clang.Clang{
+			DefaultRunInfo: service.RunInfo{Cmd: "clang", Args: []string{"-pthread", "-std=gnu11"}},
+		},
+		id.CStyleTemplated: templated.Templated{},
 	}}
 )
 
@@ -109,6 +115,55 @@ func (r *Resolver) RunCompiler(ctx context.Context, j mdl.Job, sr service.Runner
 	return cp.RunCompiler(ctx, j, sr)
 }
 
+// OptPasses lists the optimisation passes enabled by the compiler instance c, using sr to run the compiler.
+// It fails with ErrNoPassControl if c's style doesn't support pass control.
+func (r *Resolver) OptPasses(ctx context.Context, c *mdl.Instance, sr service.Runner) ([]string, error) {
+	pc, err := r.getPassController(c)
+	if err != nil {
+		return nil, err
+	}
+	return pc.OptPasses(ctx, c, sr)
+}
+
+// DisablePasses modifies the compiler instance c so as to disable each of passes.
+// It fails with ErrNoPassControl if c's style doesn't support pass control.
+func (r *Resolver) DisablePasses(c *mdl.Instance, passes []string) error {
+	pc, err := r.getPassController(c)
+	if err != nil {
+		return err
+	}
+	return pc.DisablePasses(c, passes)
+}
+
+func (r *Resolver) getPassController(c *mdl.Instance) (mdl.PassController, error) {
+	if c == nil {
+		return nil, ErrNil
+	}
+	cp, err := r.Get(&c.Compiler)
+	if err != nil {
+		return nil, err
+	}
+	pc, ok := cp.(mdl.PassController)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoPassControl, c.Style)
+	}
+	return pc, nil
+}
+
+// ProbeVersion probes the version of the compiler c, using sr to run the compiler.
+// It fails with ErrNoVersionProbe if c's style doesn't support version probing.
+func (r *Resolver) ProbeVersion(ctx context.Context, c *mdl.Compiler, sr service.Runner) (*mdl.Version, error) {
+	cp, err := r.Get(c)
+	if err != nil {
+		return nil, err
+	}
+	vp, ok := cp.(mdl.VersionProber)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoVersionProbe, c.Style)
+	}
+	return vp.ProbeVersion(ctx, c, sr)
+}
+
 func (r *Resolver) Probe(ctx context.Context, sr service.Runner) (mdl.ConfigMap, error) {
 	// As an educated guess, assume every class has one spec.
 	target := make(mdl.ConfigMap, len(r.Compilers))
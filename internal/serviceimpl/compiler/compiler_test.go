@@ -11,6 +11,7 @@ import (
 
 	mocks2 "github.com/c4-project/c4t/internal/model/service/mocks"
 
+	"github.com/c4-project/c4t/internal/helper/testhelp"
 	"github.com/c4-project/c4t/internal/id"
 	"github.com/c4-project/c4t/internal/serviceimpl/compiler"
 	"github.com/c4-project/c4t/internal/serviceimpl/compiler/mocks"
@@ -49,3 +50,41 @@ func TestResolver_RunCompiler(t *testing.T) {
 	mc.AssertExpectations(t)
 	mr.AssertExpectations(t)
 }
+
+// TestResolver_OptPasses tests that OptPasses fails on compiler styles that can't control passes.
+func TestResolver_OptPasses(t *testing.T) {
+	mc := new(mocks.Compiler)
+	mr := new(mocks2.Runner)
+	mc.Test(t)
+	mr.Test(t)
+
+	r := compiler.Resolver{Compilers: map[id.ID]compiler.Compiler{id.CStyleGCC: mc}}
+
+	_, err := r.OptPasses(context.Background(), &mdl.Instance{Compiler: mdl.Compiler{Style: id.CStyleGCC}}, mr)
+	testhelp.ExpectErrorIs(t, err, compiler.ErrNoPassControl, "listing passes on mock compiler")
+
+	_, err = r.OptPasses(context.Background(), nil, mr)
+	testhelp.ExpectErrorIs(t, err, compiler.ErrNil, "listing passes on nil compiler")
+
+	mc.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+// TestResolver_ProbeVersion tests that ProbeVersion fails on compiler styles that can't probe versions.
+func TestResolver_ProbeVersion(t *testing.T) {
+	mc := new(mocks.Compiler)
+	mr := new(mocks2.Runner)
+	mc.Test(t)
+	mr.Test(t)
+
+	r := compiler.Resolver{Compilers: map[id.ID]compiler.Compiler{id.CStyleGCC: mc}}
+
+	_, err := r.ProbeVersion(context.Background(), &mdl.Compiler{Style: id.CStyleGCC}, mr)
+	testhelp.ExpectErrorIs(t, err, compiler.ErrNoVersionProbe, "probing version of mock compiler")
+
+	_, err = r.ProbeVersion(context.Background(), &mdl.Compiler{Style: id.FromString("nonsuch")}, mr)
+	testhelp.ExpectErrorIs(t, err, compiler.ErrUnknownStyle, "probing version of unknown compiler")
+
+	mc.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
@@ -17,7 +17,7 @@ import (
 	"github.com/c4-project/c4t/internal/model/service"
 )
 
-// GCC represents GCC-style compilers such as GCC and Clang.
+// GCC represents GCC-style compilers.
 type GCC service.ExtClass
 
 // RunCompiler compiles j using a GCC-friendly invocation.
@@ -41,6 +41,7 @@ func Args(j compiler.Job) []string {
 	var args []string
 	args = AddStringArg(args, "O", j.SelectedOptName())
 	args = AddStringArg(args, "m", j.SelectedMOptName())
+	args = append(args, j.SelectedFlags()...)
 	args = AddKindArg(args, j.Kind)
 	args = append(args, "-o", j.Out)
 	args = append(args, j.In...)
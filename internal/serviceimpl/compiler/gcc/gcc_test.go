@@ -143,6 +143,18 @@ func TestArgs(t *testing.T) {
 			),
 			out: []string{"-O3", "-o", "a.out", "foo.c", "bar.c"},
 		},
+		"with-flags": {
+			job: *compiler.NewJob(
+				compiler.Obj,
+				&compiler.Instance{
+					SelectedMOpt:  "arch=nehalem",
+					SelectedFlags: []string{"-fwrapv", "-fno-inline"},
+				},
+				"foo.o",
+				"foo.c",
+			),
+			out: []string{"-march=nehalem", "-fwrapv", "-fno-inline", "-c", "-o", "foo.o", "foo.c"},
+		},
 		"do-not-override-run": {
 			job: *compiler.NewJob(
 				compiler.Exe,
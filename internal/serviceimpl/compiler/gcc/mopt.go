@@ -101,5 +101,6 @@ var invocationFamilies = map[string]func(string, id.ID) (*mOptSet, error){
 	id.ArchFamilyAArch64: aarch64MOpts,
 	id.ArchFamilyArm:     armMOpts,
 	id.ArchFamilyPPC:     ppcMOpts,
+	id.ArchFamilyRISCV:   riscvMOpts,
 	id.ArchFamilyX86:     x86MOpts,
 }
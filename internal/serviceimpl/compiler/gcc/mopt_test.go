@@ -35,6 +35,10 @@ func TestDefaultMOpts(t *testing.T) {
 		"power9":       {in: id.ArchPPCPOWER9, out: []string{"", "cpu=native", "cpu=powerpc64le", "cpu=power7", "cpu=power8", "cpu=power9"}},
 		"power8":       {in: id.ArchPPCPOWER8, out: []string{"", "cpu=native", "cpu=powerpc64le", "cpu=power7", "cpu=power8"}},
 		"power7":       {in: id.ArchPPCPOWER7, out: []string{"", "cpu=native", "cpu=powerpc64le", "cpu=power7"}},
+		"riscv64gcv":   {in: id.ArchRISCV64GCV, out: []string{"", "arch=rv64gc", "arch=rv64gcv"}},
+		"riscv64gc":    {in: id.ArchRISCV64GC, out: []string{"", "arch=rv64gc"}},
+		"riscv64":      {in: id.ArchRISCV64, out: []string{""}},
+		"riscv32gc":    {in: id.FromString("riscv.32.gc"), out: []string{"", "arch=rv32gc"}},
 	}
 
 	for name, c := range cases {
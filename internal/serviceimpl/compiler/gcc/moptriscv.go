@@ -0,0 +1,42 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package gcc
+
+import (
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/id"
+)
+
+func riscvMOpts(variant string, subvar id.ID) (*mOptSet, error) {
+	switch variant {
+	case id.ArchVariantRISCV32, id.ArchVariantRISCV64:
+		return riscvISAMOpts("rv"+variant, subvar)
+	default:
+		return nil, fmt.Errorf("%w: unknown variant: %s", ErrUnsupportedVariant, variant)
+	}
+}
+
+func riscvISAMOpts(base string, svar id.ID) (*mOptSet, error) {
+	// The empty selection uses the toolchain's default ISA, which stands in for -march=native on older GCCs.
+	// We don't go below G, as the default Linux ABIs need the D extension and litmus tests need the A extension.
+	set := newMOptSet(true)
+
+	// More extensions append to fewer extensions, hence the fallthrough.
+	switch svar.String() {
+	case id.ArchSubVariantRISCVGCV:
+		set.AddArch(base + "gcv")
+		fallthrough
+	case id.ArchSubVariantRISCVGC:
+		set.AddArch(base + "gc")
+		fallthrough
+	case "":
+	default:
+		return nil, fmt.Errorf("%w: unknown subvariant: %s", ErrUnsupportedVariant, svar)
+	}
+
+	return set, nil
+}
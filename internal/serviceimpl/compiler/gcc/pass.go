@@ -0,0 +1,78 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package gcc
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+)
+
+// OptPasses asks GCC, through sr, which '-f' optimisation passes c enables at its selected optimisation level.
+func (g GCC) OptPasses(ctx context.Context, c *compiler.Instance, sr service.Runner) ([]string, error) {
+	run := g.DefaultRunInfo
+	run.OverrideIfNotNil(c.Run)
+	run.AppendArgs(PassQueryArgs(c)...)
+
+	out, err := service.RunAndCaptureStdout(ctx, sr, run)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePasses(strings.NewReader(out))
+}
+
+// PassQueryArgs computes the arguments to pass to GCC to list the optimisation passes enabled by c.
+func PassQueryArgs(c *compiler.Instance) []string {
+	var args []string
+	args = AddStringArg(args, "O", c.SelectedOptName())
+	args = AddStringArg(args, "m", c.SelectedMOpt)
+	args = append(args, c.SelectedFlags...)
+	return append(args, "-Q", "--help=optimizers")
+}
+
+// ParsePasses parses the output of 'gcc -Q --help=optimizers' from r, returning the names of each enabled pass.
+//
+// Pass names are returned without their '-f' prefix; passes that take values are ignored, as they can't be disabled
+// with a simple '-fno-' flag.
+func ParsePasses(r io.Reader) ([]string, error) {
+	var passes []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fs := strings.Fields(s.Text())
+		if len(fs) != 2 || fs[1] != "[enabled]" {
+			continue
+		}
+		if name := strings.TrimPrefix(fs[0], "-f"); name != fs[0] && !strings.ContainsAny(name, "=<") {
+			passes = append(passes, name)
+		}
+	}
+	return passes, s.Err()
+}
+
+// DisablePasses modifies c so that GCC disables each of passes, by appending '-fno-' flags to its run information.
+func (GCC) DisablePasses(c *compiler.Instance, passes []string) error {
+	var run service.RunInfo
+	if c.Run != nil {
+		run = *c.Run
+	}
+	// Copying the arguments, as c.Run may be shared with other instances.
+	run.Args = append(append([]string(nil), run.Args...), DisablePassArgs(passes)...)
+	c.Run = &run
+	return nil
+}
+
+// DisablePassArgs gets the arguments needed to disable each pass in passes.
+func DisablePassArgs(passes []string) []string {
+	args := make([]string, len(passes))
+	for i, p := range passes {
+		args[i] = "-fno-" + p
+	}
+	return args
+}
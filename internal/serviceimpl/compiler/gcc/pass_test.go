@@ -0,0 +1,69 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package gcc_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/helper/srvrun"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/model/service/compiler/optlevel"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/gcc"
+)
+
+// ExampleGCC_OptPasses is a runnable example for GCC.OptPasses.
+func ExampleGCC_OptPasses() {
+	g := gcc.GCC{DefaultRunInfo: service.RunInfo{Cmd: "gcc"}}
+	c := compiler.Instance{
+		SelectedMOpt: "arch=skylake",
+		SelectedOpt:  &optlevel.Named{Name: "3"},
+	}
+	// The dry runner doesn't produce any output, so we won't get any passes here.
+	passes, err := g.OptPasses(context.Background(), &c, srvrun.DryRunner{Writer: os.Stdout})
+	fmt.Println(len(passes), err)
+
+	// Output:
+	// gcc -O3 -march=skylake -Q --help=optimizers
+	// 0 <nil>
+}
+
+// ExampleParsePasses is a runnable example for ParsePasses.
+func ExampleParsePasses() {
+	out := `The following options control optimizations:
+  -O<number>                  		
+  -faggressive-loop-optimizations 	[enabled]
+  -falign-functions           		[disabled]
+  -falign-functions=          		
+  -fexcess-precision=[fast|standard|16] 	[default]
+  -ftree-vectorize            		[enabled]
+  -fvect-cost-model=[unlimited|dynamic|cheap|very-cheap] 	[dynamic]
+`
+	passes, err := gcc.ParsePasses(strings.NewReader(out))
+	fmt.Println(passes, err)
+	fmt.Println(gcc.DisablePassArgs(passes))
+
+	// Output:
+	// [aggressive-loop-optimizations tree-vectorize] <nil>
+	// [-fno-aggressive-loop-optimizations -fno-tree-vectorize]
+}
+
+// ExampleGCC_DisablePasses is a runnable example for GCC.DisablePasses.
+func ExampleGCC_DisablePasses() {
+	var g gcc.GCC
+	c := compiler.Instance{Compiler: compiler.Compiler{Run: service.NewRunInfo("gcc-10", "-g")}}
+	c2 := c
+	_ = g.DisablePasses(&c2, []string{"tree-vectorize", "gcse"})
+	fmt.Println(c.Run)
+	fmt.Println(c2.Run)
+
+	// Output:
+	// gcc-10 -g
+	// gcc-10 -g -fno-tree-vectorize -fno-gcse
+}
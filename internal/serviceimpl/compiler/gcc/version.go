@@ -0,0 +1,42 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package gcc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+)
+
+// ProbeVersion asks GCC, through sr, for the version of c.
+func (g GCC) ProbeVersion(ctx context.Context, c *compiler.Compiler, sr service.Runner) (*compiler.Version, error) {
+	run := g.DefaultRunInfo
+	run.OverrideIfNotNil(c.Run)
+	run.AppendArgs("--version")
+
+	out, err := service.RunAndCaptureStdout(ctx, sr, run)
+	if err != nil {
+		return nil, err
+	}
+	return ParseVersion(out)
+}
+
+// ParseVersion parses the version of GCC from the output s of 'gcc --version'.
+//
+// The first line of this output generally looks like 'gcc (Vendor 1.2.3-4) 1.2.3', though some vendors put more
+// information after the version; we look for the first version number after the parenthetical, falling back to the
+// first version number on the line if there isn't one (as happens with Apple's 'gcc', which is really Clang).
+func ParseVersion(s string) (*compiler.Version, error) {
+	line := strings.SplitN(s, "\n", 2)[0]
+	if i := strings.Index(line, ")"); i != -1 {
+		if v, err := compiler.FindVersion(line[i+1:]); err == nil {
+			return v, nil
+		}
+	}
+	return compiler.FindVersion(line)
+}
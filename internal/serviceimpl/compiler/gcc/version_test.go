@@ -0,0 +1,56 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package gcc_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/gcc"
+)
+
+// ExampleParseVersion is a runnable example for ParseVersion.
+func ExampleParseVersion() {
+	v, _ := gcc.ParseVersion(`gcc (Ubuntu 11.2.0-19ubuntu1) 11.2.0
+Copyright (C) 2021 Free Software Foundation, Inc.`)
+	fmt.Println(v)
+
+	// Output:
+	// 11.2.0
+}
+
+// TestParseVersion tests ParseVersion on various version strings.
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		in  string
+		out compiler.Version
+		err error
+	}{
+		"plain":    {in: "gcc (GCC) 10.2.1\n", out: compiler.Version{Major: 10, Minor: 2, Patch: 1}},
+		"red-hat":  {in: "gcc (GCC) 4.8.5 20150623 (Red Hat 4.8.5-44)\n", out: compiler.Version{Major: 4, Minor: 8, Patch: 5}},
+		"homebrew": {in: "gcc-9 (Homebrew GCC 9.3.0_1) 9.3.0\n", out: compiler.Version{Major: 9, Minor: 3}},
+		"apple":    {in: "Apple clang version 13.0.0 (clang-1300.0.29.3)\n", out: compiler.Version{Major: 13}},
+		"garbage":  {in: "no version here\n", err: compiler.ErrBadVersion},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			v, err := gcc.ParseVersion(c.in)
+			if !testhelp.ExpectErrorIs(t, err, c.err, "parsing version") || err != nil {
+				return
+			}
+			assert.Equal(t, c.out, *v, "wrong version")
+		})
+	}
+}
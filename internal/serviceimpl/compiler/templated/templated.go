@@ -0,0 +1,190 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package templated implements the templated compiler style.
+//
+// Templated compilers take their argument layout, optimisation levels, and machine optimisation profiles from the
+// 'template' section of their configuration, which lets us support new compilers without writing any Go code.
+package templated
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/buildkite/interpolate"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/model/service/compiler/optlevel"
+)
+
+const (
+	// VarIn is the template variable for input files.
+	VarIn = "in"
+	// VarOut is the template variable for the output file.
+	VarOut = "out"
+	// VarOpt is the template variable for the selected optimisation level.
+	VarOpt = "opt"
+	// VarMOpt is the template variable for the selected machine optimisation profile.
+	VarMOpt = "mopt"
+	// VarKind is the template variable for the compile kind argument.
+	VarKind = "kind"
+	// VarFlags is the template variable for any extra flags selected from the compiler's flag pool.
+	VarFlags = "flags"
+)
+
+var (
+	// ErrNoTemplate occurs when a templated compiler has no template.
+	ErrNoTemplate = errors.New("templated compiler has no template")
+	// ErrNoCommand occurs when a templated compiler has no command to run.
+	ErrNoCommand = errors.New("templated compiler has no command")
+)
+
+// Templated represents compilers whose invocation is described by a compiler.Template in their configuration.
+type Templated struct{}
+
+// RunCompiler compiles j by expanding its compiler's template.
+func (Templated) RunCompiler(ctx context.Context, j compiler.Job, sr service.Runner) error {
+	run, err := RunInfo(j)
+	if err != nil {
+		return err
+	}
+	return sr.Run(ctx, *run)
+}
+
+// RunInfo computes the run information for compiling j with a templated compiler.
+// The command and any arguments in j's compiler's run information come first, followed by the expanded template.
+func RunInfo(j compiler.Job) (*service.RunInfo, error) {
+	t, err := template(j.Compiler)
+	if err != nil {
+		return nil, err
+	}
+	var run service.RunInfo
+	run.OverrideIfNotNil(j.CompilerRun())
+	if run.Cmd == "" {
+		return nil, ErrNoCommand
+	}
+
+	targs, err := Args(*t, j)
+	if err != nil {
+		return nil, err
+	}
+	run.AppendArgs(targs...)
+	return &run, nil
+}
+
+// Args expands the arguments of template t for job j.
+func Args(t compiler.Template, j compiler.Job) ([]string, error) {
+	vars := variables(t, j)
+
+	tr := service.RunInfo{Args: make([]string, 0, len(t.Args)+len(j.In))}
+	for _, arg := range t.Args {
+		// We splice inputs and flags directly, rather than interpolating them, so that they stay as separate arguments.
+		switch arg {
+		case "${" + VarIn + "}":
+			tr.AppendArgs(j.In...)
+			continue
+		case "${" + VarFlags + "}":
+			tr.AppendArgs(j.SelectedFlags()...)
+			continue
+		}
+		keep, err := keepArg(arg, vars)
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			tr.AppendArgs(arg)
+		}
+	}
+	if err := tr.Interpolate(vars); err != nil {
+		return nil, err
+	}
+	return tr.Args, nil
+}
+
+func variables(t compiler.Template, j compiler.Job) map[string]string {
+	vars := map[string]string{}
+	if j.Compiler != nil {
+		vars = j.Compiler.Interpolations()
+	}
+	vars[VarIn] = strings.Join(j.In, " ")
+	vars[VarOut] = j.Out
+	vars[VarOpt] = j.SelectedOptName()
+	vars[VarMOpt] = j.SelectedMOptName()
+	vars[VarKind] = t.KindArg(j.Kind)
+	vars[VarFlags] = strings.Join(j.SelectedFlags(), " ")
+	return vars
+}
+
+// keepArg decides whether to keep argument template arg, dropping it if it mentions an empty optional variable.
+func keepArg(arg string, vars map[string]string) (bool, error) {
+	ids, err := interpolate.Identifiers(arg)
+	if err != nil {
+		return false, fmt.Errorf("bad template argument %q: %w", arg, err)
+	}
+	for _, v := range ids {
+		switch v {
+		case VarOpt, VarMOpt, VarKind, VarFlags:
+			if vars[v] == "" {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// DefaultOptLevels gets the optimisation levels declared in c's template.
+func (t Templated) DefaultOptLevels(c *compiler.Compiler) (stringhelp.Set, error) {
+	ls, err := t.OptLevels(c)
+	if err != nil {
+		return nil, err
+	}
+	names := make(stringhelp.Set, len(ls))
+	for n := range ls {
+		names.Add(n)
+	}
+	return names, nil
+}
+
+// OptLevels gets the optimisation levels declared in c's template.
+func (Templated) OptLevels(c *compiler.Compiler) (map[string]optlevel.Level, error) {
+	t, err := compilerTemplate(c)
+	if err != nil {
+		return nil, err
+	}
+	return t.OptLevels, nil
+}
+
+// DefaultMOpts gets the machine optimisation profiles declared in c's template.
+func (Templated) DefaultMOpts(c *compiler.Compiler) (stringhelp.Set, error) {
+	t, err := compilerTemplate(c)
+	if err != nil {
+		return nil, err
+	}
+	return stringhelp.NewSet(t.MOpts...), nil
+}
+
+// Probe does nothing, as templated compilers only come from configuration.
+func (Templated) Probe(context.Context, service.Runner, id.ID, compiler.ConfigMap) error {
+	return nil
+}
+
+func template(c *compiler.Instance) (*compiler.Template, error) {
+	if c == nil {
+		return nil, ErrNoTemplate
+	}
+	return compilerTemplate(&c.Compiler)
+}
+
+func compilerTemplate(c *compiler.Compiler) (*compiler.Template, error) {
+	if c == nil || c.Template == nil {
+		return nil, ErrNoTemplate
+	}
+	return c.Template, nil
+}
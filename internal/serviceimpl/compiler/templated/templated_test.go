@@ -0,0 +1,168 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package templated_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/helper/srvrun"
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/model/service/compiler/optlevel"
+	"github.com/c4-project/c4t/internal/serviceimpl/compiler/templated"
+)
+
+// compCert is a template loosely modelled on CompCert's command line.
+var compCert = compiler.Template{
+	Args: []string{"-O${opt}", "${kind}", "-o", "${out}", "${in}"},
+	OptLevels: map[string]optlevel.Level{
+		"0": {Bias: optlevel.BiasDebug},
+		"s": {Optimises: true, Bias: optlevel.BiasSize},
+	},
+}
+
+// ExampleTemplated_RunCompiler is a runnable example for Templated.RunCompiler.
+func ExampleTemplated_RunCompiler() {
+	j := compiler.NewJob(
+		compiler.Obj,
+		&compiler.Instance{
+			SelectedOpt: &optlevel.Named{Name: "s"},
+			Compiler: compiler.Compiler{
+				Run:      service.NewRunInfo("ccomp", "-fstruct-passing"),
+				Template: &compCert,
+			},
+		},
+		"foo.o",
+		"foo.c",
+	)
+	_ = templated.Templated{}.RunCompiler(context.Background(), *j, srvrun.DryRunner{Writer: os.Stdout})
+
+	// Output:
+	// ccomp -fstruct-passing -Os -c -o foo.o foo.c
+}
+
+// TestArgs tests Args on various templates and jobs.
+func TestArgs(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		tmpl compiler.Template
+		job  compiler.Job
+		out  []string
+	}{
+		"exe-no-opt": {
+			tmpl: compCert,
+			job:  *compiler.NewJob(compiler.Exe, nil, "a.out", "foo.c", "bar.c"),
+			out:  []string{"-o", "a.out", "foo.c", "bar.c"},
+		},
+		"obj-custom-kind": {
+			tmpl: compiler.Template{
+				Args:  []string{"${kind}", "${out}", "${in}"},
+				Kinds: map[string]string{"exe": "--link", "obj": "--no-link"},
+			},
+			job: *compiler.NewJob(compiler.Obj, nil, "foo.o", "foo.c"),
+			out: []string{"--no-link", "foo.o", "foo.c"},
+		},
+		"mopt-and-vars": {
+			tmpl: compiler.Template{
+				Args: []string{"--target=${mopt}", "--seed=${mutant}", "/Fe${out}", "--inputs=${in}"},
+			},
+			job: *compiler.NewJob(
+				compiler.Exe,
+				&compiler.Instance{SelectedMOpt: "rv64gc"},
+				"a.exe",
+				"foo.c", "bar.c",
+			),
+			out: []string{"--target=rv64gc", "--seed=0", "/Fea.exe", "--inputs=foo.c bar.c"},
+		},
+		"flags": {
+			tmpl: compiler.Template{Args: []string{"${flags}", "--flags=${flags}", "${in}"}},
+			job: *compiler.NewJob(
+				compiler.Exe,
+				&compiler.Instance{SelectedFlags: []string{"-fwrapv", "-fno-inline"}},
+				"a.out",
+				"foo.c",
+			),
+			out: []string{"-fwrapv", "-fno-inline", "--flags=-fwrapv -fno-inline", "foo.c"},
+		},
+		"no-flags": {
+			tmpl: compiler.Template{Args: []string{"${flags}", "--flags=${flags}", "${in}"}},
+			job:  *compiler.NewJob(compiler.Exe, nil, "a.out", "foo.c"),
+			out:  []string{"foo.c"},
+		},
+		"no-mopt": {
+			tmpl: compiler.Template{Args: []string{"--target=${mopt}", "${in}"}},
+			job:  *compiler.NewJob(compiler.Exe, nil, "a.out", "foo.c"),
+			out:  []string{"foo.c"},
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			out, err := templated.Args(c.tmpl, c.job)
+			require.NoError(t, err, "expanding template")
+			assert.Equal(t, c.out, out, "wrong arguments")
+		})
+	}
+}
+
+// TestRunInfo_errors tests the error cases of RunInfo.
+func TestRunInfo_errors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		inst *compiler.Instance
+		err  error
+	}{
+		"no-instance": {err: templated.ErrNoTemplate},
+		"no-template": {
+			inst: &compiler.Instance{Compiler: compiler.Compiler{Run: service.NewRunInfo("ccomp")}},
+			err:  templated.ErrNoTemplate,
+		},
+		"no-command": {
+			inst: &compiler.Instance{Compiler: compiler.Compiler{Template: &compCert}},
+			err:  templated.ErrNoCommand,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := templated.RunInfo(*compiler.NewJob(compiler.Exe, c.inst, "a.out", "foo.c"))
+			testhelp.ExpectErrorIs(t, err, c.err, "computing run info")
+		})
+	}
+}
+
+// TestTemplated_inspector tests that the optimisation levels and mopts of a templated compiler come from its template.
+func TestTemplated_inspector(t *testing.T) {
+	t.Parallel()
+
+	tmpl := compCert
+	tmpl.MOpts = []string{"", "rv64gc"}
+	c := compiler.Compiler{Template: &tmpl}
+
+	ls, err := compiler.SelectLevels(templated.Templated{}, &c)
+	require.NoError(t, err, "selecting levels")
+	assert.Equal(t, compCert.OptLevels, ls, "wrong levels")
+
+	ms, err := compiler.SelectMOpts(templated.Templated{}, &c)
+	require.NoError(t, err, "selecting mopts")
+	assert.Equal(t, stringhelp.NewSet("", "rv64gc"), ms, "wrong mopts")
+
+	_, err = templated.Templated{}.DefaultMOpts(&compiler.Compiler{})
+	assert.ErrorIs(t, err, templated.ErrNoTemplate, "missing template should error")
+}
@@ -14,6 +14,9 @@ import (
 	"github.com/c4-project/c4t/internal/plan/stage"
 
 	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stage/regressor"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
 
 	"github.com/c4-project/c4t/internal/plan/analysis"
 
@@ -26,6 +29,8 @@ type Analyser struct {
 	errOnBadStatus bool
 	// savePaths is the set of paths to which we save failing corpora.
 	savePaths *saver.Pathset
+	// regress, if present, is the store to which we add saved subjects for later regression testing.
+	regress *regressor.Store
 	// aopts is the set of options to pass to the underlying analyser.
 	aopts []analysis.Option
 	// observers is the list of observers to which analyses are sent.
@@ -79,6 +84,9 @@ func (a *Analyser) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 	if err := a.maybeSave(an); err != nil {
 		return nil, err
 	}
+	if err := a.maybeRegress(an); err != nil {
+		return nil, err
+	}
 
 	return an.Plan, a.statusErr(an)
 }
@@ -99,6 +107,22 @@ func (a *Analyser) maybeSave(an *analysis.Analysis) error {
 	return save.Run(*an)
 }
 
+func (a *Analyser) maybeRegress(an *analysis.Analysis) error {
+	if a.regress == nil {
+		return nil
+	}
+	// We regress every subject that the saver archives, not just the flagged ones: forbidden subjects, in particular,
+	// are usually miscompilations.
+	c := make(corpus.Corpus)
+	for st := status.FirstBad; st <= status.Last; st++ {
+		for n, s := range an.ByStatus[st] {
+			c[n] = s
+		}
+	}
+	_, err := a.regress.Add(c)
+	return err
+}
+
 func (a *Analyser) analyse(ctx context.Context, p *plan.Plan) (*analysis.Analysis, error) {
 	return analysis.Analyse(ctx, p, a.aopts...)
 }
@@ -0,0 +1,57 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package analyser_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/stage/analyser"
+	"github.com/c4-project/c4t/internal/stage/regressor"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnalyser_Run_regress tests that the analyser adds every subject with a bad status to its regression store.
+func TestAnalyser_Run_regress(t *testing.T) {
+	t.Parallel()
+
+	in := t.TempDir()
+	st := regressor.NewStore(filepath.Join(t.TempDir(), "regress"))
+
+	p := plan.Mock()
+	p.Corpus = make(corpus.Corpus)
+	for i, s := range []status.Status{status.Ok, status.Flagged, status.Forbidden, status.RunFail} {
+		name := s.String()
+		lpath := filepath.Join(in, name+".litmus")
+		// Each test needs a distinct canonical hash, or the store will deduplicate it.
+		body := "C " + name + " { x = " + strconv.Itoa(i) + "; }"
+		require.NoError(t, os.WriteFile(lpath, []byte(body), 0644), "writing litmus test")
+		p.Corpus[name] = *subject.NewOrPanic(
+			litmus.NewOrPanic(lpath),
+			subject.WithRun(id.FromString("gcc"), compilation.RunResult{Result: compilation.Result{Status: s}}),
+		)
+	}
+
+	a, err := analyser.New(analyser.RegressTo(st))
+	require.NoError(t, err, "constructing analyser")
+	_, err = a.Run(context.Background(), p)
+	require.NoError(t, err, "running analyser")
+
+	rc, err := st.Load()
+	require.NoError(t, err, "loading regression store")
+	assert.Len(t, rc, 3, "regression store should contain every subject with a bad status")
+}
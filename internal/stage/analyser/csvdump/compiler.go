@@ -42,6 +42,7 @@ var staticColumnHeaders = [...]string{
 	"ArchID",
 	"Opt",
 	"MOpt",
+	"Version",
 }
 
 func (c *CompilerWriter) writeHeader() {
@@ -78,6 +79,7 @@ func (c *CompilerWriter) staticColumnsForCompiler(cid id.ID, can analysis.Compil
 		can.Info.Arch.String(),
 		optName(can.Info),
 		can.Info.SelectedMOpt,
+		versionName(can.Info),
 	}
 }
 
@@ -101,6 +103,13 @@ func duration(d time.Duration) string {
 	return fmt.Sprint(d.Seconds())
 }
 
+func versionName(i compiler.Instance) string {
+	if i.Version == nil {
+		return ""
+	}
+	return i.Version.String()
+}
+
 func optName(i compiler.Instance) string {
 	if i.SelectedOpt == nil {
 		return ""
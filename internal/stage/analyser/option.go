@@ -11,6 +11,7 @@ import (
 	"github.com/c4-project/c4t/internal/plan/analysis"
 
 	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stage/regressor"
 )
 
 // ErrObserverNil occurs if we pass a nil Observer to ObserveWith.
@@ -82,3 +83,14 @@ func SaveToPathset(ps *saver.Pathset) Option {
 		return nil
 	}
 }
+
+// RegressTo makes this analyser stage add every subject it would save (that is, every subject with a bad status) to
+// the given regression store.
+// This can be nil, in which case no regression corpus is kept.
+func RegressTo(st *regressor.Store) Option {
+	return func(a *Analyser) error {
+		// st can be nil
+		a.regress = st
+		return nil
+	}
+}
@@ -8,6 +8,7 @@ package pretty_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,7 +20,9 @@ import (
 
 	"github.com/c4-project/c4t/internal/plan"
 	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/plan/stage"
 	"github.com/c4-project/c4t/internal/stage/analyser/pretty"
+	"github.com/c4-project/c4t/internal/subject"
 )
 
 // ExamplePrinter_OnAnalysis is a testable example for Printer.OnAnalysis.
@@ -73,33 +76,63 @@ func TestPrinter_OnAnalysis_regress(t *testing.T) {
 
 		var p plan.Plan
 		require.NoError(t, plan.ReadFile(path, &p), "couldn't read plan")
-
-		an, err := analysis.Analyse(context.Background(), &p)
-		require.NoError(t, err, "couldn't analyse plan")
-
-		cases := map[string]pretty.Option{
-			"cp":  pretty.Options(pretty.ShowCompilers(true), pretty.ShowPlanInfo(true)),
-			"cs":  pretty.Options(pretty.ShowCompilers(true), pretty.ShowSubjects(true)),
-			"ps":  pretty.Options(pretty.ShowPlanInfo(true), pretty.ShowSubjects(true)),
-			"cps": pretty.Options(pretty.ShowCompilers(true), pretty.ShowPlanInfo(true), pretty.ShowSubjects(true)),
-			"mut": pretty.Options(pretty.ShowMutation(true)),
-		}
-
-		var gotw bytes.Buffer
-		for cname, popt := range cases {
-			gotw.Reset()
-			cfile := strings.Join([]string{name, cname, "txt"}, ".")
-
-			t.Run(cfile, func(t *testing.T) {
-				wbytes, err := os.ReadFile(filepath.Join("testdata", cfile))
-				require.NoErrorf(t, err, "couldn't load case file %q", cfile)
-				want := string(wbytes)
-
-				pp, err := pretty.NewPrinter(popt, pretty.WriteTo(&gotw))
-				require.NoErrorf(t, err, "couldn't set up pretty printer for profile %q", cname)
-				require.NoErrorf(t, pp.Write(*an), "couldn't write analysis for profile %q", cname)
-				require.Equal(t, want, gotw.String())
-			})
-		}
+		testProfiles(t, name, &p)
 	})
 }
+
+// TestPrinter_OnAnalysis_failures performs regression testing on the output of the pretty printer for a plan with
+// stage failures.
+//
+// It takes the plan in testdata/small-ok.json, adds some failed subjects, and tests output against the small-fail
+// profiles.
+func TestPrinter_OnAnalysis_failures(t *testing.T) {
+	t.Parallel()
+
+	var p plan.Plan
+	require.NoError(t, plan.ReadFile(filepath.Join("testdata", "small-ok.json"), &p), "couldn't read plan")
+
+	src := p.Corpus["test_1"].Source
+	fails := map[string]subject.Failure{
+		"test_1_2": subject.NewFailure(stage.Fuzz, errors.New("context deadline exceeded"), true),
+		"test_3":   subject.NewFailure(stage.Lift, errors.New("when lifting test_3 with Backend (arch aarch64.8.1): exit status 1"), false),
+	}
+	for n, f := range fails {
+		s := subject.NewOrPanic(&src)
+		s.AddFailure(f)
+		p.Corpus[n] = *s
+	}
+	testProfiles(t, "small-fail", &p)
+}
+
+// testProfiles analyses p and tests the pretty printer's output against the profiles in testdata/ for name.
+func testProfiles(t *testing.T, name string, p *plan.Plan) {
+	t.Helper()
+
+	an, err := analysis.Analyse(context.Background(), p)
+	require.NoError(t, err, "couldn't analyse plan")
+
+	cases := map[string]pretty.Option{
+		"cp":  pretty.Options(pretty.ShowCompilers(true), pretty.ShowPlanInfo(true)),
+		"cs":  pretty.Options(pretty.ShowCompilers(true), pretty.ShowSubjects(true)),
+		"ps":  pretty.Options(pretty.ShowPlanInfo(true), pretty.ShowSubjects(true)),
+		"cps": pretty.Options(pretty.ShowCompilers(true), pretty.ShowPlanInfo(true), pretty.ShowSubjects(true)),
+		"mut": pretty.Options(pretty.ShowMutation(true)),
+	}
+
+	var gotw bytes.Buffer
+	for cname, popt := range cases {
+		gotw.Reset()
+		cfile := strings.Join([]string{name, cname, "txt"}, ".")
+
+		t.Run(cfile, func(t *testing.T) {
+			wbytes, err := os.ReadFile(filepath.Join("testdata", cfile))
+			require.NoErrorf(t, err, "couldn't load case file %q", cfile)
+			want := string(wbytes)
+
+			pp, err := pretty.NewPrinter(popt, pretty.WriteTo(&gotw))
+			require.NoErrorf(t, err, "couldn't set up pretty printer for profile %q", cname)
+			require.NoErrorf(t, pp.Write(*an), "couldn't write analysis for profile %q", cname)
+			require.Equal(t, want, gotw.String())
+		})
+	}
+}
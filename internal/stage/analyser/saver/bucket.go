@@ -11,6 +11,7 @@ import (
 	"github.com/c4-project/c4t/internal/helper/errhelp"
 
 	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/subject/confirm"
 	"github.com/c4-project/c4t/internal/subject/corpus"
 	"github.com/c4-project/c4t/internal/subject/status"
 )
@@ -25,33 +26,63 @@ type bucketSaver struct {
 }
 
 func (b *bucketSaver) save(c corpus.Corpus) error {
-	if err := b.paths.Prepare(); err != nil {
+	for v, vc := range byVerdict(b.s, c) {
+		if err := b.saveVerdict(b.paths.Confirmed(v), vc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// byVerdict partitions c by the confirmation verdicts of its subjects' runs with status st.
+//
+// Where a subject has several such runs, it goes into the partition of the most reproducible verdict; this errs on
+// the side of keeping subjects out of the 'not reproduced' partition, so that they get triaged.
+func byVerdict(st status.Status, c corpus.Corpus) map[confirm.Verdict]corpus.Corpus {
+	cs := make(map[confirm.Verdict]corpus.Corpus)
+	for n, s := range c {
+		v := confirm.Unchecked
+		for _, cc := range s.Compilations {
+			if cc.Run != nil && cc.Run.Status == st && v < cc.Run.Confirmation {
+				v = cc.Run.Confirmation
+			}
+		}
+		if cs[v] == nil {
+			cs[v] = make(corpus.Corpus)
+		}
+		cs[v][n] = s
+	}
+	return cs
+}
+
+func (b *bucketSaver) saveVerdict(paths *RunPathset, c corpus.Corpus) error {
+	if err := paths.Prepare(); err != nil {
 		return err
 	}
-	if err := b.writePlan(c); err != nil {
+	if err := b.writePlan(paths, c); err != nil {
 		return err
 	}
-	return b.archiveSubjects(c)
+	return b.archiveSubjects(paths, c)
 }
 
-func (b *bucketSaver) writePlan(c corpus.Corpus) error {
+func (b *bucketSaver) writePlan(paths *RunPathset, c corpus.Corpus) error {
 	// TODO(@MattWindsor91): we do this because c has non-normalised names, but it still seems a bit extraneous.
 	pn := *b.plan
 	pn.Corpus = pn.Corpus.FilterToNames(c.Names()...)
-	return pn.WriteFile(b.paths.FilePlan, plan.WriteCompress|plan.WriteHuman)
+	return pn.WriteFile(paths.FilePlan, plan.WriteCompress|plan.WriteHuman)
 }
 
-func (b *bucketSaver) archiveSubjects(corp corpus.Corpus) error {
+func (b *bucketSaver) archiveSubjects(paths *RunPathset, corp corpus.Corpus) error {
 	for name := range corp {
-		if err := b.archiveSubject(name); err != nil {
+		if err := b.archiveSubject(paths, name); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (b *bucketSaver) archiveSubject(name string) error {
-	path := b.paths.SubjectTarFile(name)
+func (b *bucketSaver) archiveSubject(paths *RunPathset, name string) error {
+	path := paths.SubjectTarFile(name)
 	ar, err := b.parent.archiveMaker(path)
 	if err != nil {
 		return err
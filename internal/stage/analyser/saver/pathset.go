@@ -16,18 +16,28 @@ import (
 	"github.com/c4-project/c4t/internal/helper/iohelp"
 
 	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/subject/confirm"
 	"github.com/c4-project/c4t/internal/subject/status"
 )
 
 const (
 	planBasename       = "plan"
 	segFlagged         = "flagged"
+	segForbidden       = "forbidden"
 	segCompileFailures = "compile_fail"
 	segCompileTimeouts = "compile_timeout"
 	segRunFailures     = "run_fail"
 	segRunTimeouts     = "run_timeout"
 )
 
+// confirmSegs maps each confirmation verdict to the subdirectory in which subjects with that verdict are saved.
+// Unchecked subjects are saved directly in the run directory.
+var confirmSegs = [...]string{
+	confirm.NotReproduced: "not_reproduced",
+	confirm.Intermittent:  "intermittent",
+	confirm.Reproducible:  "reproducible",
+}
+
 // Pathset contains the pre-computed paths for saving 'interesting' run results.
 type Pathset struct {
 	// Dirs maps 'interesting' statuses to directories.
@@ -39,6 +49,7 @@ func NewPathset(root string) *Pathset {
 	return &Pathset{
 		Dirs: [...]string{
 			status.Flagged:        filepath.Join(root, segFlagged),
+			status.Forbidden:      filepath.Join(root, segForbidden),
 			status.CompileFail:    filepath.Join(root, segCompileFailures),
 			status.CompileTimeout: filepath.Join(root, segCompileTimeouts),
 			status.RunFail:        filepath.Join(root, segRunFailures),
@@ -86,6 +97,19 @@ func runRoot(root string, iterTime time.Time) string {
 	)
 }
 
+// Confirmed gets the pathset within this one into which subjects with confirmation verdict v should be saved.
+// This is the pathset itself for unchecked subjects, and a subdirectory for each other verdict.
+func (s *RunPathset) Confirmed(v confirm.Verdict) *RunPathset {
+	if v == confirm.Unchecked || confirm.Last < v {
+		return s
+	}
+	root := filepath.Join(s.DirRoot, confirmSegs[v])
+	return &RunPathset{
+		DirRoot:  root,
+		FilePlan: filepath.Join(root, planBasename+plan.ExtCompress),
+	}
+}
+
 // SubjectTarFile gets the path to which a tarball for subject sname should be saved.
 func (s *RunPathset) SubjectTarFile(sname string) string {
 	return s.subjectFile(sname + normpath.TarSuffix)
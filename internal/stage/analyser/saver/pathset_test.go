@@ -17,6 +17,7 @@ import (
 
 	"github.com/c4-project/c4t/internal/stage/analyser/saver"
 
+	"github.com/c4-project/c4t/internal/subject/confirm"
 	"github.com/c4-project/c4t/internal/subject/status"
 )
 
@@ -30,6 +31,7 @@ func ExampleNewPathset() {
 
 	// Unordered output:
 	// Flagged: saved/flagged
+	// Forbidden: saved/forbidden
 	// CompileFail: saved/compile_fail
 	// CompileTimeout: saved/compile_timeout
 	// RunFail: saved/run_fail
@@ -60,6 +62,22 @@ func ExampleRunPathset_SubjectTarFile() {
 	// saved/compile_fail/2015/10/21/07_28_00/foo.tar.gz
 }
 
+// ExampleRunPathset_Confirmed is a runnable example for Confirmed.
+func ExampleRunPathset_Confirmed() {
+	p := saver.NewPathset("saved")
+	t := time.Date(2015, time.October, 21, 7, 28, 0, 0, time.FixedZone("UTC-8", -8*60*60))
+	rp, _ := p.SubjectRun(status.Flagged, t)
+	for v := confirm.Unchecked; v <= confirm.Last; v++ {
+		fmt.Println(filepath.ToSlash(rp.Confirmed(v).SubjectTarFile("foo")))
+	}
+
+	// Output:
+	// saved/flagged/2015/10/21/07_28_00/foo.tar.gz
+	// saved/flagged/2015/10/21/07_28_00/not_reproduced/foo.tar.gz
+	// saved/flagged/2015/10/21/07_28_00/intermittent/foo.tar.gz
+	// saved/flagged/2015/10/21/07_28_00/reproducible/foo.tar.gz
+}
+
 // TestPathset_SubjectRun_errors tests several error cases for SubjectRun.
 func TestPathset_SubjectRun_errors(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,278 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package bisector contains the part of the tester framework that finds which optimisation passes cause a bad run.
+//
+// The bisector takes a plan containing a compilation with a bad run, asks the compiler which individual passes its
+// selected optimisation level enables, and then delta-debugs over those passes.  Each candidate disables every pass
+// not in the candidate set, then recompiles and reruns the subject; the bisector reports the smallest set of enabled
+// passes that still reproduces the same bad status.
+package bisector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/c4-project/c4t/internal/helper/ddmin"
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/confirm"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+var (
+	// ErrInvokerNil occurs when a bisector is constructed without an invoker.
+	ErrInvokerNil = errors.New("invoker nil")
+
+	// ErrPassControlNil occurs when a bisector is constructed without a pass controller.
+	ErrPassControlNil = errors.New("pass controller nil")
+
+	// ErrRunnerNil occurs when a bisector is constructed without a service factory for listing passes.
+	ErrRunnerNil = errors.New("service factory nil")
+
+	// ErrNoCompilation occurs when a bisector is constructed without a compilation to bisect.
+	ErrNoCompilation = errors.New("no compilation supplied")
+
+	// ErrMissingSubject occurs when the subject to bisect isn't in the plan.
+	ErrMissingSubject = errors.New("subject not in plan")
+
+	// ErrNotBad occurs when the compilation to bisect doesn't have a bad run.
+	ErrNotBad = errors.New("compilation has no bad run to bisect")
+
+	// ErrNotReproduced occurs when rerunning the compilation with every pass enabled doesn't reproduce its bad run.
+	ErrNotReproduced = errors.New("bad run doesn't reproduce with all passes enabled")
+)
+
+// Bisector holds the main configuration for the bisection part of the tester framework.
+type Bisector struct {
+	// invoker is the runner used to recompile and rerun each candidate.
+	// It must permit re-invocation of plans that have already been invoked.
+	invoker plan.Runner
+
+	// passes is used to list and disable the compiler's optimisation passes.
+	passes compiler.PassController
+
+	// services makes the service runners used to list the compiler's optimisation passes.
+	services ServiceFactory
+
+	// name is the name of the compilation to bisect.
+	name compilation.Name
+
+	// obs track the bisector's progress.
+	obs []Observer
+}
+
+// ServiceFactory is the interface of factories that make service runners for the machine of a plan.
+type ServiceFactory interface {
+	// MakeServiceRunner makes a service runner that runs services on the machine of p.
+	MakeServiceRunner(p *plan.Plan) (service.Runner, error)
+
+	// Closer captures that service factories can be closed, for instance to close any SSH connections.
+	io.Closer
+}
+
+// New constructs a new Bisector that reruns candidates through invoker inv, controlling passes with pc.
+// It lists passes by running the compiler, on the plan's machine, through a runner made by sf.
+//
+// The bisector takes ownership of inv and sf, and will close them when closed.
+func New(inv plan.Runner, pc compiler.PassController, sf ServiceFactory, os ...Option) (*Bisector, error) {
+	if inv == nil {
+		return nil, ErrInvokerNil
+	}
+	if pc == nil {
+		return nil, ErrPassControlNil
+	}
+	if sf == nil {
+		return nil, ErrRunnerNil
+	}
+	b := Bisector{invoker: inv, passes: pc, services: sf}
+	if err := Options(os...)(&b); err != nil {
+		return nil, err
+	}
+	if b.name.SubjectName == "" || b.name.CompilerID.IsEmpty() {
+		return nil, ErrNoCompilation
+	}
+	return &b, nil
+}
+
+// Stage gets the stage for this Bisector.
+func (*Bisector) Stage() stage.Stage {
+	return stage.Bisect
+}
+
+// Close closes the bisector's invoker and service factory.
+func (b *Bisector) Close() error {
+	ierr := b.invoker.Close()
+	serr := b.services.Close()
+	return errhelp.FirstError(ierr, serr)
+}
+
+// Run bisects the configured compilation in p, returning a plan containing its rerun with the smallest set of
+// reproducing passes.
+func (b *Bisector) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
+	if err := checkPlan(p); err != nil {
+		return nil, err
+	}
+	s, ok := p.Corpus[b.name.SubjectName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMissingSubject, b.name.SubjectName)
+	}
+	want, err := badStatus(s, b.name)
+	if err != nil {
+		return nil, err
+	}
+	inst, err := p.Compilers.Get(b.name.CompilerID)
+	if err != nil {
+		return nil, err
+	}
+	// The passes depend on the exact compiler binary, so we must list them on the machine that ran the compilation.
+	sr, err := b.services.MakeServiceRunner(p)
+	if err != nil {
+		return nil, err
+	}
+	passes, err := b.passes.OptPasses(ctx, &inst, sr)
+	if err != nil {
+		return nil, err
+	}
+
+	j := job{base: *p, subject: s, inst: inst, passes: passes, want: want}
+	OnBisect(start(b.name, want, len(passes)), b.obs...)
+	keep, err := b.bisect(ctx, &j)
+	if err != nil {
+		return nil, err
+	}
+
+	// Later candidates will have overwritten the invoker's working files, so we rerun the winner.
+	_, rp, err := b.try(ctx, &j, keep)
+	if err != nil {
+		return nil, err
+	}
+	OnBisect(end(j.enabled(keep)), b.obs...)
+	return rp, nil
+}
+
+func checkPlan(p *plan.Plan) error {
+	if p == nil {
+		return plan.ErrNil
+	}
+	if err := p.Check(); err != nil {
+		return err
+	}
+	return p.Metadata.RequireStage(stage.Invoke)
+}
+
+// badStatus gets the bad status of the run of n in s.
+func badStatus(s subject.Subject, n compilation.Name) (status.Status, error) {
+	rr, err := s.RunResult(n.CompilerID)
+	if err != nil {
+		return status.Unknown, err
+	}
+	if !confirm.Confirmable(rr.Status) {
+		return status.Unknown, fmt.Errorf("%w: %s has status %s", ErrNotBad, n, rr.Status)
+	}
+	return rr.Status, nil
+}
+
+// job holds the state of a single bisection.
+type job struct {
+	// base is the plan on which each candidate plan is based.
+	base plan.Plan
+	// subject is the original subject.
+	subject subject.Subject
+	// inst is the original compiler instance.
+	inst compiler.Instance
+	// passes lists every pass enabled by inst.
+	passes []string
+	// want is the status being reproduced.
+	want status.Status
+	// ncands is the number of candidates tried so far.
+	ncands int
+}
+
+// enabled gets the names of the passes at indices keep.
+func (j *job) enabled(keep []int) []string {
+	ps := make([]string, len(keep))
+	for i, k := range keep {
+		ps[i] = j.passes[k]
+	}
+	return ps
+}
+
+// disabled gets the names of the passes not at indices keep, which must be ascending.
+func (j *job) disabled(keep []int) []string {
+	ps := make([]string, 0, len(j.passes)-len(keep))
+	for i, p := range j.passes {
+		if len(keep) != 0 && keep[0] == i {
+			keep = keep[1:]
+			continue
+		}
+		ps = append(ps, p)
+	}
+	return ps
+}
+
+// bisect finds the smallest set of passes, as indices into j.passes, that still reproduces the bad status.
+func (b *Bisector) bisect(ctx context.Context, j *job) ([]int, error) {
+	all := make([]int, len(j.passes))
+	for i := range all {
+		all[i] = i
+	}
+	ok, _, err := b.try(ctx, j, all)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotReproduced, b.name)
+	}
+
+	return ddmin.Minimise(ctx, len(j.passes), func(ctx context.Context, keep []int) (bool, error) {
+		ok, _, err := b.try(ctx, j, keep)
+		return ok, err
+	})
+}
+
+// try reruns the subject with only the passes at indices keep enabled.
+// It returns whether the rerun reproduced the bad status, and the invoked plan.
+func (b *Bisector) try(ctx context.Context, j *job, keep []int) (bool, *plan.Plan, error) {
+	i := j.ncands
+	j.ncands++
+
+	cp, err := b.candidate(j, keep)
+	if err != nil {
+		return false, nil, err
+	}
+	if cp, err = cp.RunStage(ctx, b.invoker); err != nil {
+		return false, nil, fmt.Errorf("checking candidate %d: %w", i, err)
+	}
+
+	ok := cp.Corpus.Reproduces(b.name, j.want)
+	OnBisect(step(i, len(keep), ok), b.obs...)
+	return ok, cp, nil
+}
+
+// candidate builds a plan that recompiles the subject with only the passes at indices keep enabled.
+func (b *Bisector) candidate(j *job, keep []int) (*plan.Plan, error) {
+	inst := j.inst
+	if err := b.passes.DisablePasses(&inst, j.disabled(keep)); err != nil {
+		return nil, err
+	}
+
+	s := j.subject
+	s.Compilations = nil
+
+	cp := j.base
+	cp.Metadata.Stages = append([]stage.Record(nil), j.base.Metadata.Stages...)
+	cp.Compilers = compiler.InstanceMap{b.name.CompilerID: inst}
+	cp.Corpus = corpus.Corpus{b.name.SubjectName: s}
+	return &cp, nil
+}
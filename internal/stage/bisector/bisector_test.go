@@ -0,0 +1,247 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package bisector_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/srvrun"
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/observing"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/stage/bisector"
+	"github.com/c4-project/c4t/internal/stage/invoker/runner"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/c4-project/c4t/internal/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	gcc     = id.FromString("gcc")
+	fooName = compilation.Name{SubjectName: "foo", CompilerID: gcc}
+)
+
+// TestNew_errors tests the error result of New in various situations.
+func TestNew_errors(t *testing.T) {
+	t.Parallel()
+
+	opterr := errors.New("oopsie")
+	sr := &runner.FromPlanServiceFactory{Local: srvrun.DryRunner{}}
+	ok := []bisector.Option{bisector.ForCompilation(fooName)}
+
+	cases := map[string]struct {
+		inv plan.Runner
+		pc  compiler.PassController
+		sr  bisector.ServiceFactory
+		os  []bisector.Option
+		err error
+	}{
+		"ok":             {inv: &fakeInvoker{}, pc: fakePasses{}, sr: sr, os: ok},
+		"nil-invoker":    {pc: fakePasses{}, sr: sr, os: ok, err: bisector.ErrInvokerNil},
+		"nil-passes":     {inv: &fakeInvoker{}, sr: sr, os: ok, err: bisector.ErrPassControlNil},
+		"nil-runner":     {inv: &fakeInvoker{}, pc: fakePasses{}, os: ok, err: bisector.ErrRunnerNil},
+		"no-compilation": {inv: &fakeInvoker{}, pc: fakePasses{}, sr: sr, err: bisector.ErrNoCompilation},
+		"nil-observer": {
+			inv: &fakeInvoker{},
+			pc:  fakePasses{},
+			sr:  sr,
+			os:  append(ok, bisector.ObserveWith(nil)),
+			err: bisector.ErrObserverNil,
+		},
+		"opt-err": {
+			inv: &fakeInvoker{},
+			pc:  fakePasses{},
+			sr:  sr,
+			os:  append(ok, func(*bisector.Bisector) error { return opterr }),
+			err: opterr,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := bisector.New(c.inv, c.pc, c.sr, c.os...)
+			testhelp.ExpectErrorIs(t, err, c.err, "constructing bisector")
+		})
+	}
+}
+
+// TestBisector_Run tests running the bisector against an invoker that flags runs needing passes b and d.
+func TestBisector_Run(t *testing.T) {
+	t.Parallel()
+
+	var (
+		inv fakeInvoker
+		obs recordingObserver
+	)
+	b, err := bisector.New(&inv, fakePasses{}, &runner.FromPlanServiceFactory{Local: srvrun.DryRunner{}},
+		bisector.ForCompilation(fooName),
+		bisector.ObserveWith(&obs),
+	)
+	require.NoError(t, err, "constructing bisector")
+
+	p := mockPlan(status.Flagged)
+	p2, err := b.Run(context.Background(), p)
+	require.NoError(t, err, "running bisector")
+
+	require.NotEmpty(t, obs.msgs, "observer should have seen messages")
+	assert.Equal(t, observing.BatchStart, obs.msgs[0].Kind, "first message should be a start")
+	assert.Equal(t, 5, obs.msgs[0].Num, "wrong number of passes")
+	last := obs.msgs[len(obs.msgs)-1]
+	assert.Equal(t, observing.BatchEnd, last.Kind, "last message should be an end")
+	assert.Equal(t, []string{"b", "d"}, last.Passes, "wrong minimal pass set")
+
+	assert.Equal(t, []string{"-g", "-fno-a", "-fno-c", "-fno-e"}, p2.Compilers[gcc].Run.Args, "wrong final compiler args")
+	assert.Equal(t, []string{"-g"}, p.Compilers[gcc].Run.Args, "input plan modified")
+	s := p2.Corpus["foo"]
+	rr, err := s.RunResult(gcc)
+	require.NoError(t, err, "getting final run")
+	assert.Equal(t, status.Flagged, rr.Status, "final run should be flagged")
+}
+
+// TestBisector_Run_errors tests the error result of running the bisector in various situations.
+func TestBisector_Run_errors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		name compilation.Name
+		st   status.Status
+		err  error
+	}{
+		"missing-subject":   {name: compilation.Name{SubjectName: "bar", CompilerID: gcc}, st: status.Flagged, err: bisector.ErrMissingSubject},
+		"missing-run":       {name: compilation.Name{SubjectName: "foo", CompilerID: id.FromString("clang")}, st: status.Flagged, err: subject.ErrMissingCompilation},
+		"not-bad":           {name: fooName, st: status.Ok, err: bisector.ErrNotBad},
+		"not-reproduced":    {name: fooName, st: status.RunFail, err: bisector.ErrNotReproduced},
+		"missing-compilers": {name: fooName, st: status.Flagged, err: compiler.ErrCompilerMissing},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			b, err := bisector.New(&fakeInvoker{}, fakePasses{}, &runner.FromPlanServiceFactory{Local: srvrun.DryRunner{}}, bisector.ForCompilation(c.name))
+			require.NoError(t, err, "constructing bisector")
+
+			p := mockPlan(c.st)
+			if errors.Is(c.err, compiler.ErrCompilerMissing) {
+				p.Compilers = compiler.InstanceMap{}
+			}
+			_, err = b.Run(context.Background(), p)
+			testhelp.ExpectErrorIs(t, err, c.err, "running bisector")
+		})
+	}
+}
+
+// mockPlan makes an invoked plan whose subject foo has status st on gcc.
+func mockPlan(st status.Status) *plan.Plan {
+	p := plan.Mock()
+	p.Metadata.ConfirmStage(stage.Invoke, timing.Span{})
+	p.Compilers = compiler.InstanceMap{gcc: {Compiler: compiler.Compiler{Run: service.NewRunInfo("gcc", "-g")}}}
+	p.Corpus = corpus.Corpus{
+		"foo": *subject.NewOrPanic(
+			litmus.NewOrPanic("foo.litmus"),
+			subject.WithRun(gcc, compilation.RunResult{Result: compilation.Result{Status: st}}),
+		),
+	}
+	return p
+}
+
+// TestBisector_Run_services tests that the bisector lists passes through a runner made for the plan's machine.
+func TestBisector_Run_services(t *testing.T) {
+	t.Parallel()
+
+	sf := failingServices{err: errors.New("can't reach machine")}
+	b, err := bisector.New(&fakeInvoker{}, fakePasses{}, &sf, bisector.ForCompilation(fooName))
+	require.NoError(t, err, "constructing bisector")
+
+	p := mockPlan(status.Flagged)
+	_, err = b.Run(context.Background(), p)
+	testhelp.ExpectErrorIs(t, err, sf.err, "running bisector")
+	assert.Same(t, p, sf.p, "service factory should get the plan being bisected")
+}
+
+// failingServices is a service factory that records the plan it is given, then fails with err.
+type failingServices struct {
+	err error
+	p   *plan.Plan
+}
+
+func (f *failingServices) MakeServiceRunner(p *plan.Plan) (service.Runner, error) {
+	f.p = p
+	return nil, f.err
+}
+
+func (*failingServices) Close() error {
+	return nil
+}
+
+// fakePasses is a pass controller that reports passes a to e, and disables them by adding '-fno-' flags.
+type fakePasses struct{}
+
+func (fakePasses) OptPasses(context.Context, *compiler.Instance, service.Runner) ([]string, error) {
+	return []string{"a", "b", "c", "d", "e"}, nil
+}
+
+func (fakePasses) DisablePasses(c *compiler.Instance, passes []string) error {
+	run := *c.Run
+	run.Args = append([]string(nil), run.Args...)
+	for _, p := range passes {
+		run.Args = append(run.Args, "-fno-"+p)
+	}
+	c.Run = &run
+	return nil
+}
+
+// fakeInvoker flags every run whose compiler leaves both passes b and d enabled.
+type fakeInvoker struct{}
+
+func (*fakeInvoker) Stage() stage.Stage {
+	return stage.Invoke
+}
+
+func (*fakeInvoker) Run(_ context.Context, p *plan.Plan) (*plan.Plan, error) {
+	np := *p
+	np.Corpus = make(corpus.Corpus, len(p.Corpus))
+	for sname, s := range p.Corpus {
+		s.Compilations = make(compilation.Map, len(p.Compilers))
+		for cid, c := range p.Compilers {
+			st := status.Flagged
+			for _, arg := range c.Run.Args {
+				if arg == "-fno-b" || arg == "-fno-d" {
+					st = status.Ok
+				}
+			}
+			s.Compilations[cid] = compilation.Compilation{Run: &compilation.RunResult{Result: compilation.Result{Status: st}}}
+		}
+		np.Corpus[sname] = s
+	}
+	return &np, nil
+}
+
+func (*fakeInvoker) Close() error {
+	return nil
+}
+
+// recordingObserver records every bisect message it receives.
+type recordingObserver struct {
+	msgs []bisector.Message
+}
+
+func (r *recordingObserver) OnBisect(m bisector.Message) {
+	r.msgs = append(r.msgs, m)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package bisector
+
+import (
+	"github.com/c4-project/c4t/internal/observing"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// Observer is the interface of types that can observe the progress of a bisector.
+type Observer interface {
+	// OnBisect announces that a bisection is starting, stopping, or progressing.
+	OnBisect(m Message)
+}
+
+// Message is the type of messages announcing bisection progress.
+type Message struct {
+	observing.Batch
+
+	// Name contains, on start messages, the compilation being bisected.
+	Name compilation.Name
+
+	// Status contains, on start messages, the bad status that each candidate must reproduce.
+	Status status.Status
+
+	// NPasses contains, on step messages, the number of passes left enabled in the candidate.
+	NPasses int
+
+	// Reproduced contains, on step messages, whether the candidate reproduced the bad status.
+	Reproduced bool
+
+	// Passes contains, on end messages, the smallest set of enabled passes that reproduces the bad status.
+	Passes []string
+}
+
+// OnBisect broadcasts bisect message m to all observers o.
+func OnBisect(m Message, o ...Observer) {
+	for _, obs := range o {
+		obs.OnBisect(m)
+	}
+}
+
+func start(n compilation.Name, s status.Status, npasses int) Message {
+	return Message{Batch: observing.NewBatchStart(npasses), Name: n, Status: s}
+}
+
+func step(i, npasses int, repro bool) Message {
+	return Message{Batch: observing.NewBatchStep(i), NPasses: npasses, Reproduced: repro}
+}
+
+func end(passes []string) Message {
+	return Message{Batch: observing.NewBatchEnd(), Passes: passes}
+}
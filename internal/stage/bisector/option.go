@@ -0,0 +1,51 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package bisector
+
+import (
+	"errors"
+
+	"github.com/c4-project/c4t/internal/subject/compilation"
+)
+
+// ErrObserverNil occurs when we try to pass a nil observer as an option.
+var ErrObserverNil = errors.New("observer nil")
+
+// Option is the type of options to pass to New.
+type Option func(*Bisector) error
+
+// Options bundles up each option in os into a single option.
+func Options(os ...Option) Option {
+	return func(b *Bisector) error {
+		for _, o := range os {
+			if err := o(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ObserveWith adds each observer in obs to the bisector's observer list.
+func ObserveWith(obs ...Observer) Option {
+	return func(b *Bisector) error {
+		for _, o := range obs {
+			if o == nil {
+				return ErrObserverNil
+			}
+		}
+		b.obs = append(b.obs, obs...)
+		return nil
+	}
+}
+
+// ForCompilation sets the name of the compilation whose bad run should be bisected.
+func ForCompilation(n compilation.Name) Option {
+	return func(b *Bisector) error {
+		b.name = n
+		return nil
+	}
+}
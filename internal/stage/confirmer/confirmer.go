@@ -0,0 +1,179 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package confirmer contains the part of the tester framework that re-runs bad results to see whether they reproduce.
+//
+// The confirmer builds a cut-down plan containing only those subjects with bad run results, invokes it a configurable
+// number of times on the same machine, and records whether each bad result recurred in every, some, or none of those
+// repeats.
+package confirmer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/confirm"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/corpus/builder"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// ErrInvokerNil occurs when a confirmer is constructed without an invoker.
+var ErrInvokerNil = errors.New("invoker nil")
+
+// Confirmer holds the main configuration for the confirmation part of the tester framework.
+type Confirmer struct {
+	// invoker is the runner used to re-invoke bad subjects.
+	// It must permit re-invocation of plans that have already been invoked.
+	invoker plan.Runner
+
+	// quantities contains the confirmer's quantity set.
+	quantities quantity.ConfirmSet
+
+	// obs track the confirmer's progress across a corpus.
+	obs []builder.Observer
+}
+
+// New constructs a new Confirmer that re-runs subjects through invoker inv, with options os.
+//
+// The confirmer takes ownership of inv, and will close it when closed.
+func New(inv plan.Runner, os ...Option) (*Confirmer, error) {
+	if inv == nil {
+		return nil, ErrInvokerNil
+	}
+	c := Confirmer{invoker: inv}
+	if err := Options(os...)(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Stage gets the stage for this Confirmer.
+func (*Confirmer) Stage() stage.Stage {
+	return stage.Confirm
+}
+
+// Close closes the confirmer's invoker.
+func (c *Confirmer) Close() error {
+	return c.invoker.Close()
+}
+
+// Run re-runs every bad run in p, recording a confirmation verdict against each.
+func (c *Confirmer) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
+	if err := checkPlan(p); err != nil {
+		return nil, err
+	}
+	ts := targets(p.Corpus)
+	if c.quantities.NRepeats <= 0 || len(ts) == 0 {
+		return p, nil
+	}
+
+	mp := miniPlan(p, ts)
+	nrepro, err := c.repeat(ctx, mp, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	outp := *p
+	outp.Corpus, err = c.record(ctx, p.Corpus, mp.Corpus, ts, nrepro)
+	return &outp, err
+}
+
+func checkPlan(p *plan.Plan) error {
+	if p == nil {
+		return plan.ErrNil
+	}
+	if err := p.Check(); err != nil {
+		return err
+	}
+	return p.Metadata.RequireStage(stage.Invoke)
+}
+
+// targets maps each subject name in c to the original statuses of those of its runs that need confirming.
+func targets(c corpus.Corpus) map[string]map[compilation.Name]status.Status {
+	ts := make(map[string]map[compilation.Name]status.Status)
+	for sname, s := range c {
+		for cid, cc := range s.Compilations {
+			if cc.Run == nil || cc.Run.Confirmation != confirm.Unchecked || !confirm.Confirmable(cc.Run.Status) {
+				continue
+			}
+			if ts[sname] == nil {
+				ts[sname] = make(map[compilation.Name]status.Status)
+			}
+			ts[sname][compilation.Name{SubjectName: sname, CompilerID: cid}] = cc.Run.Status
+		}
+	}
+	return ts
+}
+
+// miniPlan cuts p down to the subjects and compilers mentioned in ts, erasing any existing compilations.
+func miniPlan(p *plan.Plan, ts map[string]map[compilation.Name]status.Status) *plan.Plan {
+	mp := *p
+	// Copying the stage records stops the invoker appending into the original plan's records.
+	mp.Metadata.Stages = append([]stage.Record(nil), p.Metadata.Stages...)
+	mp.Corpus = make(corpus.Corpus, len(ts))
+	mp.Compilers = make(compiler.InstanceMap)
+	for sname, ns := range ts {
+		s := p.Corpus[sname]
+		s.Compilations = nil
+		mp.Corpus[sname] = s
+		for n := range ns {
+			mp.Compilers[n.CompilerID] = p.Compilers[n.CompilerID]
+		}
+	}
+	return &mp
+}
+
+// repeat invokes mp once per repeat, counting the number of times each target run reproduces its original status.
+func (c *Confirmer) repeat(ctx context.Context, mp *plan.Plan, ts map[string]map[compilation.Name]status.Status) (map[compilation.Name]int, error) {
+	nrepro := make(map[compilation.Name]int)
+	for i := 0; i < c.quantities.NRepeats; i++ {
+		rp, err := c.invoker.Run(ctx, mp)
+		if err != nil {
+			return nil, fmt.Errorf("in confirmation repeat %d: %w", i, err)
+		}
+		for _, ns := range ts {
+			for n, want := range ns {
+				if rp.Corpus.Reproduces(n, want) {
+					nrepro[n]++
+				}
+			}
+		}
+	}
+	return nrepro, nil
+}
+
+// record records the verdicts implied by nrepro against each target in ts, building a copy of corpus in.
+// The corpus src should contain every subject mentioned in ts.
+func (c *Confirmer) record(ctx context.Context, in, src corpus.Corpus, ts map[string]map[compilation.Name]status.Status, nrepro map[compilation.Name]int) (corpus.Corpus, error) {
+	nreqs := 0
+	for _, ns := range ts {
+		nreqs += len(ns)
+	}
+	cfg := builder.Config{
+		Init:      in,
+		Observers: c.obs,
+		Manifest: builder.Manifest{
+			Name:  "confirm",
+			NReqs: nreqs,
+		},
+	}
+	return builder.ParBuild(ctx, 1, src, cfg, func(ctx context.Context, s subject.Named, rq chan<- builder.Request) error {
+		for n := range ts[s.Name] {
+			v := confirm.FromCounts(nrepro[n], c.quantities.NRepeats)
+			if err := builder.ConfirmRequest(n, v).SendTo(ctx, rq); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package confirmer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/confirmer"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/confirm"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/c4-project/c4t/internal/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNew_errors tests the error result of New in various situations.
+func TestNew_errors(t *testing.T) {
+	t.Parallel()
+
+	opterr := errors.New("oopsie")
+
+	cases := map[string]struct {
+		inv plan.Runner
+		os  []confirmer.Option
+		err error
+	}{
+		"ok":          {inv: &fakeInvoker{}},
+		"nil-invoker": {err: confirmer.ErrInvokerNil},
+		"nil-observer": {
+			inv: &fakeInvoker{},
+			os:  []confirmer.Option{confirmer.ObserveWith(nil)},
+			err: confirmer.ErrObserverNil,
+		},
+		"opt-err": {
+			inv: &fakeInvoker{},
+			os:  []confirmer.Option{func(*confirmer.Confirmer) error { return opterr }},
+			err: opterr,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := confirmer.New(c.inv, c.os...)
+			testhelp.ExpectErrorIs(t, err, c.err, "constructing confirmer")
+		})
+	}
+}
+
+// TestConfirmer_Run tests running the confirmer on a small plan with a scripted invoker.
+func TestConfirmer_Run(t *testing.T) {
+	t.Parallel()
+
+	gcc, clang := id.FromString("gcc"), id.FromString("clang")
+
+	inv := fakeInvoker{results: []corpus.Corpus{
+		{
+			"foo": *subject.NewOrPanic(litmus.NewOrPanic("foo.litmus"), subject.WithRun(gcc, mockRun(status.Flagged))),
+			"bar": *subject.NewOrPanic(litmus.NewOrPanic("bar.litmus"), subject.WithRun(gcc, mockRun(status.RunFail))),
+		},
+		{
+			"foo": *subject.NewOrPanic(litmus.NewOrPanic("foo.litmus"), subject.WithRun(gcc, mockRun(status.Ok))),
+			"bar": *subject.NewOrPanic(litmus.NewOrPanic("bar.litmus"), subject.WithRun(gcc, mockRun(status.RunFail))),
+		},
+	}}
+	c, err := confirmer.New(&inv, confirmer.OverrideQuantities(quantity.ConfirmSet{NRepeats: 2}))
+	require.NoError(t, err, "constructing confirmer")
+
+	p := plan.Mock()
+	p.Metadata.ConfirmStage(stage.Invoke, timing.Span{})
+	p.Compilers = compiler.InstanceMap{gcc: {}, clang: {}}
+	p.Corpus = corpus.Corpus{
+		"foo": *subject.NewOrPanic(
+			litmus.NewOrPanic("foo.litmus"),
+			subject.WithRun(gcc, mockRun(status.Flagged)),
+			subject.WithRun(clang, mockRun(status.Ok)),
+		),
+		"bar": *subject.NewOrPanic(litmus.NewOrPanic("bar.litmus"), subject.WithRun(gcc, mockRun(status.RunFail))),
+		"baz": *subject.NewOrPanic(litmus.NewOrPanic("baz.litmus"), subject.WithRun(gcc, mockRun(status.RunTimeout))),
+	}
+	// baz's result never appears in the invoker's results, and so should count as not reproduced.
+
+	p2, err := c.Run(context.Background(), p)
+	require.NoError(t, err, "running confirmer")
+
+	require.Len(t, inv.plans, 2, "wrong number of invocations")
+	mp := inv.plans[0]
+	assert.ElementsMatch(t, []string{"bar", "baz", "foo"}, mp.Corpus.Names(), "wrong mini-plan subjects")
+	assert.Empty(t, mp.Corpus["foo"].Compilations, "mini-plan should have no compilations")
+	assert.Contains(t, mp.Compilers, gcc, "mini-plan should contain gcc")
+	assert.NotContains(t, mp.Compilers, clang, "mini-plan shouldn't contain clang")
+	assert.NotSame(t, &p.Metadata.Stages[0], &mp.Metadata.Stages[0], "mini-plan should have its own stage records")
+
+	for _, c := range []struct {
+		sub  string
+		cid  id.ID
+		want confirm.Verdict
+	}{
+		{sub: "foo", cid: gcc, want: confirm.Intermittent},
+		{sub: "foo", cid: clang, want: confirm.Unchecked},
+		{sub: "bar", cid: gcc, want: confirm.Reproducible},
+		{sub: "baz", cid: gcc, want: confirm.NotReproduced},
+	} {
+		s := p2.Corpus[c.sub]
+		rr, err := s.RunResult(c.cid)
+		if assert.NoError(t, err, "getting run result", c.sub, c.cid) {
+			assert.Equal(t, c.want, rr.Confirmation, "wrong verdict", c.sub, c.cid)
+		}
+	}
+	assert.Equal(t, confirm.Unchecked, p.Corpus["foo"].Compilations[gcc].Run.Confirmation, "input plan modified")
+}
+
+// TestConfirmer_Run_disabled tests that the confirmer does nothing if it has no repeats configured.
+func TestConfirmer_Run_disabled(t *testing.T) {
+	t.Parallel()
+
+	var inv fakeInvoker
+	c, err := confirmer.New(&inv)
+	require.NoError(t, err, "constructing confirmer")
+
+	p := plan.Mock()
+	p.Metadata.ConfirmStage(stage.Invoke, timing.Span{})
+	p.Corpus = corpus.Corpus{
+		"foo": *subject.NewOrPanic(litmus.NewOrPanic("foo.litmus"), subject.WithRun(id.FromString("gcc"), mockRun(status.Flagged))),
+	}
+
+	p2, err := c.Run(context.Background(), p)
+	require.NoError(t, err, "running confirmer")
+	assert.Same(t, p, p2, "disabled confirmer should pass plan through")
+	assert.Empty(t, inv.plans, "disabled confirmer shouldn't invoke")
+}
+
+func mockRun(s status.Status) compilation.RunResult {
+	return compilation.RunResult{Result: compilation.Result{Status: s}}
+}
+
+// fakeInvoker is a plan runner that returns a scripted corpus on each run.
+type fakeInvoker struct {
+	// results contains the corpus returned on each run; runs past the end return an empty corpus.
+	results []corpus.Corpus
+	// plans records each plan passed to the invoker.
+	plans []*plan.Plan
+}
+
+func (*fakeInvoker) Stage() stage.Stage {
+	return stage.Invoke
+}
+
+func (f *fakeInvoker) Run(_ context.Context, p *plan.Plan) (*plan.Plan, error) {
+	np := *p
+	np.Corpus = corpus.Corpus{}
+	if i := len(f.plans); i < len(f.results) {
+		np.Corpus = f.results[i]
+	}
+	f.plans = append(f.plans, p)
+	return &np, nil
+}
+
+func (*fakeInvoker) Close() error {
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package confirmer
+
+import (
+	"errors"
+
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/subject/corpus/builder"
+)
+
+// ErrObserverNil occurs when we try to pass a nil observer as an option.
+var ErrObserverNil = errors.New("observer nil")
+
+// Option is the type of options to pass to New.
+type Option func(*Confirmer) error
+
+// Options bundles up each option in os into a single option.
+func Options(os ...Option) Option {
+	return func(c *Confirmer) error {
+		for _, o := range os {
+			if err := o(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ObserveWith adds each observer in obs to the confirmer's observer list.
+func ObserveWith(obs ...builder.Observer) Option {
+	return func(c *Confirmer) error {
+		for _, o := range obs {
+			if o == nil {
+				return ErrObserverNil
+			}
+		}
+		c.obs = append(c.obs, obs...)
+		return nil
+	}
+}
+
+// OverrideQuantities overrides the confirmer's quantities with qs.
+func OverrideQuantities(qs quantity.ConfirmSet) Option {
+	return func(c *Confirmer) error {
+		c.quantities.Override(qs)
+		return nil
+	}
+}
@@ -58,6 +58,8 @@ type Fuzzer struct {
 	quantities quantity.FuzzSet
 	// config sets various options on the fuzzer itself.
 	config *fuzzer.Config
+	// strata contains the strata used to sample the fuzzed corpus; if empty, sampling is uniform.
+	strata corpus.Strata
 }
 
 // New constructs a fuzzer with the config c and plan p.
@@ -108,8 +110,15 @@ func (f *Fuzzer) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 		return nil, err
 	}
 
+	// If every subject has already failed, there is nothing to fuzz, but the failures should still reach analysis.
+	vc := p.Corpus.Viable()
+	if len(vc) == 0 {
+		np := *p
+		return &np, nil
+	}
+
 	rng := p.Metadata.Rand()
-	fcs, ferr := f.fuzzCorpus(ctx, rng, p.Corpus, p.Machine.Machine)
+	fcs, ferr := f.fuzzCorpus(ctx, rng, vc, p.Machine.Machine)
 	if ferr != nil {
 		return nil, ferr
 	}
@@ -118,11 +127,25 @@ func (f *Fuzzer) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 }
 
 // sampleAndUpdatePlan samples fcs, updates the fresh plan copy p with it, and returns a pointer to it.
+//
+// Only successfully fuzzed subjects count towards the sample; we keep every failed subject (whether it failed during
+// fuzzing or before) so that its failure shows up in the analysis.  If every subject failed to fuzz, the sample is
+// empty.
 func (f *Fuzzer) sampleAndUpdatePlan(fcs corpus.Corpus, rng *rand.Rand, p plan.Plan) (*plan.Plan, error) {
 	// TODO(@MattWindsor91): add some observer calls here?
-	scs, err := fcs.Sample(rng, f.quantities.CorpusSize)
-	if err != nil {
-		return nil, err
+	scs := corpus.Corpus{}
+	if vc := fcs.Viable(); len(vc) != 0 {
+		var err error
+		if scs, err = vc.StratifiedSample(rng, f.quantities.CorpusSize, f.strata); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range []corpus.Corpus{p.Corpus, fcs} {
+		for n, s := range c {
+			if s.HasFailed() {
+				scs[n] = s
+			}
+		}
 	}
 
 	p.Corpus = scs
@@ -145,7 +168,7 @@ func (f *Fuzzer) fuzzCorpus(ctx context.Context, rng *rand.Rand, c corpus.Corpus
 	seeds := corpusSeeds(rng, c)
 
 	mf := builder.Manifest{Name: "fuzz", NReqs: nfuzzes}
-	bc := builder.Config{Manifest: mf, Observers: f.observers}
+	bc := builder.Config{Manifest: mf, Observers: f.observers, Dedup: true}
 	return builder.ParBuild(ctx, f.quantities.NWorkers, c, bc, func(ctx context.Context, s subject.Named, ch chan<- builder.Request) error {
 		return f.makeInstance(s, seeds[s.Name], m, ch).Fuzz(ctx)
 	})
@@ -168,6 +191,7 @@ func (f *Fuzzer) makeInstance(s subject.Named, seed int64, m machine.Machine, re
 		Driver:        f.driver,
 		Subject:       s,
 		SubjectCycles: f.quantities.SubjectCycles,
+		Timeout:       f.quantities.Timeout,
 		Pathset:       f.paths,
 		Rng:           rand.New(rand.NewSource(seed)),
 		ResCh:         resCh,
@@ -184,7 +208,11 @@ func (f *Fuzzer) checkPlan(p *plan.Plan) error {
 	if err := p.Metadata.RequireStage(stage.Plan); err != nil {
 		return err
 	}
-	return f.checkCount(p.Corpus)
+	// An entirely failed corpus passes straight through the fuzzer, so we only check the size of a viable one.
+	if vc := p.Corpus.Viable(); len(vc) != 0 {
+		return f.checkCount(vc)
+	}
+	return nil
 }
 
 func (f *Fuzzer) checkCount(c corpus.Corpus) error {
@@ -228,3 +228,56 @@ func TestFuzzer_Run_nop(t *testing.T) {
 	mp.AssertExpectations(t)
 	md.AssertExpectations(t)
 }
+
+// TestFuzzer_Run_failed tests that the Fuzzer passes failed subjects through, rather than erroring, when no subject
+// survives fuzzing.
+func TestFuzzer_Run_failed(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		// driver is the single-fuzzer to use.
+		driver fuzzer.SingleFuzzer
+		// prefail, if true, fails every subject before fuzzing.
+		prefail bool
+		// want is the number of failed subjects to expect.
+		want int
+	}{
+		"all-fail-fuzz":     {driver: failFuzzer{}, want: 3 * fuzzer.DefaultSubjectFuzzes},
+		"all-failed-before": {driver: fuzzer.NopFuzzer{}, prefail: true, want: 3},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			md := new(mocks2.StatDumper)
+			md.Test(t)
+			mp := new(mocks.SubjectPather)
+			mp.Test(t)
+			mp.On("Prepare").Return(nil).Once()
+			mp.On("SubjectLitmus", mock.Anything).Return("fuzz.litmus").Maybe()
+			mp.On("SubjectTrace", mock.Anything).Return("fuzz.trace.txt").Maybe()
+
+			f, err := fuzzer.New(fuzzer.AggregateDriver{Single: c.driver, Stat: md}, mp)
+			require.NoError(t, err, "unexpected error in New")
+
+			p := makePlan()
+			p.Metadata.ConfirmStage(stage.Plan, timing.SpanFromInstant(time.Now()))
+			if c.prefail {
+				for n, s := range p.Corpus {
+					s.AddFailure(subject.NewFailure(stage.Lift, errors.New("lifter crashed"), false))
+					p.Corpus[n] = s
+				}
+			}
+
+			p2, err := f.Run(context.Background(), p)
+			require.NoError(t, err, "running fuzzer")
+			assert.Len(t, p2.Corpus, c.want, "wrong number of subjects")
+			assert.Empty(t, p2.Corpus.Viable(), "no subject should be viable")
+
+			mp.AssertExpectations(t)
+			md.AssertExpectations(t)
+		})
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/quantity"
 
 	"github.com/c4-project/c4t/internal/model/service/fuzzer"
 
@@ -38,6 +40,9 @@ type Instance struct {
 	// SubjectCycles is the number of times each subject should be fuzzed.
 	SubjectCycles int
 
+	// Timeout is the timeout for each fuzz cycle.
+	Timeout quantity.Timeout
+
 	// Machine is the machine, if any, being targeted by the fuzzer.
 	// Knowledge of the machine can be used to shape things like thread counts.
 	Machine *machine.Machine
@@ -87,17 +92,22 @@ func (j *Instance) fuzzCycle(ctx context.Context, cycle int) error {
 	sc := SubjectCycle{Name: j.Subject.Name, Cycle: cycle}
 	jb := j.makeJob(sc)
 
+	tctx, cancel := j.Timeout.OnContext(ctx)
+	defer cancel()
+
 	stime := time.Now()
-	if err := j.Driver.Fuzz(ctx, jb); err != nil {
-		return err
+	if err := j.Driver.Fuzz(tctx, jb); err != nil {
+		return j.fail(ctx, tctx, sc, err)
 	}
 	dur := time.Since(stime)
 
 	// TODO(@MattWindsor91): should we double-check the architecture here?
-	l, err := litmus.New(jb.OutLitmus, litmus.WithArch(id.ArchC), litmus.PopulateStatsFrom(ctx, j.Driver))
+	l, err := litmus.New(jb.OutLitmus, litmus.WithArch(id.ArchC), litmus.PopulateStatsFrom(tctx, j.Driver))
 	if err != nil {
-		return nil
+		return j.fail(ctx, tctx, sc, err)
 	}
+	// Hashing is only used to drop duplicate outputs, so a subject we can't hash just never counts as a duplicate.
+	l.Hash, _ = litmus.CanonicalHashOfFile(l.Filepath())
 
 	fz := subject.Fuzz{
 		Duration: dur,
@@ -109,6 +119,20 @@ func (j *Instance) fuzzCycle(ctx context.Context, cycle int) error {
 	return builder.AddRequest(&nsub).SendTo(ctx, j.ResCh)
 }
 
+// fail records that fuzz cycle sc failed with err, where tctx is the cycle's timeout context.
+//
+// The failure only propagates if the parent context ctx is done; otherwise, we send the cycled subject with a
+// failure record, so that the rest of the corpus can carry on.
+func (j *Instance) fail(ctx, tctx context.Context, sc SubjectCycle, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	nsub := j.fuzzedSubject(sc, nil)
+	f := subject.NewFailure(stage.Fuzz, err, errors.Is(tctx.Err(), context.DeadlineExceeded))
+	nsub.Failure = &f
+	return builder.AddRequest(&nsub).SendTo(ctx, j.ResCh)
+}
+
 func (j *Instance) makeJob(sc SubjectCycle) fuzzer.Job {
 	jb := fuzzer.Job{
 		Seed:    j.Rng.Int31(),
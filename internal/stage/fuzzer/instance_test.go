@@ -7,14 +7,19 @@ package fuzzer_test
 
 import (
 	"context"
+	"errors"
 	"math/rand"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/c4-project/c4t/internal/model/litmus/mocks"
+	fuzzer2 "github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/quantity"
 
 	"github.com/c4-project/c4t/internal/subject/corpus/builder"
 
@@ -68,3 +73,81 @@ func TestInstance_Fuzz(t *testing.T) {
 
 	md.AssertExpectations(t)
 }
+
+// TestInstance_Fuzz_failure tests that a failing fuzz cycle sends a failed subject, rather than failing the instance.
+func TestInstance_Fuzz_failure(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		fuzzer  fuzzer.SingleFuzzer
+		timeout bool
+	}{
+		"error":   {fuzzer: failFuzzer{}},
+		"timeout": {fuzzer: hangFuzzer{}, timeout: true},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resCh := make(chan builder.Request)
+
+			var md mocks.StatDumper
+			md.Test(t)
+
+			j := fuzzer.Instance{
+				Subject:       subject.Named{Name: "foo"},
+				Driver:        fuzzer.AggregateDriver{Single: c.fuzzer, Stat: &md},
+				SubjectCycles: 2,
+				Timeout:       quantity.Timeout(time.Millisecond),
+				Pathset:       fuzzer.NewPathset("test"),
+				Rng:           rand.New(rand.NewSource(0)),
+				ResCh:         resCh,
+			}
+
+			eg, ectx := errgroup.WithContext(context.Background())
+			eg.Go(func() error {
+				return j.Fuzz(ectx)
+			})
+			eg.Go(func() error {
+				for i := 0; i < 2; i++ {
+					select {
+					case r := <-resCh:
+						wname := fuzzer.SubjectCycle{Name: "foo", Cycle: i}.String()
+						assert.Equal(t, wname, r.Name, "wrong fuzz result name")
+						if assert.NotNil(t, r.Add, "failed fuzz should still be an add request") &&
+							assert.NotNil(t, r.Add.Failure, "failed fuzz should have a failure") {
+							assert.Equal(t, stage.Fuzz, r.Add.Failure.Stage, "wrong failure stage")
+							assert.Equal(t, c.timeout, r.Add.Failure.Timeout, "wrong failure timeout flag")
+							assert.Nil(t, r.Add.Fuzz, "failed fuzz shouldn't have fuzz output")
+						}
+					case <-ectx.Done():
+						return ectx.Err()
+					}
+				}
+				return nil
+			})
+			assert.NoError(t, eg.Wait(), "unexpected errgroup error")
+
+			md.AssertExpectations(t)
+		})
+	}
+}
+
+// failFuzzer is a single-fuzzer that always fails.
+type failFuzzer struct{}
+
+// Fuzz fails.
+func (failFuzzer) Fuzz(context.Context, fuzzer2.Job) error {
+	return errors.New("fuzzer exploded")
+}
+
+// hangFuzzer is a single-fuzzer that waits until its context is done.
+type hangFuzzer struct{}
+
+// Fuzz waits for ctx to finish.
+func (hangFuzzer) Fuzz(ctx context.Context, _ fuzzer2.Job) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
@@ -8,6 +8,7 @@ package fuzzer
 import (
 	"github.com/c4-project/c4t/internal/model/service/fuzzer"
 	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/subject/corpus"
 
 	"github.com/c4-project/c4t/internal/subject/corpus/builder"
 )
@@ -51,3 +52,17 @@ func UseConfig(cfg *fuzzer.Config) Option {
 		return nil
 	}
 }
+
+// UseSampleConfig sets the fuzzer to sample its output corpus using the sampling config cfg, if non-nil.
+func UseSampleConfig(cfg *corpus.SampleConfig) Option {
+	return func(f *Fuzzer) error {
+		if cfg == nil {
+			return nil
+		}
+		if err := cfg.Strata.Check(); err != nil {
+			return err
+		}
+		f.strata = cfg.Strata
+		return nil
+	}
+}
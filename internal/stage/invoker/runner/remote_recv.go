@@ -12,6 +12,7 @@ import (
 	copy2 "github.com/c4-project/c4t/internal/copier"
 
 	"github.com/c4-project/c4t/internal/model/filekind"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
 
 	"github.com/c4-project/c4t/internal/plan"
 	"github.com/c4-project/c4t/internal/subject"
@@ -19,10 +20,11 @@ import (
 	"github.com/c4-project/c4t/internal/subject/normaliser"
 )
 
-// Recv copies bits of remp into locp, including run information and any compiler failures.
+// Recv copies bits of remp into locp, including run information, any compiler failures, and compiler versions.
 // It uses SFTP to transfer back any compile logs.
 func (r *RemoteRunner) Recv(ctx context.Context, locp, remp *plan.Plan) (*plan.Plan, error) {
 	locp.Metadata.Stages = remp.Metadata.Stages
+	locp.Compilers = mergeVersions(locp.Compilers, remp.Compilers)
 
 	norm := normaliser.NewCorpus(r.localRoot)
 	ncorp, err := norm.Normalise(remp.Corpus)
@@ -36,6 +38,18 @@ func (r *RemoteRunner) Recv(ctx context.Context, locp, remp *plan.Plan) (*plan.P
 	return locp, r.recvMapping(ctx, norm.Mappings.RenamesMatching(filekind.Any, filekind.InCompile))
 }
 
+// mergeVersions returns a copy of the local compilers lcs with any versions probed on the remote compilers rcs.
+func mergeVersions(lcs, rcs compiler.InstanceMap) compiler.InstanceMap {
+	ncs := make(compiler.InstanceMap, len(lcs))
+	for cid, lc := range lcs {
+		if rc, ok := rcs[cid]; ok && rc.Version != nil {
+			lc.Version = rc.Version
+		}
+		ncs[cid] = lc
+	}
+	return ncs
+}
+
 func (r *RemoteRunner) mergeSubjects(locp *plan.Plan, rcorp corpus.Corpus) error {
 	return locp.Corpus.Map(func(sn *subject.Named) error {
 		return r.mergeSubject(sn, rcorp)
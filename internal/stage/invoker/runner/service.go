@@ -0,0 +1,60 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package runner
+
+import (
+	"io"
+
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/remote"
+)
+
+// FromPlanServiceFactory makes service runners that run commands on the machine of the plan passed to them: over SSH
+// if the machine is remote, and through Local otherwise.
+//
+// Like FromPlanFactory, this is useful for single-shot tools that need to run services (such as compilers) on a plan's
+// machine, outside of the machine node.  It connects to the machine of the first remote plan passed to it, and reuses
+// that connection thereafter.
+type FromPlanServiceFactory struct {
+	// Config is the global remoting config used for any remote connections initiated by this factory.
+	Config *remote.Config
+
+	// Local is the service runner used for plans whose machine is local.
+	Local service.Runner
+
+	// Stderr, if non-nil, receives the standard error of any remote services.
+	Stderr io.Writer
+
+	machine *remote.MachineRunner
+}
+
+// MakeServiceRunner makes a service runner for the machine of p.
+func (f *FromPlanServiceFactory) MakeServiceRunner(p *plan.Plan) (service.Runner, error) {
+	mc := p.Machine.SSH
+	if mc == nil {
+		return f.Local, nil
+	}
+	if f.machine == nil {
+		var err error
+		if f.machine, err = mc.MachineRunner(f.Config); err != nil {
+			return nil, err
+		}
+	}
+	sr := f.machine.ServiceRunner()
+	if f.Stderr == nil {
+		return sr, nil
+	}
+	return sr.WithStderr(f.Stderr), nil
+}
+
+// Close closes any SSH connection opened by this factory.
+func (f *FromPlanServiceFactory) Close() error {
+	if f.machine == nil {
+		return nil
+	}
+	return f.machine.Close()
+}
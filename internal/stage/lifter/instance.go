@@ -7,9 +7,13 @@ package lifter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/quantity"
+
 	"github.com/c4-project/c4t/internal/model/service"
 
 	"github.com/c4-project/c4t/internal/model/service/backend"
@@ -38,6 +42,9 @@ type Instance struct {
 	// Subject is the subject that we are trying to lift.
 	Subject subject.Named
 
+	// Timeout is the timeout for lifting the subject to each architecture.
+	Timeout quantity.Timeout
+
 	// ResCh is the channel onto which each fuzzed subject should be sent.
 	ResCh chan<- builder.Request
 }
@@ -74,7 +81,7 @@ func (j *Instance) liftArch(ctx context.Context, arch id.ID) error {
 
 	lit, perr := j.Subject.BestLitmus()
 	if perr != nil {
-		return perr
+		return j.fail(ctx, nil, perr)
 	}
 
 	// TODO(@MattWindsor91): don't hardcode this
@@ -87,15 +94,31 @@ func (j *Instance) liftArch(ctx context.Context, arch id.ID) error {
 		},
 	}
 
-	r, err := j.Driver.Lift(ctx, spec, j.Runner)
+	tctx, cancel := j.Timeout.OnContext(ctx)
+	defer cancel()
+
+	r, err := j.Driver.Lift(tctx, spec, j.Runner)
 	if err != nil {
 		sname := reflect.TypeOf(j.Driver).Name()
-		return &Error{Subject: &j.Subject, ServiceName: sname, Job: spec, Inner: err}
+		return j.fail(ctx, tctx, &Error{Subject: &j.Subject, ServiceName: sname, Job: spec, Inner: err})
 	}
 
 	return builder.RecipeRequest(j.Subject.Name, arch, r).SendTo(ctx, j.ResCh)
 }
 
+// fail records that lifting to one architecture failed with err, where tctx (if non-nil) is the lift's timeout
+// context.
+//
+// The failure only propagates if the parent context ctx is done; otherwise, we send a failure request for the
+// subject, so that the rest of the corpus can carry on.
+func (j *Instance) fail(ctx, tctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	timeout := tctx != nil && errors.Is(tctx.Err(), context.DeadlineExceeded)
+	return builder.FailureRequest(j.Subject.Name, subject.NewFailure(stage.Lift, err, timeout)).SendTo(ctx, j.ResCh)
+}
+
 // Error contains an error that occurred while lifting, as well as context.
 type Error struct {
 	// ServiceName is a guess at the name of the service.
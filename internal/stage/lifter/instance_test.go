@@ -0,0 +1,86 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package lifter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/recipe"
+	"github.com/c4-project/c4t/internal/model/service/backend"
+	mocks2 "github.com/c4-project/c4t/internal/model/service/backend/mocks"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/stage/lifter"
+	"github.com/c4-project/c4t/internal/stage/lifter/mocks"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus/builder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+// TestInstance_Lift_failure tests that a failing lift records a failure for that architecture and carries on.
+func TestInstance_Lift_failure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mp mocks.Pather
+		ml mocks2.SingleLifter
+	)
+	mp.Test(t)
+	ml.Test(t)
+
+	x86, arm := id.FromString("x86"), id.FromString("arm")
+	mp.On("Path", mock.Anything, "foo").Return("lift", nil).Twice()
+	ml.On("Lift", mock.Anything, mock.MatchedBy(func(j backend.LiftJob) bool {
+		return j.Arch.Equal(x86)
+	}), mock.Anything).Return(recipe.Recipe{}, errors.New("lifter crashed")).Once()
+	ml.On("Lift", mock.Anything, mock.MatchedBy(func(j backend.LiftJob) bool {
+		return j.Arch.Equal(arm)
+	}), mock.Anything).Return(recipe.Recipe{Dir: "lift"}, nil).Once()
+
+	resCh := make(chan builder.Request)
+	j := lifter.Instance{
+		Arches:  []id.ID{x86, arm},
+		Driver:  &ml,
+		Paths:   &mp,
+		Subject: subject.Named{Name: "foo", Subject: *subject.NewOrPanic(litmus.NewOrPanic("foo.litmus"))},
+		ResCh:   resCh,
+	}
+
+	var rqs []builder.Request
+	eg, ectx := errgroup.WithContext(context.Background())
+	eg.Go(func() error {
+		return j.Lift(ectx)
+	})
+	eg.Go(func() error {
+		for i := 0; i < 2; i++ {
+			select {
+			case r := <-resCh:
+				rqs = append(rqs, r)
+			case <-ectx.Done():
+				return ectx.Err()
+			}
+		}
+		return nil
+	})
+	require.NoError(t, eg.Wait(), "lifting shouldn't fail outright")
+
+	require.Len(t, rqs, 2, "should get one request per architecture")
+	if f := rqs[0].Failure; assert.NotNil(t, f, "first request should be a failure") {
+		assert.Equal(t, stage.Lift, f.Stage, "wrong failure stage")
+		assert.False(t, f.Timeout, "failure shouldn't be a timeout")
+		assert.Contains(t, f.Error, "lifter crashed", "failure should carry error")
+	}
+	assert.NotNil(t, rqs[1].Recipe, "second request should be a recipe")
+
+	mp.AssertExpectations(t)
+	ml.AssertExpectations(t)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/c4-project/c4t/internal/model/service/backend"
 
 	"github.com/c4-project/c4t/internal/helper/srvrun"
+	"github.com/c4-project/c4t/internal/quantity"
 
 	"github.com/c4-project/c4t/internal/subject/corpus"
 
@@ -49,6 +50,9 @@ type Lifter struct {
 
 	// errw is the writer to which standard error (eg from the lifting backend) should be sent.
 	errw io.Writer
+
+	// quantities sets the quantities for this lifter.
+	quantities quantity.LiftSet
 }
 
 // New constructs a new Lifter given backend resolver r, path resolver p, and options os.
@@ -56,7 +60,13 @@ func New(r backend.Resolver, p Pather, os ...Option) (*Lifter, error) {
 	if err := checkConfig(r, p); err != nil {
 		return nil, err
 	}
-	l := Lifter{resolver: r, paths: p}
+	l := Lifter{
+		resolver: r,
+		paths:    p,
+		quantities: quantity.LiftSet{
+			NWorkers: 20,
+		},
+	}
 	if err := Options(os...)(&l); err != nil {
 		return nil, err
 	}
@@ -84,6 +94,9 @@ func (*Lifter) Close() error {
 }
 
 // Run runs a lifting job: taking every test subject in p and using a backend to lift each to a compilable recipe.
+//
+// Subjects that an earlier stage failed to process pass through unlifted.  If lifting a subject fails, the lifter
+// records the failure on the subject and carries on with the rest of the corpus.
 func (l *Lifter) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 	if err := checkPlan(p); err != nil {
 		return nil, err
@@ -100,7 +113,7 @@ func (l *Lifter) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 
 func (l *Lifter) prepareDirs(p *plan.Plan) error {
 	// TODO(@MattWindsor91): observe this?
-	return l.paths.Prepare(p.Arches(), p.Corpus.Names())
+	return l.paths.Prepare(p.Arches(), p.Corpus.Viable().Names())
 }
 
 func checkPlan(p *plan.Plan) error {
@@ -130,8 +143,7 @@ func (l *Lifter) liftCorpus(ctx context.Context, p *plan.Plan) (corpus.Corpus, e
 			NReqs: p.MaxNumRecipes(),
 		},
 	}
-	// TODO(@MattWindsor91): extract this 20 into configuration.
-	return builder.ParBuild(ctx, 20, p.Corpus, cfg, func(ctx context.Context, s subject.Named, rq chan<- builder.Request) error {
+	return builder.ParBuild(ctx, l.quantities.NWorkers, p.Corpus.Viable(), cfg, func(ctx context.Context, s subject.Named, rq chan<- builder.Request) error {
 		j := l.makeJob(p, b, s, rq)
 		return j.Lift(ctx)
 	})
@@ -145,6 +157,7 @@ func (l *Lifter) makeJob(p *plan.Plan, b backend.SingleLifter, s subject.Named,
 		Paths:   l.paths,
 		Driver:  b,
 		Subject: s,
+		Timeout: l.quantities.Timeout,
 		ResCh:   resCh,
 		// TODO(@MattWindsor91): push this further up
 		Runner: srvrun.NewExecRunner(srvrun.StderrTo(l.errw)),
@@ -10,6 +10,7 @@ import (
 	"io"
 
 	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/quantity"
 
 	"github.com/c4-project/c4t/internal/subject/corpus/builder"
 )
@@ -54,3 +55,11 @@ func ObserveWith(obs ...builder.Observer) Option {
 		return nil
 	}
 }
+
+// OverrideQuantities overrides the lifter's quantities with qs.
+func OverrideQuantities(qs quantity.LiftSet) Option {
+	return func(l *Lifter) error {
+		l.quantities.Override(qs)
+		return nil
+	}
+}
@@ -88,7 +88,8 @@ func (*Compiler) Close() error {
 }
 
 // Run runs the batch compiler with context ctx and plan p.
-// On success, it returns an amended plan, now associating each subject with its compiler results.
+// On success, it returns an amended plan, now associating each subject with its compiler results, and each compiler
+// with its version if the driver can probe it.  It doesn't compile subjects that an earlier stage failed to process.
 func (c *Compiler) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 	if err := checkPlan(p); err != nil {
 		return nil, err
@@ -97,21 +98,23 @@ func (c *Compiler) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 		return nil, err
 	}
 
+	np := *p
+	np.Compilers = c.probeVersions(ctx, p.Compilers)
+
 	observer.OnCompileStart(c.quantities, c.observers...)
 
 	newc, err := builder.ParBuild(
 		ctx,
 		c.quantities.NWorkers,
-		p.Corpus,
-		c.builderConfig(p),
+		np.Corpus.Viable(),
+		c.builderConfig(&np),
 		func(ctx context.Context, s subject.Named, requests chan<- builder.Request) error {
-			return c.instance(requests, s, p).Compile(ctx)
+			return c.instance(requests, s, &np).Compile(ctx)
 		})
 	if err != nil {
 		return nil, err
 	}
 
-	np := *p
 	np.Corpus = newc
 	return &np, nil
 }
@@ -121,7 +121,8 @@ func TestCompiler_Run(t *testing.T) {
 	}), mock.Anything).Return(nil)
 	mp.On("Prepare", id.FromString("gcc")).Return(nil)
 
-	stage, serr := compiler.New(&mc, &mp, compiler.OverrideQuantities(qs))
+	ver := mdl.Version{Major: 11, Minor: 2}
+	stage, serr := compiler.New(versionedDriver{Driver: &mc, v: ver}, &mp, compiler.OverrideQuantities(qs))
 	require.NoError(t, serr, "constructing compile job")
 	p2, err := stage.Run(ctx, &p)
 	require.NoError(t, err, "running compile job")
@@ -132,4 +133,16 @@ func TestCompiler_Run(t *testing.T) {
 	for got := range p2.Corpus {
 		assert.Contains(t, names, got, "corpus got an extra subject name")
 	}
+	assert.Equal(t, &ver, p2.Compilers[id.FromString("gcc")].Version, "compiler version not probed")
+	assert.Nil(t, p.Compilers[id.FromString("gcc")].Version, "input plan modified")
+}
+
+// versionedDriver adds version probing to a mock driver.
+type versionedDriver struct {
+	*mocks2.Driver
+	v mdl.Version
+}
+
+func (d versionedDriver) ProbeVersion(context.Context, *mdl.Compiler, service.Runner) (*mdl.Version, error) {
+	return &d.v, nil
 }
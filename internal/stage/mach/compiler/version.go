@@ -0,0 +1,35 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package compiler
+
+import (
+	"context"
+
+	"github.com/c4-project/c4t/internal/helper/srvrun"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+)
+
+// probeVersions returns a copy of cs in which each compiler has its version filled in, if the driver can probe it.
+//
+// We probe on the machine node, as this is the only place we can be sure of running the same compiler binary as the
+// compiles themselves.
+func (c *Compiler) probeVersions(ctx context.Context, cs compiler.InstanceMap) compiler.InstanceMap {
+	vp, ok := c.driver.(compiler.VersionProber)
+	if !ok {
+		return cs
+	}
+	sr := srvrun.NewExecRunner()
+
+	ncs := make(compiler.InstanceMap, len(cs))
+	for cid, inst := range cs {
+		// Not knowing the version of a compiler shouldn't stop us from compiling with it, so we ignore errors here.
+		if v, err := vp.ProbeVersion(ctx, &inst.Compiler, sr); err == nil {
+			inst.Version = v
+		}
+		ncs[cid] = inst
+	}
+	return ncs
+}
@@ -80,10 +80,68 @@ func (n *Instance) runCompileInner(ctx context.Context, name compilation.Name, c
 	}
 
 	start := time.Now()
-	o, runErr := n.runAndParseBin(ctx, name, bin)
-	s, err := statusOfRun(o, runErr)
+	if n.quantities.NRepeats <= 1 {
+		o, runErr := n.runAndParseBin(ctx, name, bin)
+		s, err := statusOfRun(o, runErr)
+		return n.makeResult(start, s, o), err
+	}
+	return n.runRepeated(ctx, start, name, bin)
+}
+
+// runRepeated runs the binary at bin once per configured repeat, merging the observations of each repeat that
+// completed.
+//
+// The overall status of the result is the most severe status of any repeat, as ranked by severity.
+func (n *Instance) runRepeated(ctx context.Context, start time.Time, name compilation.Name, bin string) (compilation.RunResult, error) {
+	var (
+		merged  *obs.Obs
+		s       = status.Ok
+		repeats = make([]status.Status, n.quantities.NRepeats)
+	)
+	for i := range repeats {
+		o, runErr := n.runAndParseBin(ctx, name, bin)
+		rs, err := statusOfRun(o, runErr)
+		if err != nil {
+			return n.makeResult(start, rs, merged), err
+		}
+		repeats[i] = rs
+		if severity(s) < severity(rs) {
+			s = rs
+		}
+		merged = mergeRepeat(merged, o, runErr)
+	}
 
-	return n.makeResult(start, s, o), err
+	res := n.makeResult(start, s, merged)
+	res.Repeats = repeats
+	return res, nil
+}
+
+// severity ranks the status s of a repeat, so that the overall status of a repeated run is the highest-ranked status
+// of any of its repeats.
+//
+// Flagged and forbidden repeats are the results that analysis and saving look for, so they outrank failures and
+// timeouts, which would otherwise hide them; the remaining statuses rank in enumeration order.
+func severity(s status.Status) int {
+	switch s {
+	case status.Forbidden:
+		return int(status.Last) + 2
+	case status.Flagged:
+		return int(status.Last) + 1
+	default:
+		return int(s)
+	}
+}
+
+// mergeRepeat merges the observation o of a repeat into merged, unless the repeat failed with runErr.
+func mergeRepeat(merged, o *obs.Obs, runErr error) *obs.Obs {
+	if runErr != nil || o == nil {
+		return merged
+	}
+	if merged == nil {
+		return o
+	}
+	merged.Merge(*o)
+	return merged
 }
 
 func (n *Instance) makeResult(start time.Time, s status.Status, o *obs.Obs) compilation.RunResult {
@@ -70,6 +70,7 @@ func (*Runner) Close() error {
 }
 
 // Run runs the runner on the plan p.
+// It doesn't run subjects that an earlier stage failed to process.
 func (r *Runner) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 	if err := checkPlan(p); err != nil {
 		return nil, err
@@ -82,7 +83,7 @@ func (r *Runner) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
 	}
 
 	bcfg := r.builderConfig(p)
-	c, err := builder.ParBuild(ctx, r.quantities.NWorkers, p.Corpus, bcfg,
+	c, err := builder.ParBuild(ctx, r.quantities.NWorkers, p.Corpus.Viable(), bcfg,
 		func(ctx context.Context, named subject.Named, requests chan<- builder.Request) error {
 			return r.instance(requests, named, b).Run(ctx)
 		})
@@ -0,0 +1,158 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package runner_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/recipe"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/backend"
+	"github.com/c4-project/c4t/internal/model/service/backend/mocks"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/mach/runner"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/obs"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/c4-project/c4t/internal/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunner_Run_repeats tests that repeated runs record each repeat, and take the most severe status.
+func TestRunner_Run_repeats(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		repeats []string
+		want    status.Status
+	}{
+		"all-ok":         {repeats: []string{"ok", "ok", "ok"}, want: status.Ok},
+		"fail":           {repeats: []string{"ok", "fail", "ok"}, want: status.RunFail},
+		"flagged":        {repeats: []string{"ok", "flag", "ok"}, want: status.Flagged},
+		"flagged-first":  {repeats: []string{"flag", "fail", "ok"}, want: status.Flagged},
+		"flagged-last":   {repeats: []string{"fail", "ok", "flag"}, want: status.Flagged},
+		"flagged-always": {repeats: []string{"flag", "flag", "flag"}, want: status.Flagged},
+	}
+
+	dir := t.TempDir()
+	p := plan.Mock()
+	p.Metadata.ConfirmStage(stage.Compile, timing.Span{})
+	p.Compilers = compiler.InstanceMap{id.FromString("gcc"): compiler.MockPower9GCCOpt()}
+	p.Corpus = make(corpus.Corpus, len(cases))
+	for name, c := range cases {
+		bin := writeRepeatBin(t, filepath.Join(dir, name), c.repeats...)
+		p.Corpus[name] = *subject.NewOrPanic(
+			litmus.NewOrPanic(name+".litmus"),
+			subject.WithCompile(id.FromString("gcc"), compilation.CompileResult{
+				Result: compilation.Result{Status: status.Ok},
+				Files:  compilation.CompileFileset{Bin: bin},
+			}),
+		)
+	}
+
+	var res mocks.Resolver
+	res.Test(t)
+	res.On("Resolve", mock.Anything).Return(fakeClass{}, nil).Once()
+
+	r, err := runner.New(&res, runner.NewPathset(dir), runner.OverrideQuantities(quantity.BatchSet{
+		Timeout:  quantity.Timeout(time.Minute),
+		NWorkers: 1,
+		NRepeats: 3,
+	}))
+	require.NoError(t, err, "constructing runner")
+
+	p2, err := r.Run(context.Background(), p)
+	require.NoError(t, err, "running runner")
+
+	for name, c := range cases {
+		s := p2.Corpus[name]
+		rr, err := s.RunResult(id.FromString("gcc"))
+		if !assert.NoError(t, err, "getting run result", name) {
+			continue
+		}
+		assert.Equal(t, c.want, rr.Status, "wrong overall status", name)
+		assert.Len(t, rr.Repeats, len(c.repeats), "wrong number of repeats", name)
+		assert.Equal(t, strings.Count(strings.Join(c.repeats, " "), "flag"), rr.NRepeatsWith(status.Flagged),
+			"wrong number of flagged repeats", name)
+	}
+	res.AssertExpectations(t)
+}
+
+// writeRepeatBin writes a shell script into dir that behaves, on its n-th invocation, as described by outcomes[n].
+// 'ok' prints a satisfied observation, 'flag' prints an unsatisfied one, and 'fail' exits with an error.
+func writeRepeatBin(t *testing.T, dir string, outcomes ...string) string {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0744), "making directory for binary")
+
+	var sb strings.Builder
+	count := filepath.Join(dir, "count")
+	fmt.Fprintf(&sb, "#!/bin/sh\nn=$(cat '%s' 2>/dev/null || echo 0)\necho $((n+1)) > '%s'\ncase $n in\n", count, count)
+	for i, o := range outcomes {
+		if o == "fail" {
+			fmt.Fprintf(&sb, "%d) exit 1 ;;\n", i)
+		} else {
+			fmt.Fprintf(&sb, "%d) echo %s ;;\n", i, o)
+		}
+	}
+	sb.WriteString("esac\n")
+
+	bin := filepath.Join(dir, "bin")
+	require.NoError(t, os.WriteFile(bin, []byte(sb.String()), 0755), "writing binary")
+	return bin
+}
+
+// fakeClass is a backend class whose backend parses the output of binaries made by writeRepeatBin.
+type fakeClass struct{}
+
+func (fakeClass) Metadata() backend.Metadata {
+	return backend.Metadata{Capabilities: backend.CanProduceExe}
+}
+
+func (fakeClass) Instantiate(backend.Spec) backend.Backend {
+	return fakeBackend{}
+}
+
+func (fakeClass) Probe(context.Context, service.Runner, id.ID) ([]backend.NamedSpec, error) {
+	return nil, nil
+}
+
+type fakeBackend struct{}
+
+func (fakeBackend) Class() backend.Class {
+	return fakeClass{}
+}
+
+func (fakeBackend) Lift(context.Context, backend.LiftJob, service.Runner) (recipe.Recipe, error) {
+	return recipe.Recipe{}, nil
+}
+
+func (fakeBackend) ParseObs(_ context.Context, r io.Reader, o *obs.Obs) error {
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	o.Flags = obs.Sat
+	if strings.TrimSpace(string(out)) == "flag" {
+		o.Flags = obs.Unsat
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package oracle
+
+import (
+	"errors"
+	"io"
+
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/model/service/backend"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/subject/corpus/builder"
+)
+
+var (
+	// ErrObserverNil occurs when we try to pass a nil observer as an option.
+	ErrObserverNil = errors.New("observer nil")
+)
+
+// Option is the type of options to pass to New.
+type Option func(*Oracle) error
+
+// Options bundles up each option in os into a single option.
+func Options(os ...Option) Option {
+	return func(o *Oracle) error {
+		for _, op := range os {
+			if err := op(o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ObserveWith adds each observer in obs to the oracle's observer list.
+func ObserveWith(obs ...builder.Observer) Option {
+	return func(o *Oracle) error {
+		for _, ob := range obs {
+			if ob == nil {
+				return ErrObserverNil
+			}
+		}
+		o.obs = append(o.obs, obs...)
+		return nil
+	}
+}
+
+// SendStderrTo makes the oracle send any stderr output from its backend to w.
+func SendStderrTo(w io.Writer) Option {
+	return func(o *Oracle) error {
+		o.errw = iohelp.EnsureWriter(w)
+		return nil
+	}
+}
+
+// UseConfig populates settings for the oracle from the configuration cfg.
+func UseConfig(cfg *backend.OracleConfig) Option {
+	return func(o *Oracle) error {
+		o.config = cfg
+		return nil
+	}
+}
+
+// WithWorkerCount sets the number of subjects the oracle checks in parallel to nworkers.
+func WithWorkerCount(nworkers int) Option {
+	return func(o *Oracle) error {
+		o.quantities.NWorkers = nworkers
+		return nil
+	}
+}
+
+// OverrideQuantities overrides the oracle's quantities with qs.
+func OverrideQuantities(qs quantity.BatchSet) Option {
+	return func(o *Oracle) error {
+		o.quantities.Override(qs)
+		return nil
+	}
+}
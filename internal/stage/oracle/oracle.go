@@ -0,0 +1,228 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package oracle contains the part of the tester framework that checks run observations against a reference model.
+//
+// The oracle runs a standalone backend (by default, herd7 under the C11 model) over each subject's C litmus test,
+// records the set of states the model allows, and marks any run whose observation contains a state outside that set
+// as forbidden.
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/srvrun"
+	"github.com/c4-project/c4t/internal/model/recipe"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/backend"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/corpus/builder"
+	"github.com/c4-project/c4t/internal/subject/obs"
+
+	"github.com/1set/gut/ystring"
+)
+
+var (
+	// ErrResolverNil occurs when an oracle runs without a backend resolver set.
+	ErrResolverNil = errors.New("backend resolver nil")
+
+	// ErrRootBlank occurs when an oracle is constructed without an output directory.
+	ErrRootBlank = errors.New("oracle output directory blank")
+
+	// ErrNotStandalone occurs when the oracle's backend can't be run standalone.
+	ErrNotStandalone = errors.New("oracle backend can't run standalone")
+)
+
+// Oracle holds the main configuration for the oracle part of the tester framework.
+type Oracle struct {
+	// resolver resolves backend specifications.
+	resolver backend.Resolver
+
+	// config is the oracle configuration; it may be nil.
+	config *backend.OracleConfig
+
+	// root is the directory under which the oracle keeps its per-subject outputs.
+	root string
+
+	// quantities sets the quantities for this oracle.
+	quantities quantity.BatchSet
+
+	// obs track the oracle's progress across a corpus.
+	obs []builder.Observer
+
+	// errw is the writer to which standard error (eg from the oracle backend) should be sent.
+	errw io.Writer
+}
+
+// New constructs a new Oracle given backend resolver r, output directory root, and options os.
+func New(r backend.Resolver, root string, os ...Option) (*Oracle, error) {
+	if r == nil {
+		return nil, ErrResolverNil
+	}
+	if ystring.IsBlank(root) {
+		return nil, ErrRootBlank
+	}
+	o := Oracle{resolver: r, root: root, quantities: quantity.BatchSet{NWorkers: 20}}
+	if err := Options(os...)(&o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Stage gets the stage for this Oracle.
+func (*Oracle) Stage() stage.Stage {
+	return stage.Oracle
+}
+
+// Close does nothing.
+func (*Oracle) Close() error {
+	return nil
+}
+
+// Run checks every test subject in p against the reference model, marking any forbidden runs.
+func (o *Oracle) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
+	if err := checkPlan(p); err != nil {
+		return nil, err
+	}
+	b, err := o.backend()
+	if err != nil {
+		return nil, err
+	}
+
+	outp := *p
+	outp.Corpus, err = o.checkCorpus(ctx, b, p.Corpus)
+	return &outp, err
+}
+
+func checkPlan(p *plan.Plan) error {
+	if p == nil {
+		return plan.ErrNil
+	}
+	if err := p.Check(); err != nil {
+		return err
+	}
+	return p.Metadata.RequireStage(stage.Invoke)
+}
+
+func (o *Oracle) backend() (backend.Backend, error) {
+	b, err := backend.ResolveAndInstantiate(o.config.Spec(), o.resolver)
+	if err != nil {
+		return nil, err
+	}
+	if !b.Class().Metadata().Capabilities.Satisfies(backend.CanRunStandalone) {
+		return nil, ErrNotStandalone
+	}
+	return b, nil
+}
+
+func (o *Oracle) checkCorpus(ctx context.Context, b backend.Backend, c corpus.Corpus) (corpus.Corpus, error) {
+	cs := checkable(c)
+	if len(cs) == 0 {
+		return c, nil
+	}
+
+	cfg := builder.Config{
+		Init:      c,
+		Observers: o.obs,
+		Manifest: builder.Manifest{
+			Name:  "oracle",
+			NReqs: len(cs),
+		},
+	}
+	xr := srvrun.NewExecRunner(srvrun.StderrTo(o.errw))
+	return builder.ParBuild(ctx, o.quantities.NWorkers, cs, cfg, func(ctx context.Context, s subject.Named, rq chan<- builder.Request) error {
+		tctx, cancel := o.quantities.Timeout.OnContext(ctx)
+		defer cancel()
+
+		ob, err := o.checkSubject(tctx, b, xr, s)
+		if err != nil {
+			return fail(ctx, tctx, s.Name, err, rq)
+		}
+		// AddOracle only changes its own copy of the subject, so we can try it here to catch reference observations
+		// that we can't compare against the subject's runs; we record those as failures of this subject.
+		if err := s.AddOracle(*ob); err != nil {
+			return builder.FailureRequest(s.Name, subject.NewFailure(stage.Oracle, err, false)).SendTo(ctx, rq)
+		}
+		return builder.OracleRequest(s.Name, *ob).SendTo(ctx, rq)
+	})
+}
+
+// fail records that checking the subject named name failed with err, where tctx is the check's timeout context.
+//
+// The failure only propagates if the parent context ctx is done; otherwise, we send a failure request for the
+// subject, so that a reference model that chokes on (or takes too long over) one subject doesn't stall the rest.
+func fail(ctx, tctx context.Context, name string, err error, rq chan<- builder.Request) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	timeout := errors.Is(tctx.Err(), context.DeadlineExceeded)
+	return builder.FailureRequest(name, subject.NewFailure(stage.Oracle, err, timeout)).SendTo(ctx, rq)
+}
+
+// checkable gets the sub-corpus of c containing subjects with C litmus tests that haven't yet been checked, and that
+// no earlier stage has failed to process.
+func checkable(c corpus.Corpus) corpus.Corpus {
+	cs := make(corpus.Corpus, len(c))
+	for n, s := range c {
+		if s.Oracle != nil || s.HasFailed() {
+			continue
+		}
+		if l, err := s.BestLitmus(); err == nil && l.IsC() {
+			cs[n] = s
+		}
+	}
+	return cs
+}
+
+func (o *Oracle) checkSubject(ctx context.Context, b backend.Backend, xr service.Runner, s subject.Named) (*obs.Obs, error) {
+	l, err := s.BestLitmus()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(o.root, s.Name)
+	if err := os.MkdirAll(dir, 0744); err != nil {
+		return nil, err
+	}
+
+	j := backend.LiftJob{
+		In:  backend.LiftLitmusInput(l),
+		Out: backend.LiftOutput{Dir: dir, Target: backend.ToStandalone},
+	}
+	r, err := b.Lift(ctx, j, xr)
+	if err != nil {
+		return nil, fmt.Errorf("when running oracle on %s: %w", s.Name, err)
+	}
+	return parseRecipe(ctx, b, r)
+}
+
+func parseRecipe(ctx context.Context, b backend.ObsParser, r recipe.Recipe) (*obs.Obs, error) {
+	var ob obs.Obs
+	for _, fname := range r.Paths() {
+		if err := parseFile(ctx, b, &ob, fname); err != nil {
+			return nil, err
+		}
+	}
+	return &ob, nil
+}
+
+func parseFile(ctx context.Context, b backend.ObsParser, ob *obs.Obs, fname string) error {
+	f, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	perr := b.ParseObs(ctx, f, ob)
+	cerr := f.Close()
+	return errhelp.FirstError(perr, cerr)
+}
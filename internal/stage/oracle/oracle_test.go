@@ -0,0 +1,225 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package oracle_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/recipe"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/backend"
+	"github.com/c4-project/c4t/internal/model/service/backend/mocks"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/oracle"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/obs"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/c4-project/c4t/internal/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+)
+
+// TestNew_errors tests the error result of New in various situations.
+func TestNew_errors(t *testing.T) {
+	t.Parallel()
+
+	opterr := errors.New("oopsie")
+
+	cases := map[string]struct {
+		r    backend.Resolver
+		root string
+		os   []oracle.Option
+		err  error
+	}{
+		"ok":           {r: &mocks.Resolver{}, root: "oracle"},
+		"nil-resolver": {root: "oracle", err: oracle.ErrResolverNil},
+		"blank-root":   {r: &mocks.Resolver{}, err: oracle.ErrRootBlank},
+		"nil-observer": {
+			r:    &mocks.Resolver{},
+			root: "oracle",
+			os:   []oracle.Option{oracle.ObserveWith(nil)},
+			err:  oracle.ErrObserverNil,
+		},
+		"opt-err": {
+			r:    &mocks.Resolver{},
+			root: "oracle",
+			os:   []oracle.Option{func(*oracle.Oracle) error { return opterr }},
+			err:  opterr,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := oracle.New(c.r, c.root, c.os...)
+			testhelp.ExpectErrorIs(t, err, c.err, "constructing oracle")
+		})
+	}
+}
+
+// TestOracle_Run tests running the oracle on a small plan with a fake reference model.
+func TestOracle_Run(t *testing.T) {
+	t.Parallel()
+
+	var r mocks.Resolver
+	r.Test(t)
+	r.On("Resolve", mock.MatchedBy(func(cid id.ID) bool {
+		return cid.Equal(id.FromString("herdtools.herd"))
+	})).Return(fakeClass{}, nil).Once()
+
+	o, err := oracle.New(&r, t.TempDir())
+	require.NoError(t, err, "constructing oracle")
+
+	p := plan.Mock()
+	p.Metadata.ConfirmStage(stage.Invoke, timing.Span{})
+	p.Corpus = corpus.Corpus{
+		"c": *subject.NewOrPanic(
+			litmus.NewOrPanic("c.litmus", litmus.WithArch(id.ArchC)),
+			subject.WithRun(id.FromString("gcc"), mockRun(status.Ok, "0", "2")),
+			subject.WithRun(id.FromString("clang"), mockRun(status.Flagged, "1")),
+		),
+		"arm": *subject.NewOrPanic(
+			litmus.NewOrPanic("arm.litmus", litmus.WithArch(id.ArchArm)),
+			subject.WithRun(id.FromString("gcc"), mockRun(status.Ok, "2")),
+		),
+		"renamed": *subject.NewOrPanic(
+			litmus.NewOrPanic("renamed.litmus", litmus.WithArch(id.ArchC)),
+			subject.WithRun(id.FromString("gcc"), compilation.RunResult{
+				Result: compilation.Result{Status: status.Ok},
+				Obs:    &obs.Obs{States: []obs.State{{Values: obs.Valuation{"y": "2"}}}},
+			}),
+		),
+	}
+
+	p2, err := o.Run(context.Background(), p)
+	require.NoError(t, err, "running oracle")
+
+	for _, c := range []struct {
+		sub  string
+		cid  string
+		want status.Status
+	}{
+		{sub: "c", cid: "gcc", want: status.Forbidden},
+		{sub: "c", cid: "clang", want: status.Flagged},
+		{sub: "arm", cid: "gcc", want: status.Ok},
+	} {
+		s := p2.Corpus[c.sub]
+		rr, err := s.RunResult(id.FromString(c.cid))
+		if assert.NoError(t, err, "getting run result", c.sub, c.cid) {
+			assert.Equal(t, c.want, rr.Status, "wrong status", c.sub, c.cid)
+		}
+	}
+	assert.NotNil(t, p2.Corpus["c"].Oracle, "C subject should have oracle")
+	assert.Nil(t, p2.Corpus["arm"].Oracle, "non-C subject shouldn't have oracle")
+	assert.Nil(t, p2.Corpus["renamed"].Oracle, "subject with incomparable runs shouldn't have oracle")
+	if f := p2.Corpus["renamed"].Failure; assert.NotNil(t, f, "subject with incomparable runs should fail") {
+		assert.Equal(t, stage.Oracle, f.Stage, "failure should be in oracle stage")
+	}
+
+	assert.Equal(t, status.Ok, p.Corpus["c"].Compilations[id.FromString("gcc")].Run.Status, "input plan modified")
+	r.AssertExpectations(t)
+}
+
+// TestOracle_Run_timeout tests that the oracle marks a subject on which the reference model hangs as failed, rather
+// than stalling the whole corpus.
+func TestOracle_Run_timeout(t *testing.T) {
+	t.Parallel()
+
+	var r mocks.Resolver
+	r.Test(t)
+	r.On("Resolve", mock.Anything).Return(fakeClass{}, nil).Once()
+
+	qs := quantity.BatchSet{Timeout: quantity.Timeout(50 * time.Millisecond)}
+	o, err := oracle.New(&r, t.TempDir(), oracle.OverrideQuantities(qs))
+	require.NoError(t, err, "constructing oracle")
+
+	p := plan.Mock()
+	p.Metadata.ConfirmStage(stage.Invoke, timing.Span{})
+	p.Corpus = corpus.Corpus{
+		"fast": *subject.NewOrPanic(
+			litmus.NewOrPanic("fast.litmus", litmus.WithArch(id.ArchC)),
+			subject.WithRun(id.FromString("gcc"), mockRun(status.Ok, "0")),
+		),
+		"hang": *subject.NewOrPanic(
+			litmus.NewOrPanic("hang.litmus", litmus.WithArch(id.ArchC)),
+			subject.WithRun(id.FromString("gcc"), mockRun(status.Ok, "0")),
+		),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	p2, err := o.Run(ctx, p)
+	require.NoError(t, err, "running oracle")
+
+	assert.NotNil(t, p2.Corpus["fast"].Oracle, "fast subject should have oracle")
+	if f := p2.Corpus["hang"].Failure; assert.NotNil(t, f, "hanging subject should fail") {
+		assert.Equal(t, stage.Oracle, f.Stage, "failure should be in oracle stage")
+		assert.True(t, f.Timeout, "failure should be a timeout")
+	}
+	r.AssertExpectations(t)
+}
+
+func mockRun(s status.Status, xs ...string) compilation.RunResult {
+	var o obs.Obs
+	for _, x := range xs {
+		o.States = append(o.States, obs.State{Values: obs.Valuation{"x": x}})
+	}
+	return compilation.RunResult{Result: compilation.Result{Status: s}, Obs: &o}
+}
+
+// fakeClass is a backend class whose backend allows exactly the states x=0 and x=1.
+type fakeClass struct{}
+
+func (fakeClass) Metadata() backend.Metadata {
+	return backend.Metadata{Capabilities: backend.CanRunStandalone | backend.CanLiftLitmus}
+}
+
+func (fakeClass) Instantiate(backend.Spec) backend.Backend {
+	return fakeBackend{}
+}
+
+func (fakeClass) Probe(context.Context, service.Runner, id.ID) ([]backend.NamedSpec, error) {
+	return nil, nil
+}
+
+type fakeBackend struct{}
+
+func (fakeBackend) Class() backend.Class {
+	return fakeClass{}
+}
+
+func (fakeBackend) Lift(ctx context.Context, j backend.LiftJob, _ service.Runner) (recipe.Recipe, error) {
+	// The reference model hangs on any test called 'hang'.
+	if j.In.Litmus.Filepath() == "hang.litmus" {
+		<-ctx.Done()
+		return recipe.Recipe{}, ctx.Err()
+	}
+	if err := os.WriteFile(filepath.Join(j.Out.Dir, "out.txt"), []byte{}, 0644); err != nil {
+		return recipe.Recipe{}, err
+	}
+	return recipe.New(j.Out.Dir, recipe.OutNothing, recipe.AddFiles("out.txt"))
+}
+
+func (fakeBackend) ParseObs(_ context.Context, _ io.Reader, o *obs.Obs) error {
+	o.States = []obs.State{{Values: obs.Valuation{"x": "0"}}, {Values: obs.Valuation{"x": "1"}}}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package perturber
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+)
+
+// DefaultExploration is the default exploration constant for the bandit strategy.
+// This is the constant used in the classic UCB1 algorithm.
+var DefaultExploration = math.Sqrt2
+
+// Yield summarises the historical yield of a compiler configuration.
+type Yield struct {
+	// Cycles is the number of cycles in which the configuration was tested.
+	Cycles uint64
+	// Subjects is the number of subject runs made with the configuration.
+	Subjects uint64
+	// Hits is the number of those runs that were flagged or failed.
+	Hits uint64
+}
+
+// Rate gets the proportion of subject runs in y that were hits.
+func (y Yield) Rate() float64 {
+	if y.Subjects == 0 {
+		return 0
+	}
+	return float64(y.Hits) / float64(y.Subjects)
+}
+
+// Add gets the sum of y and y2.
+func (y Yield) Add(y2 Yield) Yield {
+	return Yield{Cycles: y.Cycles + y2.Cycles, Subjects: y.Subjects + y2.Subjects, Hits: y.Hits + y2.Hits}
+}
+
+// YieldSource is the interface of sources of historical yield information.
+type YieldSource interface {
+	// CompilerYield gets the yield of the compiler with full ID cid on the machine with ID mid.
+	// It should include the yield of every configuration that adds extra flags to cid.
+	CompilerYield(mid, cid id.ID) Yield
+
+	// SourceYield gets the yield of the descendants of the source subject with slashpath path on the machine with ID
+	// mid.
+	SourceYield(mid id.ID, path string) Yield
+}
+
+// ChoiceReason is the enumeration of reasons why the bandit strategy chose a compiler configuration.
+type ChoiceReason uint8
+
+const (
+	// ReasonUntried means that the configuration had never been tested before.
+	ReasonUntried ChoiceReason = iota
+	// ReasonExploit means that the configuration has the best yield so far.
+	ReasonExploit
+	// ReasonExplore means that the configuration doesn't have the best yield so far, but has been tested rarely
+	// enough that it is worth trying again.
+	ReasonExplore
+)
+
+//go:generate stringer -type=ChoiceReason -trimprefix=Reason
+
+// Choice describes a choice of compiler configuration made by the bandit strategy.
+type Choice struct {
+	// Compiler is the full ID of the chosen configuration, not counting any extra flags.
+	Compiler id.ID
+	// Reason is the reason for the choice.
+	Reason ChoiceReason
+	// Weight is the weight the bandit gave the configuration; for untried configurations, this is infinite.
+	Weight float64
+	// Yield is the historical yield of the configuration.
+	Yield Yield
+}
+
+// bandit chooses compiler configurations using the UCB1 algorithm, with historical yield as the reward.
+type bandit struct {
+	// source supplies historical yields; if nil, every configuration is untried.
+	source YieldSource
+	// machine is the ID of the machine whose yields we are using.
+	machine id.ID
+	// exploration is the exploration constant.
+	exploration float64
+	// rng is used to break ties.
+	rng *rand.Rand
+}
+
+// arm is a single configuration considered by the bandit.
+type arm struct {
+	row    []int
+	choice Choice
+}
+
+// pick picks n rows from sp for the compiler named name, returning them alongside the choices made.
+//
+// The bandit only chooses between distinct combinations of the base axes; it picks any extra flags randomly.
+func (b *bandit) pick(name id.ID, sp space, n int) ([][]int, []Choice, error) {
+	arms, err := b.arms(name, sp)
+	if err != nil {
+		return nil, nil, err
+	}
+	b.weigh(arms)
+
+	// Shuffling first means that the stable sort breaks ties randomly.
+	b.rng.Shuffle(len(arms), func(i, j int) { arms[i], arms[j] = arms[j], arms[i] })
+	sort.SliceStable(arms, func(i, j int) bool { return arms[i].choice.Weight > arms[j].choice.Weight })
+
+	if len(arms) < n {
+		n = len(arms)
+	}
+	rows := make([][]int, n)
+	choices := make([]Choice, n)
+	for i, a := range arms[:n] {
+		rows[i], choices[i] = append(a.row, sp.randomFlagRow(b.rng)...), a.choice
+	}
+	return rows, choices, nil
+}
+
+// arms gets every combination of the base axes of sp, with its full ID and yield.
+func (b *bandit) arms(name id.ID, sp space) ([]arm, error) {
+	sizes := sp.baseSizes()
+	var arms []arm
+	row := make([]int, len(sizes))
+	for {
+		cid, err := sp.fullID(name, row)
+		if err != nil {
+			return nil, err
+		}
+		a := arm{row: append([]int(nil), row...), choice: Choice{Compiler: cid}}
+		if b.source != nil {
+			a.choice.Yield = b.source.CompilerYield(b.machine, cid)
+		}
+		arms = append(arms, a)
+		if !incRow(row, sizes) {
+			return arms, nil
+		}
+	}
+}
+
+// weigh sets the UCB1 weight and reason of each arm in arms.
+func (b *bandit) weigh(arms []arm) {
+	var total uint64
+	best := -1.0
+	for _, a := range arms {
+		total += a.choice.Yield.Cycles
+		if a.choice.Yield.Cycles != 0 && best < a.choice.Yield.Rate() {
+			best = a.choice.Yield.Rate()
+		}
+	}
+
+	for i := range arms {
+		c := &arms[i].choice
+		if c.Yield.Cycles == 0 {
+			c.Reason, c.Weight = ReasonUntried, math.Inf(1)
+			continue
+		}
+		rate := c.Yield.Rate()
+		c.Weight = rate + b.exploration*math.Sqrt(math.Log(float64(total))/float64(c.Yield.Cycles))
+		c.Reason = ReasonExplore
+		if best <= rate {
+			c.Reason = ReasonExploit
+		}
+	}
+}
+
+// fullID gets the full ID that the compiler named name would have if configured with row.
+func (s space) fullID(name id.ID, row []int) (id.ID, error) {
+	opt, mopt := s.choice(row)
+	inst := compiler.Instance{SelectedOpt: opt, SelectedMOpt: mopt, SelectedFlags: s.flagChoice(row)}
+	return compiler.Named{ID: name, Instance: inst}.FullID()
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package perturber_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/c4-project/c4t/internal/subject/corpus/builder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ExampleYield_Rate is a runnable example for Yield.Rate.
+func ExampleYield_Rate() {
+	fmt.Println(perturber.Yield{Cycles: 2, Subjects: 40, Hits: 10}.Rate())
+	fmt.Println(perturber.Yield{}.Rate())
+
+	// Output:
+	// 0.25
+	// 0
+}
+
+// TestPerturber_Run_bandit tests the bandit strategy against a few sets of historical yields.
+func TestPerturber_Run_bandit(t *testing.T) {
+	t.Parallel()
+
+	// Every configuration of the mock compiler, with a uniform, mediocre yield.
+	base := fakeYields{}
+	for _, o := range []string{"o", "o1", "o2"} {
+		for _, m := range []string{"mmarch=a", "mmarch=b"} {
+			base["gcc."+o+"."+m] = perturber.Yield{Cycles: 10, Subjects: 100, Hits: 5}
+		}
+	}
+
+	cases := map[string]struct {
+		yields fakeYields
+		want   string
+		reason perturber.ChoiceReason
+	}{
+		"exploit": {
+			yields: base.with("gcc.o2.mmarch=b", perturber.Yield{Cycles: 10, Subjects: 100, Hits: 50}),
+			want:   "gcc.o2.mmarch=b",
+			reason: perturber.ReasonExploit,
+		},
+		"explore": {
+			// A configuration tried only once, with a slightly worse yield, should still be worth another look.
+			yields: base.with("gcc.o1.mmarch=a", perturber.Yield{Cycles: 1, Subjects: 10, Hits: 0}),
+			want:   "gcc.o1.mmarch=a",
+			reason: perturber.ReasonExplore,
+		},
+		"untried": {
+			yields: base.without("gcc.o.mmarch=b"),
+			want:   "gcc.o.mmarch=b",
+			reason: perturber.ReasonUntried,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var obs choiceObserver
+			pt, err := perturber.New(
+				mockInspector(t),
+				perturber.UseStrategy(compiler.StrategyBandit),
+				perturber.UseYields(c.yields),
+				perturber.ObserveWith(&obs),
+			)
+			require.NoError(t, err, "constructing perturber")
+
+			pm := plan.Mock()
+			pm.Compilers = compiler.InstanceMap{id.FromString("gcc"): {Compiler: compiler.Compiler{Style: id.CStyleGCC}}}
+			np, err := pt.Run(context.Background(), pm)
+			require.NoError(t, err, "perturbing")
+
+			want := id.FromString(c.want)
+			assert.Contains(t, np.Compilers, want, "wrong compiler chosen")
+			require.Len(t, obs.choices, 1, "should have observed one choice")
+			got := obs.choices[0]
+			assert.Equal(t, want, got.Compiler, "wrong compiler in choice")
+			assert.Equal(t, c.reason, got.Reason, "wrong reason in choice")
+			assert.Equal(t, c.yields[c.want], got.Yield, "wrong yield in choice")
+			if c.reason == perturber.ReasonUntried {
+				assert.True(t, math.IsInf(got.Weight, 1), "untried weight should be infinite")
+			}
+		})
+	}
+}
+
+// fakeYields is a yield source that ignores the machine ID, and keys both compilers and source paths by string.
+type fakeYields map[string]perturber.Yield
+
+func (f fakeYields) CompilerYield(_, cid id.ID) perturber.Yield {
+	return f[cid.String()]
+}
+
+func (f fakeYields) SourceYield(_ id.ID, path string) perturber.Yield {
+	return f[path]
+}
+
+func (f fakeYields) with(cid string, y perturber.Yield) fakeYields {
+	g := make(fakeYields, len(f))
+	for k, v := range f {
+		g[k] = v
+	}
+	g[cid] = y
+	return g
+}
+
+func (f fakeYields) without(cid string) fakeYields {
+	g := f.with(cid, perturber.Yield{})
+	delete(g, cid)
+	return g
+}
+
+// choiceObserver records the choices announced by the perturber.
+type choiceObserver struct {
+	choices []perturber.Choice
+}
+
+func (o *choiceObserver) OnPerturb(m perturber.Message) {
+	if m.Kind == perturber.KindChoseCompiler {
+		o.choices = append(o.choices, *m.Choice)
+	}
+}
+
+func (*choiceObserver) OnBuild(builder.Message) {}
+
+func (*choiceObserver) OnCompilerConfig(compiler.Message) {}
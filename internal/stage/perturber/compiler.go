@@ -7,6 +7,8 @@ package perturber
 
 import (
 	"math/rand"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/c4-project/c4t/internal/model/service"
@@ -36,15 +38,52 @@ type compilerPerturber struct {
 	useFullIDs bool
 	// mutant is the mutant ID to insert into compilers.
 	mutant mutation.Mutant
+	// strategy is the strategy to use when choosing compiler instances.
+	strategy compiler.Strategy
+	// limit is the maximum number of instances to make from each compiler, if nonzero.
+	limit int
+	// coverage tracks the coverage of each compiler's configuration space across cycles.
+	coverage *CoverageMap
+	// bandit chooses configurations for the bandit strategy.
+	bandit *bandit
+	// announce sends perturber messages to the perturber's observers.
+	announce func(Message)
+}
+
+// compilerInstances gets the maximum number of instances the perturber makes from each compiler on each cycle under
+// its strategy, or zero if there is no maximum.
+func (p *Perturber) compilerInstances() int {
+	switch p.strategy {
+	case compiler.StrategyRandom:
+		return 1
+	case compiler.StrategyMatrix:
+		return p.quantities.MatrixCompilerInstances()
+	case compiler.StrategyBandit:
+		if p.quantities.CompilerInstances <= 0 {
+			return 1
+		}
+	}
+	return p.quantities.CompilerInstances
 }
 
 func (p *Perturber) perturbCompilers(rng *rand.Rand, pn *plan.Plan) error {
 	c := compilerPerturber{
-		inspector:  p.ci,
-		observers:  lowerToCompiler(p.observers),
-		rng:        rng,
-		useFullIDs: p.useFullIDs,
+		inspector: p.ci,
+		observers: lowerToCompiler(p.observers),
+		rng:       rng,
+		// Strategies other than the random one can make several instances per compiler, which need distinct IDs.
+		useFullIDs: p.useFullIDs || p.strategy != compiler.StrategyRandom,
 		mutant:     pn.Mutant(),
+		strategy:   p.strategy,
+		limit:      p.compilerInstances(),
+		coverage:   p.coverage,
+		bandit: &bandit{
+			source:      p.yields,
+			machine:     pn.Machine.ID,
+			exploration: p.exploration,
+			rng:         rng,
+		},
+		announce: p.announce,
 	}
 	var err error
 	pn.Compilers, err = c.Perturb(pn.Compilers)
@@ -53,22 +92,25 @@ func (p *Perturber) perturbCompilers(rng *rand.Rand, pn *plan.Plan) error {
 
 // Perturb perturbs the compiler set for a plan.
 func (c *compilerPerturber) Perturb(cfgs compiler.InstanceMap) (compiler.InstanceMap, error) {
-	compiler.OnCompilerConfigStart(len(cfgs), c.observers...)
-
-	ncfgs := make(compiler.InstanceMap, len(cfgs))
-	i := 0
+	var ncs []compiler.Named
 	for n, cfg := range cfgs {
 		nc, err := c.perturbCompiler(n, cfg.Compiler)
 		if err != nil {
 			return nil, err
 		}
-		nid, err := c.fullCompilerName(nc)
+		ncs = append(ncs, nc...)
+	}
+
+	compiler.OnCompilerConfigStart(len(ncs), c.observers...)
+
+	ncfgs := make(compiler.InstanceMap, len(ncs))
+	for i, nc := range ncs {
+		nid, err := c.fullCompilerName(&nc)
 		if err != nil {
 			return nil, err
 		}
 		ncfgs[nid] = nc.Instance
-		compiler.OnCompilerConfigStep(i, *nc, c.observers...)
-		i++
+		compiler.OnCompilerConfigStep(i, nc, c.observers...)
 	}
 
 	compiler.OnCompilerConfigEnd(c.observers...)
@@ -88,30 +130,53 @@ func (c *compilerPerturber) fullCompilerName(nc *compiler.Named) (id.ID, error)
 	return fid, nil
 }
 
-func (c *compilerPerturber) perturbCompiler(name id.ID, cmp compiler.Compiler) (*compiler.Named, error) {
-	inst, err := c.makeCompilerInstance(cmp)
+func (c *compilerPerturber) perturbCompiler(name id.ID, cmp compiler.Compiler) ([]compiler.Named, error) {
+	sp, err := c.space(cmp)
 	if err != nil {
 		return nil, err
 	}
-	return inst.AddName(name), nil
+	rows, err := c.pickRows(name, sp)
+	if err != nil {
+		return nil, err
+	}
+
+	ncs := make([]compiler.Named, len(rows))
+	for i, row := range rows {
+		inst, err := c.makeCompilerInstance(cmp, sp, row)
+		if err != nil {
+			return nil, err
+		}
+		ncs[i] = *inst.AddName(name)
+	}
+	return ncs, nil
 }
 
-func (c *compilerPerturber) makeCompilerInstance(cmp compiler.Compiler) (compiler.Instance, error) {
-	opt, err := c.perturbCompilerOpt(cmp)
-	if err != nil {
-		return compiler.Instance{}, err
+func (c *compilerPerturber) pickRows(name id.ID, sp space) ([][]int, error) {
+	if c.strategy != compiler.StrategyBandit {
+		return c.coverage.pick(name, sp, c.strategy, c.limit, c.rng), nil
 	}
-	mopt, err := c.perturbCompilerMOpt(cmp)
+
+	rows, choices, err := c.bandit.pick(name, sp, c.limit)
 	if err != nil {
-		return compiler.Instance{}, err
+		return nil, err
 	}
+	for i := range choices {
+		c.announce(Message{Kind: KindChoseCompiler, Choice: &choices[i]})
+	}
+	return rows, nil
+}
+
+func (c *compilerPerturber) makeCompilerInstance(cmp compiler.Compiler, sp space, row []int) (compiler.Instance, error) {
+	opt, mopt := sp.choice(row)
 	inst := compiler.Instance{
-		ConfigTime:   time.Now(),
-		Mutant:       c.mutant,
-		SelectedOpt:  opt,
-		SelectedMOpt: mopt,
-		Compiler:     cmp,
+		ConfigTime:    time.Now(),
+		Mutant:        c.mutant,
+		SelectedOpt:   opt,
+		SelectedMOpt:  mopt,
+		SelectedFlags: sp.flagChoice(row),
+		Compiler:      cmp,
 	}
+	var err error
 	inst.Run, err = c.expandRun(inst.Run, inst.Interpolations())
 	return inst, err
 }
@@ -128,52 +193,154 @@ func (c *compilerPerturber) expandRun(r *service.RunInfo, interps map[string]str
 	return &newr, nil
 }
 
-func (c *compilerPerturber) perturbCompilerOpt(cfg compiler.Compiler) (*optlevel.Named, error) {
+func (c *compilerPerturber) space(cfg compiler.Compiler) (space, error) {
+	if err := cfg.Flags.Check(); err != nil {
+		return space{}, err
+	}
 	opts, err := compiler.SelectLevels(c.inspector, &cfg)
 	if err != nil {
-		return nil, err
+		return space{}, err
 	}
 	names, err := stringhelp.MapKeys(opts)
 	if err != nil {
-		return nil, err
+		return space{}, err
 	}
-	return c.chooseOpt(opts, names), nil
-}
-
-func (c *compilerPerturber) perturbCompilerMOpt(cfg compiler.Compiler) (string, error) {
 	mopts, err := compiler.SelectMOpts(c.inspector, &cfg)
 	if err != nil {
-		return "", err
+		return space{}, err
+	}
+	// Map iteration order is random, so we sort the choices to keep both coverage and seeded choices stable.
+	sort.Strings(names)
+	ms := mopts.Slice()
+	sort.Strings(ms)
+	sp := space{opts: opts, optNames: names, mopts: ms, optional: cfg.Flags.OptionalNames()}
+	if cfg.Flags != nil {
+		sp.pool, sp.groups = cfg.Flags, cfg.Flags.Groups
 	}
-	return c.chooseMOpt(mopts), nil
+	return sp, nil
 }
 
-func (c *compilerPerturber) chooseOpt(opts map[string]optlevel.Level, names []string) *optlevel.Named {
-	// Don't bother trying to select an optimisation if there aren't any
-	if len(opts) == 0 {
-		return nil
-	}
+// space is the configuration space of a compiler: the choices the perturber can make on each of its axes.
+//
+// Each combination of choices is a row, holding the index of the choice on each axis.
+// The axes are, in order, the optimisation level, the machine profile, each optional flag, and each flag group.
+// The first two axes are the base axes; the rest are the flag axes.
+type space struct {
+	// opts contains the permitted optimisation levels.
+	opts map[string]optlevel.Level
+	// optNames contains the names of the levels in opts, in order.
+	// Choice 0 on the optimisation level axis is 'no optimisation level'; choice i is optNames[i-1].
+	optNames []string
+	// mopts contains the permitted machine profiles, in order.
+	mopts []string
+	// pool is the flag pool, if any.
+	pool *compiler.FlagPool
+	// optional contains the optional flags in pool, in order.
+	// Choice 0 on each optional flag axis is 'leave out the flag', and choice 1 is 'pass the flag'.
+	optional []string
+	// groups contains the flag groups in pool.
+	// Choice 0 on each group axis is 'pass no flag from the group'; choice i is the group's flag i-1.
+	groups []compiler.FlagGroup
+}
 
-	// The idea here is that we're giving 'don't choose an optimisation' - index -1 - an equal chance.
-	i := c.rng.Intn(len(opts)+1) - 1
-	if i < 0 {
-		return nil
+// numBaseAxes is the number of base axes in a space.
+const numBaseAxes = 2
+
+// sizes gets the number of choices on each axis of the space.
+func (s space) sizes() []int {
+	nmopts := len(s.mopts)
+	if nmopts == 0 {
+		// We still need one choice, the empty machine profile.
+		nmopts = 1
+	}
+	sizes := []int{len(s.optNames) + 1, nmopts}
+	for range s.optional {
+		sizes = append(sizes, 2)
+	}
+	for _, g := range s.groups {
+		sizes = append(sizes, len(g.Flags)+1)
 	}
+	return sizes
+}
 
-	name := names[i]
-	return &optlevel.Named{Name: name, Level: opts[name]}
+// baseSizes gets the number of choices on each base axis of the space.
+func (s space) baseSizes() []int {
+	return s.sizes()[:numBaseAxes]
+}
 
+// signature gets a string that changes whenever the choices available in the space change.
+func (s space) signature() string {
+	parts := []string{strings.Join(s.optNames, ","), strings.Join(s.mopts, ","), strings.Join(s.optional, ",")}
+	for _, g := range s.groups {
+		parts = append(parts, strings.Join(g.Flags, ","))
+	}
+	return strings.Join(parts, "|")
 }
 
-func (c *compilerPerturber) chooseMOpt(opts stringhelp.Set) string {
-	// Don't bother trying to select an mopt if there aren't any
-	// TODO(@MattWindsor91): should this be an error?
-	nopts := len(opts)
-	if nopts == 0 {
-		return ""
+// choice gets the optimisation level and machine profile chosen by row.
+func (s space) choice(row []int) (*optlevel.Named, string) {
+	var (
+		opt  *optlevel.Named
+		mopt string
+	)
+	if 0 < row[0] {
+		name := s.optNames[row[0]-1]
+		opt = &optlevel.Named{Name: name, Level: s.opts[name]}
+	}
+	if len(s.mopts) != 0 {
+		mopt = s.mopts[row[1]]
 	}
+	return opt, mopt
+}
+
+// flagChoice gets the extra flags chosen by row; if row only covers the base axes, there are none.
+func (s space) flagChoice(row []int) []string {
+	if len(row) <= numBaseAxes {
+		return nil
+	}
+	var flags []string
+	frow := row[numBaseAxes:]
+	for i, f := range s.optional {
+		if frow[i] == 1 {
+			flags = append(flags, f)
+		}
+	}
+	for i, g := range s.groups {
+		if c := frow[len(s.optional)+i]; 0 < c {
+			flags = append(flags, g.Flags[c-1])
+		}
+	}
+	return flags
+}
+
+// randomRow picks a random row from the space.
+func (s space) randomRow(rng *rand.Rand) []int {
+	// The idea here is that we're giving 'don't choose an optimisation' - index 0 - an equal chance.
+	row := []int{rng.Intn(len(s.optNames) + 1), 0}
 	// 'don't choose an mopt' - the empty string, may or may not be a valid choice, so we don't factor it in here.
-	optsl := opts.Slice()
-	i := c.rng.Intn(nopts)
-	return optsl[i]
+	// TODO(@MattWindsor91): should having no mopts be an error?
+	if nmopts := len(s.mopts); nmopts != 0 {
+		row[1] = rng.Intn(nmopts)
+	}
+	return append(row, s.randomFlagRow(rng)...)
+}
+
+// randomFlagRow picks random choices for the flag axes of the space, using the probabilities in its flag pool.
+func (s space) randomFlagRow(rng *rand.Rand) []int {
+	var row []int
+	for _, f := range s.optional {
+		c := 0
+		if rng.Float64() < s.pool.OptionalProbability(f) {
+			c = 1
+		}
+		row = append(row, c)
+	}
+	for _, g := range s.groups {
+		c := 0
+		if len(g.Flags) != 0 && rng.Float64() < g.EffectiveProbability() {
+			c = 1 + rng.Intn(len(g.Flags))
+		}
+		row = append(row, c)
+	}
+	return row
 }
@@ -8,6 +8,9 @@ package perturber
 import (
 	"math/rand"
 
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/subject"
+
 	"github.com/c4-project/c4t/internal/subject/corpus"
 	"github.com/c4-project/c4t/internal/subject/corpus/builder"
 
@@ -15,7 +18,7 @@ import (
 )
 
 func (p *Perturber) sampleCorpus(rng *rand.Rand, pn *plan.Plan) error {
-	nc, err := pn.Corpus.Sample(rng, p.quantities.CorpusSize)
+	nc, err := pn.Corpus.WeightedSample(rng, p.quantities.CorpusSize, p.strata, p.sourceWeigher(pn.Machine.ID))
 	if err != nil {
 		return err
 	}
@@ -26,6 +29,19 @@ func (p *Perturber) sampleCorpus(rng *rand.Rand, pn *plan.Plan) error {
 	return nil
 }
 
+// sourceWeigher gets a weigher that weights each subject by the smoothed historical yield of its source on the machine
+// with ID mid, or nil if we aren't weighting by source.
+func (p *Perturber) sourceWeigher(mid id.ID) corpus.Weigher {
+	if !p.weightBySource || p.yields == nil {
+		return nil
+	}
+	return func(s subject.Subject) float64 {
+		y := p.yields.SourceYield(mid, s.Source.Path)
+		// Laplace smoothing keeps untested and so-far unproductive sources in play.
+		return float64(y.Hits+1) / float64(y.Subjects+2)
+	}
+}
+
 func (p *Perturber) announceCorpus(c corpus.Corpus) {
 	// TODO(@MattWindsor91): the fact that we're reusing the builder observations here is sus.
 	obs := lowerToBuilder(p.observers)
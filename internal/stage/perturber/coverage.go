@@ -0,0 +1,322 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package perturber
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+)
+
+// pairwiseCandidates is the number of candidate rows the pairwise strategy considers before picking one.
+const pairwiseCandidates = 20
+
+// Coverage summarises how much of a compiler's configuration space the perturber has covered in its current round.
+//
+// For the pairwise strategy, the elements counted are pairs of choices; otherwise, they are whole combinations.
+type Coverage struct {
+	// Covered is the number of elements covered so far in this round.
+	Covered int
+	// Total is the number of elements in the compiler's configuration space.
+	Total int
+	// Rounds is the number of times the perturber has covered the whole space.
+	Rounds int
+}
+
+// Coverage gets a summary of the coverage the perturber has achieved so far for each compiler.
+func (p *Perturber) Coverage() map[id.ID]Coverage {
+	return p.coverage.summary()
+}
+
+// CoverageMap tracks coverage across cycles for each compiler, by original compiler ID.
+//
+// The zero CoverageMap is empty and ready to use.  A CoverageMap is safe for concurrent use, and so perturbers can
+// share one (see ShareCoverage) to work through each compiler's configuration space together.
+type CoverageMap struct {
+	mu sync.Mutex
+	cs map[id.ID]*compilerCoverage
+}
+
+func (m *CoverageMap) summary() map[id.ID]Coverage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := make(map[id.ID]Coverage, len(m.cs))
+	for cid, c := range m.cs {
+		s[cid] = c.summary()
+	}
+	return s
+}
+
+// pick picks the rows for compiler cid with configuration space sp, using strategy st and limit.
+// limit is ignored for the random strategy.
+func (m *CoverageMap) pick(cid id.ID, sp space, st compiler.Strategy, limit int, rng *rand.Rand) [][]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cs == nil {
+		m.cs = make(map[id.ID]*compilerCoverage)
+	}
+	sizes := sp.sizes()
+	// Pairwise coverage needs at least two axes; with fewer, it is the same thing as the matrix.
+	if st == compiler.StrategyPairwise && len(sizes) < 2 {
+		st = compiler.StrategyMatrix
+	}
+	// If the compiler's configuration has changed since we last saw it, the old coverage is meaningless.
+	sig := sp.signature()
+	c, ok := m.cs[cid]
+	if !ok || c.sig != sig || c.strategy != st {
+		c = newCompilerCoverage(sig, st, sizes)
+		m.cs[cid] = c
+	}
+
+	switch st {
+	case compiler.StrategyMatrix:
+		return c.pick(limit, c.nextMatrix)
+	case compiler.StrategyPairwise:
+		return c.pick(limit, func() []int { return c.nextPairwise(rng) })
+	default:
+		return c.pick(1, func() []int { return sp.randomRow(rng) })
+	}
+}
+
+// pair is a pair of choices i and j on distinct axes a < b.
+type pair struct {
+	a, i, b, j int
+}
+
+// compilerCoverage tracks coverage for a single compiler.
+type compilerCoverage struct {
+	// sig is the signature of the configuration space being covered.
+	sig string
+	// strategy is the strategy in use.
+	strategy compiler.Strategy
+	// sizes contains the number of choices on each axis.
+	sizes []int
+	// rows contains the keys of each combination covered this round.
+	rows map[string]struct{}
+	// pairs contains each pair covered this round.
+	pairs map[pair]struct{}
+	// rounds counts the completed rounds.
+	rounds int
+}
+
+func newCompilerCoverage(sig string, st compiler.Strategy, sizes []int) *compilerCoverage {
+	c := compilerCoverage{sig: sig, strategy: st, sizes: sizes}
+	c.clear()
+	return &c
+}
+
+func (c *compilerCoverage) clear() {
+	c.rows = map[string]struct{}{}
+	c.pairs = map[pair]struct{}{}
+}
+
+// pick picks up to limit rows (or, if limit is zero, every outstanding row) using next.
+//
+// Whenever the space is fully covered, pick starts a new round; the rows already picked this cycle count towards it.
+func (c *compilerCoverage) pick(limit int, next func() []int) [][]int {
+	var rows [][]int
+	for limit == 0 || len(rows) < limit {
+		if c.full() {
+			if limit == 0 && len(rows) != 0 {
+				break
+			}
+			c.newRound(rows)
+			if c.full() {
+				break
+			}
+		}
+		r := next()
+		c.mark(r)
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+func (c *compilerCoverage) newRound(rows [][]int) {
+	c.rounds++
+	c.clear()
+	for _, r := range rows {
+		c.mark(r)
+	}
+}
+
+func (c *compilerCoverage) mark(row []int) {
+	c.rows[rowKey(row)] = struct{}{}
+	for _, p := range rowPairs(row) {
+		c.pairs[p] = struct{}{}
+	}
+}
+
+func (c *compilerCoverage) full() bool {
+	cv := c.summary()
+	return cv.Total <= cv.Covered
+}
+
+func (c *compilerCoverage) summary() Coverage {
+	if c.strategy == compiler.StrategyPairwise {
+		return Coverage{Covered: len(c.pairs), Total: numPairs(c.sizes), Rounds: c.rounds}
+	}
+	return Coverage{Covered: len(c.rows), Total: numRows(c.sizes), Rounds: c.rounds}
+}
+
+// nextMatrix gets the first uncovered row in lexicographic order.
+func (c *compilerCoverage) nextMatrix() []int {
+	row := make([]int, len(c.sizes))
+	for {
+		if _, ok := c.rows[rowKey(row)]; !ok {
+			return row
+		}
+		if !incRow(row, c.sizes) {
+			// Shouldn't happen, as we only call this when the space isn't full.
+			return row
+		}
+	}
+}
+
+// nextPairwise greedily builds a row covering as many uncovered pairs as possible.
+//
+// This follows the general approach of AETG: each candidate starts from a random uncovered pair, and then fills in
+// the remaining axes in a random order, each time picking the choice that covers the most new pairs.
+func (c *compilerCoverage) nextPairwise(rng *rand.Rand) []int {
+	todo := c.uncoveredPairs()
+	var (
+		best  []int
+		bestn = -1
+	)
+	for k := 0; k < pairwiseCandidates && len(todo) != 0; k++ {
+		row := c.pairwiseCandidate(todo[rng.Intn(len(todo))], rng)
+		if n := c.newPairs(row); bestn < n {
+			best, bestn = row, n
+		}
+	}
+	return best
+}
+
+func (c *compilerCoverage) pairwiseCandidate(seed pair, rng *rand.Rand) []int {
+	row := make([]int, len(c.sizes))
+	fixed := make([]bool, len(c.sizes))
+	row[seed.a], row[seed.b] = seed.i, seed.j
+	fixed[seed.a], fixed[seed.b] = true, true
+
+	for _, a := range rng.Perm(len(c.sizes)) {
+		if fixed[a] {
+			continue
+		}
+		besti, bestn := 0, -1
+		for i := 0; i < c.sizes[a]; i++ {
+			row[a] = i
+			if n := c.newFixedPairs(row, fixed, a); bestn < n {
+				besti, bestn = i, n
+			}
+		}
+		row[a] = besti
+		fixed[a] = true
+	}
+	return row
+}
+
+// newFixedPairs counts the uncovered pairs between axis a of row and each other fixed axis.
+func (c *compilerCoverage) newFixedPairs(row []int, fixed []bool, a int) int {
+	n := 0
+	for b := range row {
+		if b == a || !fixed[b] {
+			continue
+		}
+		if _, ok := c.pairs[makePair(a, row[a], b, row[b])]; !ok {
+			n++
+		}
+	}
+	return n
+}
+
+// newPairs counts the uncovered pairs in row.
+func (c *compilerCoverage) newPairs(row []int) int {
+	n := 0
+	for _, p := range rowPairs(row) {
+		if _, ok := c.pairs[p]; !ok {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *compilerCoverage) uncoveredPairs() []pair {
+	var ps []pair
+	for a := range c.sizes {
+		for b := a + 1; b < len(c.sizes); b++ {
+			for i := 0; i < c.sizes[a]; i++ {
+				for j := 0; j < c.sizes[b]; j++ {
+					p := pair{a: a, i: i, b: b, j: j}
+					if _, ok := c.pairs[p]; !ok {
+						ps = append(ps, p)
+					}
+				}
+			}
+		}
+	}
+	return ps
+}
+
+func makePair(a, i, b, j int) pair {
+	if b < a {
+		return pair{a: b, i: j, b: a, j: i}
+	}
+	return pair{a: a, i: i, b: b, j: j}
+}
+
+func rowPairs(row []int) []pair {
+	var ps []pair
+	for a := range row {
+		for b := a + 1; b < len(row); b++ {
+			ps = append(ps, pair{a: a, i: row[a], b: b, j: row[b]})
+		}
+	}
+	return ps
+}
+
+func rowKey(row []int) string {
+	ss := make([]string, len(row))
+	for i, x := range row {
+		ss[i] = strconv.Itoa(x)
+	}
+	return strings.Join(ss, ",")
+}
+
+// incRow advances row to the next row in lexicographic order, returning false if it was the last row.
+func incRow(row, sizes []int) bool {
+	for a := len(row) - 1; 0 <= a; a-- {
+		row[a]++
+		if row[a] < sizes[a] {
+			return true
+		}
+		row[a] = 0
+	}
+	return false
+}
+
+func numRows(sizes []int) int {
+	n := 1
+	for _, s := range sizes {
+		n *= s
+	}
+	return n
+}
+
+func numPairs(sizes []int) int {
+	n := 0
+	for a := range sizes {
+		for b := a + 1; b < len(sizes); b++ {
+			n += sizes[a] * sizes[b]
+		}
+	}
+	return n
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package perturber_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/stringhelp"
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/model/service/compiler/mocks"
+	"github.com/c4-project/c4t/internal/model/service/compiler/optlevel"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/c4-project/c4t/internal/subject/corpus/builder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPerturber_Run_strategies tests that the non-random strategies cover the configuration space across cycles, and
+// that each strategy announces the instance limit it uses.
+func TestPerturber_Run_strategies(t *testing.T) {
+	t.Parallel()
+
+	// Three optimisation choices (none, 1, 2) and two machine profiles make six combinations and twelve pairs.
+	cases := map[string]struct {
+		strategy compiler.Strategy
+		limit    int
+		// want contains the number of instances wanted on each cycle.
+		want []int
+		// total is the number of elements in the space.
+		total int
+		// announced is the instance limit that the perturber should announce on starting.
+		announced int
+	}{
+		"random":             {strategy: compiler.StrategyRandom, limit: 4, want: []int{1, 1}, total: 6, announced: 1},
+		"matrix":             {strategy: compiler.StrategyMatrix, limit: 4, want: []int{4, 4, 4}, total: 6, announced: 4},
+		"matrix-unlimited":   {strategy: compiler.StrategyMatrix, want: []int{6, 6}, total: 6, announced: 32},
+		"pairwise":           {strategy: compiler.StrategyPairwise, limit: 4, want: []int{4, 4}, total: 6, announced: 4},
+		"pairwise-unlimited": {strategy: compiler.StrategyPairwise, want: []int{6, 6}, total: 6, announced: 0},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var obs startObserver
+			pt, err := perturber.New(
+				mockInspector(t),
+				perturber.UseStrategy(c.strategy),
+				perturber.OverrideQuantities(quantity.PerturbSet{CompilerInstances: c.limit}),
+				perturber.UseSeed(8675309),
+				perturber.ObserveWith(&obs),
+			)
+			require.NoError(t, err, "constructing perturber")
+
+			gcc := id.FromString("gcc")
+			seen := map[id.ID]bool{}
+			for i, want := range c.want {
+				pm := plan.Mock()
+				pm.Compilers = compiler.InstanceMap{gcc: {Compiler: compiler.Compiler{Style: id.CStyleGCC}}}
+
+				np, err := pt.Run(context.Background(), pm)
+				require.NoError(t, err, "perturbing cycle", i)
+				assert.Len(t, np.Compilers, want, "wrong number of instances on cycle", i)
+				for cid := range np.Compilers {
+					seen[cid] = true
+				}
+			}
+
+			cv := pt.Coverage()[gcc]
+			assert.Equal(t, c.total, cv.Total, "wrong coverage total")
+			if c.strategy != compiler.StrategyRandom {
+				assert.Len(t, seen, 6, "every combination should have been seen")
+				assert.Less(t, 0, cv.Rounds, "should have completed at least one round")
+			}
+			assert.Equal(t, c.announced, obs.limit, "wrong announced instance limit")
+		})
+	}
+}
+
+// startObserver records the instance limit announced when the perturber starts.
+type startObserver struct {
+	limit int
+}
+
+func (o *startObserver) OnPerturb(m perturber.Message) {
+	if m.Kind == perturber.KindStart {
+		o.limit = m.CompilerInstances
+	}
+}
+
+func (*startObserver) OnBuild(builder.Message) {}
+
+func (*startObserver) OnCompilerConfig(compiler.Message) {}
+
+// TestShareCoverage tests that perturbers sharing a coverage map cover the configuration space together.
+func TestShareCoverage(t *testing.T) {
+	t.Parallel()
+
+	var cm perturber.CoverageMap
+	pts := make([]*perturber.Perturber, 2)
+	for j := range pts {
+		var err error
+		pts[j], err = perturber.New(
+			mockInspector(t),
+			perturber.UseStrategy(compiler.StrategyMatrix),
+			perturber.OverrideQuantities(quantity.PerturbSet{CompilerInstances: 3}),
+			perturber.UseSeed(8675309),
+			perturber.ShareCoverage(&cm),
+		)
+		require.NoError(t, err, "constructing perturber", j)
+	}
+
+	// Between them, the two perturbers should cover all six combinations in one cycle each.
+	gcc := id.FromString("gcc")
+	seen := map[id.ID]bool{}
+	for j, pt := range pts {
+		pm := plan.Mock()
+		pm.Compilers = compiler.InstanceMap{gcc: {Compiler: compiler.Compiler{Style: id.CStyleGCC}}}
+
+		np, err := pt.Run(context.Background(), pm)
+		require.NoError(t, err, "perturbing with perturber", j)
+		for cid := range np.Compilers {
+			assert.Falsef(t, seen[cid], "perturber %d repeated combination %s", j, cid)
+			seen[cid] = true
+		}
+	}
+	assert.Len(t, seen, 6, "every combination should have been seen")
+	assert.Equal(t, pts[0].Coverage(), pts[1].Coverage(), "perturbers should report the same coverage")
+	cv := pts[1].Coverage()[gcc]
+	assert.Equal(t, cv.Total, cv.Covered, "perturbers should have covered the space together")
+}
+
+// TestUseStrategy_bad tests that UseStrategy rejects out-of-range strategies.
+func TestUseStrategy_bad(t *testing.T) {
+	t.Parallel()
+
+	_, err := perturber.New(mockInspector(t), perturber.UseStrategy(compiler.NumStrategy))
+	testhelp.ExpectErrorIs(t, err, compiler.ErrBadStrategy, "constructing perturber")
+}
+
+func mockInspector(t *testing.T) *mocks.Inspector {
+	t.Helper()
+
+	var mi mocks.Inspector
+	mi.Test(t)
+	mi.On("DefaultOptLevels", mock.Anything).Return(stringhelp.NewSet("1", "2"), nil).Maybe()
+	mi.On("DefaultMOpts", mock.Anything).Return(stringhelp.NewSet("march=a", "march=b"), nil).Maybe()
+	mi.On("OptLevels", mock.Anything).Return(map[string]optlevel.Level{
+		"1": {Optimises: true, Bias: optlevel.BiasSize},
+		"2": {Optimises: true, Bias: optlevel.BiasSpeed},
+	}, nil).Maybe()
+	return &mi
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package perturber_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flagPool is a flag pool with one always-on flag, one never-selected flag, and a group.
+var flagPool = compiler.FlagPool{
+	Optional: map[string]float64{"-fno-inline": 1, "-funroll-loops": 0.000001},
+	Groups:   []compiler.FlagGroup{{Flags: []string{"-fwrapv", "-ftrapv"}, Probability: 1}},
+}
+
+// TestPerturber_Run_flags tests that the random strategy samples extra flags from the compiler's flag pool.
+func TestPerturber_Run_flags(t *testing.T) {
+	t.Parallel()
+
+	gcc := id.FromString("gcc")
+	groupSeen := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		pt, err := perturber.New(mockInspector(t), perturber.UseSeed(int64(i)))
+		require.NoError(t, err, "constructing perturber")
+
+		pm := plan.Mock()
+		pm.Compilers = compiler.InstanceMap{gcc: {Compiler: compiler.Compiler{Style: id.CStyleGCC, Flags: &flagPool}}}
+
+		np, err := pt.Run(context.Background(), pm)
+		require.NoError(t, err, "perturbing cycle", i)
+		require.Contains(t, np.Compilers, gcc, "random strategy should keep compiler IDs")
+
+		fs := np.Compilers[gcc].SelectedFlags
+		require.Len(t, fs, 2, "wrong number of flags on cycle", i)
+		assert.Equal(t, "-fno-inline", fs[0], "certain optional flag missing on cycle", i)
+		assert.Contains(t, []string{"-fwrapv", "-ftrapv"}, fs[1], "group flag missing on cycle", i)
+		groupSeen[fs[1]] = true
+	}
+	assert.Len(t, groupSeen, 2, "both group flags should have been picked at some point")
+}
+
+// TestPerturber_Run_flagsMatrix tests that the matrix strategy covers every combination of extra flags, up to the
+// number of compiler instances per cycle.
+func TestPerturber_Run_flagsMatrix(t *testing.T) {
+	t.Parallel()
+
+	// 3 optimisation levels, 2 machine profiles, 2 choices for each optional flag, and 3 choices for the group.
+	const total = 3 * 2 * 2 * 2 * 3
+
+	cases := map[string]struct {
+		limit int
+		want  int
+	}{
+		"unlimited":  {want: quantity.DefaultMatrixCompilerInstances},
+		"capped":     {limit: 10, want: 10},
+		"everything": {limit: total, want: total},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pt, err := perturber.New(mockInspector(t),
+				perturber.UseStrategy(compiler.StrategyMatrix),
+				perturber.OverrideQuantities(quantity.PerturbSet{CompilerInstances: c.limit}),
+			)
+			require.NoError(t, err, "constructing perturber")
+
+			pm := plan.Mock()
+			gcc := id.FromString("gcc")
+			pm.Compilers = compiler.InstanceMap{gcc: {Compiler: compiler.Compiler{Style: id.CStyleGCC, Flags: &flagPool}}}
+
+			np, err := pt.Run(context.Background(), pm)
+			require.NoError(t, err, "perturbing")
+			assert.Len(t, np.Compilers, c.want, "wrong number of instances")
+		})
+	}
+}
+
+// TestPerturber_Run_badFlags tests that the perturber rejects flag pools with out-of-range probabilities.
+func TestPerturber_Run_badFlags(t *testing.T) {
+	t.Parallel()
+
+	pt, err := perturber.New(mockInspector(t))
+	require.NoError(t, err, "constructing perturber")
+
+	pm := plan.Mock()
+	pool := compiler.FlagPool{Optional: map[string]float64{"-fwrapv": 1.5}}
+	pm.Compilers = compiler.InstanceMap{id.FromString("gcc"): {Compiler: compiler.Compiler{Style: id.CStyleGCC, Flags: &pool}}}
+
+	_, err = pt.Run(context.Background(), pm)
+	testhelp.ExpectErrorIs(t, err, compiler.ErrBadFlagProbability, "perturbing")
+}
@@ -28,7 +28,7 @@ type Kind uint8
 
 const (
 	// KindStart means that the perturber is starting.
-	// Quantities points to the perturber's quantity set.
+	// Quantities points to the perturber's quantity set, and CompilerInstances is the per-compiler instance limit.
 	KindStart Kind = iota
 	// KindSeedChanged means that the perturber has now changed the seed.
 	// Seed points to the new seed.
@@ -39,6 +39,9 @@ const (
 	// KindRandomisingOpts means that the perturber is now randomising the compiler optimisations.
 	// The selected compilers will be announced as a series of OnCompilerConfig messages.
 	KindRandomisingOpts
+	// KindChoseCompiler means that the bandit strategy has chosen a compiler configuration.
+	// Choice points to the choice made, including its weight and the reason for choosing it.
+	KindChoseCompiler
 )
 
 // Message is the type of messages sent through OnPerturb.
@@ -49,8 +52,15 @@ type Message struct {
 	// Quantities points to the quantity set on start messages.
 	Quantities *quantity.PerturbSet
 
+	// CompilerInstances is, on start messages, the maximum number of instances the perturber makes from each compiler
+	// under its strategy; zero means no maximum.
+	CompilerInstances int
+
 	// Seed points to the seed set on seed-changed messages.
 	Seed int64
+
+	// Choice points to the choice made on compiler-choice messages.
+	Choice *Choice
 }
 
 // OnPerturb sends a perturb message m to each observer in obs.
@@ -6,8 +6,12 @@
 package perturber
 
 import (
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/model/service/compiler"
 	"github.com/c4-project/c4t/internal/observing"
 	"github.com/c4-project/c4t/internal/quantity"
+	"github.com/c4-project/c4t/internal/subject/corpus"
 )
 
 // Option is the type of options to the Planner constructor.
@@ -61,3 +65,76 @@ func UseFullCompilerIDs(use bool) Option {
 		return nil
 	}
 }
+
+// UseStrategy sets the strategy the perturber uses to choose compiler instances.
+// By default, the perturber uses compiler.StrategyRandom.
+func UseStrategy(s compiler.Strategy) Option {
+	return func(p *Perturber) error {
+		if compiler.NumStrategy <= s {
+			return fmt.Errorf("%w: #%d", compiler.ErrBadStrategy, s)
+		}
+		p.strategy = s
+		return nil
+	}
+}
+
+// UseExploration sets the exploration constant for the bandit strategy to c.
+// If c is zero, the perturber uses DefaultExploration.
+func UseExploration(c float64) Option {
+	return func(p *Perturber) error {
+		if c < 0 {
+			return fmt.Errorf("%w: %g", ErrBadExploration, c)
+		}
+		if c == 0 {
+			c = DefaultExploration
+		}
+		p.exploration = c
+		return nil
+	}
+}
+
+// UseYields sets the source of historical yields for the bandit strategy and source weighting to src.
+// Without a source, the bandit treats every configuration as untried, and so chooses randomly; source weighting is
+// disabled.
+func UseYields(src YieldSource) Option {
+	return func(p *Perturber) error {
+		p.yields = src
+		return nil
+	}
+}
+
+// ShareCoverage makes the perturber track its coverage in m, which other perturbers may share.
+//
+// Perturbers sharing a coverage map between them cover each compiler's configuration space once, rather than once
+// each.  This is useful when several perturbers prepare cycles for the same machine.
+func ShareCoverage(m *CoverageMap) Option {
+	return func(p *Perturber) error {
+		if m != nil {
+			p.coverage = m
+		}
+		return nil
+	}
+}
+
+// UseSampleConfig sets the perturber to sample its corpus using the sampling config cfg, if non-nil.
+func UseSampleConfig(cfg *corpus.SampleConfig) Option {
+	return func(p *Perturber) error {
+		if cfg == nil {
+			return nil
+		}
+		if err := cfg.Strata.Check(); err != nil {
+			return err
+		}
+		p.strata = cfg.Strata
+		p.weightBySource = cfg.WeightBySource
+		return nil
+	}
+}
+
+// UseConfig applies the perturbation config cfg, if non-nil.
+func UseConfig(cfg *compiler.PerturbConfig) Option {
+	if cfg == nil {
+		return Options()
+	}
+	return Options(UseStrategy(cfg.Strategy), UseExploration(cfg.Exploration))
+}
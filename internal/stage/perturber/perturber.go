@@ -17,10 +17,16 @@ import (
 	"github.com/c4-project/c4t/internal/plan/stage"
 
 	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/subject/corpus"
 )
 
-// ErrCInspectorNil occurs if the perturber constructor is passed a nil compiler inspector.
-var ErrCInspectorNil = errors.New("compiler inspector nil")
+var (
+	// ErrCInspectorNil occurs if the perturber constructor is passed a nil compiler inspector.
+	ErrCInspectorNil = errors.New("compiler inspector nil")
+
+	// ErrBadExploration occurs if the perturber is given a negative exploration constant.
+	ErrBadExploration = errors.New("exploration constant must be non-negative")
+)
 
 // Perturber holds all configuration for the test perturber.
 type Perturber struct {
@@ -32,7 +38,19 @@ type Perturber struct {
 	useFullIDs bool
 	// quantities contains quantity information for this planner.
 	quantities quantity.PerturbSet
-	seed       int64
+	// strategy is the strategy used to choose compiler instances.
+	strategy compiler.Strategy
+	// exploration is the exploration constant for the bandit strategy.
+	exploration float64
+	// yields, if present, supplies historical yields to the bandit strategy.
+	yields YieldSource
+	// strata contains the strata used to sample the corpus; if empty, sampling is uniform.
+	strata corpus.Strata
+	// weightBySource makes sampling favour subjects whose sources have higher historical yields, if yields is present.
+	weightBySource bool
+	// coverage tracks, across runs, how much of each compiler's configuration space has been covered.
+	coverage *CoverageMap
+	seed     int64
 }
 
 // New constructs a new perturber with the given compiler inspector and options.
@@ -42,8 +60,10 @@ func New(ci compiler.Inspector, opts ...Option) (*Perturber, error) {
 	}
 
 	p := &Perturber{
-		ci:   ci,
-		seed: plan.UseDateSeed,
+		ci:          ci,
+		seed:        plan.UseDateSeed,
+		exploration: DefaultExploration,
+		coverage:    new(CoverageMap),
 	}
 	if err := Options(opts...)(p); err != nil {
 		return nil, err
@@ -74,7 +94,7 @@ func (p *Perturber) Run(_ context.Context, inplan *plan.Plan) (*plan.Plan, error
 }
 
 func (p *Perturber) perturbCopy(pn *plan.Plan) error {
-	p.announce(Message{Kind: KindStart, Quantities: &p.quantities})
+	p.announce(Message{Kind: KindStart, Quantities: &p.quantities, CompilerInstances: p.compilerInstances()})
 
 	p.perturbMetadata(pn)
 	rng := pn.Metadata.Rand()
@@ -73,6 +73,8 @@ func (p *CorpusPlanner) makeBuilderConfig() builder.Config {
 			Name:  "plan",
 			NReqs: len(p.Files),
 		},
+		// Input directories often contain the same test under different names.
+		Dedup: true,
 	}
 }
 
@@ -0,0 +1,55 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package reducer
+
+import (
+	"github.com/c4-project/c4t/internal/observing"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// Observer is the interface of types that can observe the progress of a reducer.
+type Observer interface {
+	// OnReduce announces that a reduction is starting, stopping, or progressing.
+	OnReduce(m Message)
+}
+
+// Message is the type of messages announcing reduction progress.
+type Message struct {
+	observing.Batch
+
+	// Name contains, on start messages, the subject and compiler whose run is being reduced.
+	Name compilation.Name
+
+	// Status contains, on start messages, the bad status that each candidate must reproduce.
+	Status status.Status
+
+	// NSteps contains, on step messages, the number of trace steps in the candidate; on end messages, it contains
+	// the number of steps in the smallest reproducing trace.
+	NSteps int
+
+	// Reproduced contains, on step messages, whether the candidate reproduced the bad status.
+	Reproduced bool
+}
+
+// OnReduce broadcasts reduce message m to all observers o.
+func OnReduce(m Message, o ...Observer) {
+	for _, obs := range o {
+		obs.OnReduce(m)
+	}
+}
+
+func start(n compilation.Name, s status.Status, nsteps int) Message {
+	return Message{Batch: observing.NewBatchStart(nsteps), Name: n, Status: s}
+}
+
+func step(i, nsteps int, repro bool) Message {
+	return Message{Batch: observing.NewBatchStep(i), NSteps: nsteps, Reproduced: repro}
+}
+
+func end(nsteps int) Message {
+	return Message{Batch: observing.NewBatchEnd(), NSteps: nsteps}
+}
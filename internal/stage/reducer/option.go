@@ -0,0 +1,83 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package reducer
+
+import (
+	"errors"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan"
+)
+
+var (
+	// ErrObserverNil occurs when we try to pass a nil observer as an option.
+	ErrObserverNil = errors.New("observer nil")
+
+	// ErrCheckerNil occurs when we try to pass a nil checker as an option.
+	ErrCheckerNil = errors.New("checker nil")
+)
+
+// Option is the type of options to pass to New.
+type Option func(*Reducer) error
+
+// Options bundles up each option in os into a single option.
+func Options(os ...Option) Option {
+	return func(r *Reducer) error {
+		for _, o := range os {
+			if err := o(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ObserveWith adds each observer in obs to the reducer's observer list.
+func ObserveWith(obs ...Observer) Option {
+	return func(r *Reducer) error {
+		for _, o := range obs {
+			if o == nil {
+				return ErrObserverNil
+			}
+		}
+		r.obs = append(r.obs, obs...)
+		return nil
+	}
+}
+
+// CheckWith appends each runner in rs to the chain of stages used to check each candidate.
+//
+// The chain should take a fuzzed plan up to the point where it has run results; typically, this is a lifter followed
+// by an invoker (which must permit re-invocation) and, if the original plan used one, an oracle.
+// The reducer takes ownership of each runner, and will close it when closed.
+func CheckWith(rs ...plan.Runner) Option {
+	return func(r *Reducer) error {
+		for _, c := range rs {
+			if c == nil {
+				return ErrCheckerNil
+			}
+		}
+		r.checkers = append(r.checkers, rs...)
+		return nil
+	}
+}
+
+// ForSubject sets the name of the subject to reduce.
+func ForSubject(name string) Option {
+	return func(r *Reducer) error {
+		r.subject = name
+		return nil
+	}
+}
+
+// ForCompiler sets the ID of the compiler whose bad run should be reproduced.
+// If not set, the reducer picks the first compiler, in ID order, on which the subject has a bad run.
+func ForCompiler(cid id.ID) Option {
+	return func(r *Reducer) error {
+		r.compiler = cid
+		return nil
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package reducer
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Pathset contains the pathset for a reducer.
+type Pathset struct {
+	// DirRoot is the root directory for the reducer's candidates.
+	DirRoot string
+}
+
+// NewPathset makes a pathset with root directory root.
+func NewPathset(root string) *Pathset {
+	return &Pathset{DirRoot: root}
+}
+
+// DirCandidate gets the directory holding the files for candidate i.
+func (p *Pathset) DirCandidate(i int) string {
+	return filepath.Join(p.DirRoot, strconv.Itoa(i))
+}
+
+// CandidateTrace gets the path of the trace file for candidate i of subject sname.
+func (p *Pathset) CandidateTrace(sname string, i int) string {
+	return filepath.Join(p.DirCandidate(i), sname+".trace")
+}
+
+// CandidateLitmus gets the path of the replayed litmus file for candidate i of subject sname.
+func (p *Pathset) CandidateLitmus(sname string, i int) string {
+	return filepath.Join(p.DirCandidate(i), sname+".litmus")
+}
+
+// PrepareCandidate makes the directory for candidate i.
+func (p *Pathset) PrepareCandidate(i int) error {
+	return os.MkdirAll(p.DirCandidate(i), 0744)
+}
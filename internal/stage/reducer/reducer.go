@@ -0,0 +1,301 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package reducer contains the part of the tester framework that shrinks bad fuzzer outputs.
+//
+// The reducer takes a plan containing a fuzzed subject with a bad run, and repeatedly replays cut-down versions of
+// that subject's fuzzer trace against its original litmus test.  It checks each candidate by lifting and invoking it
+// with the same compiler instance as the original run, and keeps the smallest trace that still reproduces the same bad
+// status.
+package reducer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/c4-project/c4t/internal/helper/ddmin"
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/confirm"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+var (
+	// ErrDriverNil occurs when a reducer is constructed without a driver.
+	ErrDriverNil = errors.New("driver nil")
+
+	// ErrNoCheckers occurs when a reducer is constructed without any checking stages.
+	ErrNoCheckers = errors.New("no checkers supplied")
+
+	// ErrNoSubject occurs when a reducer is constructed without a subject to reduce.
+	ErrNoSubject = errors.New("no subject supplied")
+
+	// ErrMissingSubject occurs when the subject to reduce isn't in the plan.
+	ErrMissingSubject = errors.New("subject not in plan")
+
+	// ErrNoTrace occurs when the subject to reduce has no fuzzer trace.
+	ErrNoTrace = errors.New("subject has no fuzzer trace")
+
+	// ErrNotBad occurs when the subject to reduce has no bad run on the requested compiler.
+	ErrNotBad = errors.New("subject has no bad run to reduce")
+
+	// ErrNotReproduced occurs when replaying the subject's full trace doesn't reproduce its bad run.
+	ErrNotReproduced = errors.New("full trace doesn't reproduce bad run")
+)
+
+// Driver groups the interfaces used to 'drive' the reducer.
+type Driver interface {
+	fuzzer.Replayer
+	litmus.StatDumper
+}
+
+// Reducer holds the main configuration for the reduction part of the tester framework.
+type Reducer struct {
+	// driver is the driver used to replay traces and populate litmus statistics.
+	driver Driver
+
+	// paths is the pathset in which the reducer keeps its candidates.
+	paths *Pathset
+
+	// checkers is the chain of stages that take each candidate plan up to its run results.
+	checkers []plan.Runner
+
+	// subject is the name of the subject to reduce.
+	subject string
+
+	// compiler is the ID of the compiler whose run should be reproduced; if empty, we pick one.
+	compiler id.ID
+
+	// obs track the reducer's progress.
+	obs []Observer
+}
+
+// New constructs a new Reducer that replays traces using d, in pathset ps, with options os.
+func New(d Driver, ps *Pathset, os ...Option) (*Reducer, error) {
+	if d == nil {
+		return nil, ErrDriverNil
+	}
+	r := Reducer{driver: d, paths: ps}
+	if err := Options(os...)(&r); err != nil {
+		return nil, err
+	}
+	if len(r.checkers) == 0 {
+		return nil, ErrNoCheckers
+	}
+	if r.subject == "" {
+		return nil, ErrNoSubject
+	}
+	return &r, nil
+}
+
+// Stage gets the stage for this Reducer.
+func (*Reducer) Stage() stage.Stage {
+	return stage.Reduce
+}
+
+// Close closes each of the reducer's checkers.
+func (r *Reducer) Close() error {
+	errs := make([]error, len(r.checkers))
+	for i, c := range r.checkers {
+		errs[i] = c.Close()
+	}
+	return errhelp.FirstError(errs...)
+}
+
+// Run reduces the configured subject in p, returning a plan containing only the smallest reproducing candidate.
+func (r *Reducer) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
+	if err := checkPlan(p); err != nil {
+		return nil, err
+	}
+	s, ok := p.Corpus[r.subject]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMissingSubject, r.subject)
+	}
+	if s.Fuzz == nil || s.Fuzz.Trace == "" {
+		return nil, fmt.Errorf("%w: %q", ErrNoTrace, r.subject)
+	}
+	cid, want, err := r.target(s)
+	if err != nil {
+		return nil, err
+	}
+	tr, err := fuzzer.ReadTraceFile(filepath.FromSlash(s.Fuzz.Trace))
+	if err != nil {
+		return nil, err
+	}
+
+	j := job{
+		base:   basePlan(p, cid),
+		source: subject.Named{Name: r.subject, Subject: s},
+		name:   compilation.Name{SubjectName: r.subject, CompilerID: cid},
+		want:   want,
+	}
+	OnReduce(start(j.name, want, tr.Len()), r.obs...)
+	best, err := r.reduce(ctx, &j, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Later candidates will have clobbered the checkers' working files, so we re-check the winner.
+	_, rp, err := r.try(ctx, &j, best)
+	if err != nil {
+		return nil, err
+	}
+	OnReduce(end(best.Len()), r.obs...)
+	return rp, nil
+}
+
+func checkPlan(p *plan.Plan) error {
+	if p == nil {
+		return plan.ErrNil
+	}
+	if err := p.Check(); err != nil {
+		return err
+	}
+	return p.Metadata.RequireStage(stage.Fuzz, stage.Invoke)
+}
+
+// target works out which compiler, and which bad status, the reducer should be chasing in s.
+func (r *Reducer) target(s subject.Subject) (id.ID, status.Status, error) {
+	if !r.compiler.IsEmpty() {
+		rr, err := s.RunResult(r.compiler)
+		if err != nil {
+			return id.ID{}, status.Unknown, err
+		}
+		if !confirm.Confirmable(rr.Status) {
+			return id.ID{}, status.Unknown, fmt.Errorf("%w: compiler %q has status %s", ErrNotBad, r.compiler, rr.Status)
+		}
+		return r.compiler, rr.Status, nil
+	}
+
+	cids, err := id.MapKeys(s.Compilations)
+	if err != nil {
+		return id.ID{}, status.Unknown, err
+	}
+	for _, cid := range cids {
+		if rr := s.Compilations[cid].Run; rr != nil && confirm.Confirmable(rr.Status) {
+			return cid, rr.Status, nil
+		}
+	}
+	return id.ID{}, status.Unknown, fmt.Errorf("%w: %q", ErrNotBad, r.subject)
+}
+
+// basePlan cuts p down to the compiler cid, and strips any stages after fuzzing from its metadata.
+func basePlan(p *plan.Plan, cid id.ID) plan.Plan {
+	bp := *p
+	bp.Compilers = compiler.InstanceMap{cid: p.Compilers[cid]}
+	bp.Metadata.Stages = nil
+	for _, rec := range p.Metadata.Stages {
+		if rec.Stage <= stage.Fuzz {
+			bp.Metadata.Stages = append(bp.Metadata.Stages, rec)
+		}
+	}
+	return bp
+}
+
+// job holds the state of a single reduction.
+type job struct {
+	// base is the plan on which each candidate plan is based.
+	base plan.Plan
+	// source is the original subject.
+	source subject.Named
+	// name identifies the run being reproduced.
+	name compilation.Name
+	// want is the status being reproduced.
+	want status.Status
+	// ncands is the number of candidates tried so far.
+	ncands int
+}
+
+// reduce minimises tr, returning the smallest reproducing trace found.
+func (r *Reducer) reduce(ctx context.Context, j *job, tr *fuzzer.Trace) (*fuzzer.Trace, error) {
+	ok, _, err := r.try(ctx, j, tr)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotReproduced, j.name)
+	}
+
+	keep, err := ddmin.Minimise(ctx, tr.Len(), func(ctx context.Context, keep []int) (bool, error) {
+		ok, _, err := r.try(ctx, j, tr.Select(keep))
+		return ok, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tr.Select(keep), nil
+}
+
+// try replays and checks candidate trace tr, returning whether it reproduced the bad status and the checked plan.
+//
+// Failures to replay the trace count as failures to reproduce, as cutting steps out of a trace can easily make it
+// inconsistent; failures in the checkers are fatal.
+func (r *Reducer) try(ctx context.Context, j *job, tr *fuzzer.Trace) (bool, *plan.Plan, error) {
+	i := j.ncands
+	j.ncands++
+
+	cp, err := r.candidate(ctx, j, tr, i)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, nil, ctx.Err()
+		}
+		OnReduce(step(i, tr.Len(), false), r.obs...)
+		return false, nil, nil
+	}
+	for _, c := range r.checkers {
+		if cp, err = cp.RunStage(ctx, c); err != nil {
+			return false, nil, fmt.Errorf("checking candidate %d: %w", i, err)
+		}
+	}
+
+	ok := cp.Corpus.Reproduces(j.name, j.want)
+	OnReduce(step(i, tr.Len(), ok), r.obs...)
+	return ok, cp, nil
+}
+
+// candidate replays tr into candidate i, building a plan for checking it.
+func (r *Reducer) candidate(ctx context.Context, j *job, tr *fuzzer.Trace, i int) (*plan.Plan, error) {
+	if err := r.paths.PrepareCandidate(i); err != nil {
+		return nil, err
+	}
+	tpath := r.paths.CandidateTrace(j.source.Name, i)
+	if err := tr.WriteFile(tpath); err != nil {
+		return nil, err
+	}
+	rj := fuzzer.ReplayJob{
+		In:        j.source.Source.Path,
+		Trace:     filepath.ToSlash(tpath),
+		OutLitmus: filepath.ToSlash(r.paths.CandidateLitmus(j.source.Name, i)),
+	}
+	if err := r.driver.Replay(ctx, rj); err != nil {
+		return nil, err
+	}
+	l, err := litmus.New(rj.OutLitmus, litmus.WithArch(id.ArchC), litmus.PopulateStatsFrom(ctx, r.driver))
+	if err != nil {
+		return nil, err
+	}
+
+	s := j.source.Subject
+	s.Fuzz = &subject.Fuzz{Litmus: *l, Trace: rj.Trace}
+	s.Transform = nil
+	s.Compilations = nil
+	s.Recipes = nil
+	s.Oracle = nil
+
+	cp := j.base
+	cp.Metadata.Stages = append([]stage.Record(nil), j.base.Metadata.Stages...)
+	cp.Corpus = corpus.Corpus{j.source.Name: s}
+	return &cp, nil
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package reducer_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/service/compiler"
+	"github.com/c4-project/c4t/internal/model/service/fuzzer"
+	"github.com/c4-project/c4t/internal/observing"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/stage/reducer"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+	"github.com/c4-project/c4t/internal/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNew_errors tests the error result of New in various situations.
+func TestNew_errors(t *testing.T) {
+	t.Parallel()
+
+	opterr := errors.New("oopsie")
+	ok := []reducer.Option{reducer.ForSubject("foo"), reducer.CheckWith(fakeChecker{})}
+
+	cases := map[string]struct {
+		d   reducer.Driver
+		os  []reducer.Option
+		err error
+	}{
+		"ok":          {d: fakeDriver{}, os: ok},
+		"nil-driver":  {os: ok, err: reducer.ErrDriverNil},
+		"no-checkers": {d: fakeDriver{}, os: []reducer.Option{reducer.ForSubject("foo")}, err: reducer.ErrNoCheckers},
+		"no-subject":  {d: fakeDriver{}, os: []reducer.Option{reducer.CheckWith(fakeChecker{})}, err: reducer.ErrNoSubject},
+		"nil-checker": {d: fakeDriver{}, os: append(ok, reducer.CheckWith(nil)), err: reducer.ErrCheckerNil},
+		"nil-observer": {
+			d:   fakeDriver{},
+			os:  append(ok, reducer.ObserveWith(nil)),
+			err: reducer.ErrObserverNil,
+		},
+		"opt-err": {
+			d:   fakeDriver{},
+			os:  append(ok, func(*reducer.Reducer) error { return opterr }),
+			err: opterr,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := reducer.New(c.d, reducer.NewPathset(t.TempDir()), c.os...)
+			testhelp.ExpectErrorIs(t, err, c.err, "constructing reducer")
+		})
+	}
+}
+
+// TestReducer_Run tests running the reducer on a scripted bad trace.
+func TestReducer_Run(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	gcc, clang := id.FromString("gcc"), id.FromString("clang")
+
+	var obs recordingObserver
+	r, err := reducer.New(
+		fakeDriver{},
+		reducer.NewPathset(filepath.Join(dir, "reduce")),
+		reducer.ForSubject("foo"),
+		reducer.CheckWith(fakeChecker{}),
+		reducer.ObserveWith(&obs),
+	)
+	require.NoError(t, err, "constructing reducer")
+
+	p := mockPlan(t, dir, "(a b c d e f g h)", gcc, clang)
+	p2, err := r.Run(context.Background(), p)
+	require.NoError(t, err, "running reducer")
+
+	assert.Equal(t, compiler.InstanceMap{clang: p.Compilers[clang]}, p2.Compilers, "reduced plan should only have clang")
+	require.Contains(t, p2.Corpus, "foo", "reduced plan should contain subject")
+	s := p2.Corpus["foo"]
+	tr, err := fuzzer.ReadTraceFile(s.Fuzz.Trace)
+	require.NoError(t, err, "reading reduced trace")
+	assert.Equal(t, []string{"c", "f"}, tr.Steps, "wrong reduced trace")
+	rr, err := s.RunResult(clang)
+	require.NoError(t, err, "getting reduced run")
+	assert.Equal(t, status.Flagged, rr.Status, "reduced run should still be flagged")
+
+	require.NotEmpty(t, obs.msgs, "observer should have seen messages")
+	assert.Equal(t, observing.BatchStart, obs.msgs[0].Kind, "first message should be a start")
+	assert.Equal(t, compilation.Name{SubjectName: "foo", CompilerID: clang}, obs.msgs[0].Name, "wrong target")
+	last := obs.msgs[len(obs.msgs)-1]
+	assert.Equal(t, observing.BatchEnd, last.Kind, "last message should be an end")
+	assert.Equal(t, 2, last.NSteps, "wrong final size")
+
+	assert.Equal(t, status.Flagged, p.Corpus["foo"].Compilations[clang].Run.Status, "input plan modified")
+}
+
+// TestReducer_Run_errors tests the error result of running the reducer in various situations.
+func TestReducer_Run_errors(t *testing.T) {
+	t.Parallel()
+
+	gcc, clang := id.FromString("gcc"), id.FromString("clang")
+
+	cases := map[string]struct {
+		trace string
+		os    []reducer.Option
+		setup func(p *plan.Plan)
+		err   error
+	}{
+		"missing-subject": {
+			trace: "(c f)",
+			os:    []reducer.Option{reducer.ForSubject("bar")},
+			err:   reducer.ErrMissingSubject,
+		},
+		"no-trace": {
+			trace: "(c f)",
+			setup: func(p *plan.Plan) {
+				s := p.Corpus["foo"]
+				s.Fuzz = nil
+				p.Corpus["foo"] = s
+			},
+			err: reducer.ErrNoTrace,
+		},
+		"not-bad": {
+			trace: "(c f)",
+			os:    []reducer.Option{reducer.ForCompiler(gcc)},
+			err:   reducer.ErrNotBad,
+		},
+		"not-reproduced": {
+			trace: "(c d)",
+			err:   reducer.ErrNotReproduced,
+		},
+		"not-invoked": {
+			trace: "(c f)",
+			setup: func(p *plan.Plan) {
+				p.Metadata.Stages = p.Metadata.Stages[:1]
+			},
+			err: plan.ErrMissingStage,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+
+			os := append([]reducer.Option{reducer.ForSubject("foo"), reducer.CheckWith(fakeChecker{})}, c.os...)
+			r, err := reducer.New(fakeDriver{}, reducer.NewPathset(filepath.Join(dir, "reduce")), os...)
+			require.NoError(t, err, "constructing reducer")
+
+			p := mockPlan(t, dir, c.trace, gcc, clang)
+			if c.setup != nil {
+				c.setup(p)
+			}
+			_, err = r.Run(context.Background(), p)
+			testhelp.ExpectErrorIs(t, err, c.err, "running reducer")
+		})
+	}
+}
+
+// mockPlan makes a plan whose subject foo has trace text trace, and is fine on ok but flagged on bad.
+func mockPlan(t *testing.T, dir, trace string, ok, bad id.ID) *plan.Plan {
+	t.Helper()
+
+	tpath := filepath.Join(dir, "foo.trace")
+	require.NoError(t, os.WriteFile(tpath, []byte(trace), 0644), "writing trace")
+
+	p := plan.Mock()
+	p.Metadata.Stages = nil
+	p.Metadata.ConfirmStage(stage.Fuzz, timing.Span{})
+	p.Metadata.ConfirmStage(stage.Invoke, timing.Span{})
+	p.Compilers = compiler.InstanceMap{ok: {}, bad: {}}
+
+	s := subject.NewOrPanic(
+		litmus.NewOrPanic("foo.litmus"),
+		subject.WithRun(ok, compilation.RunResult{Result: compilation.Result{Status: status.Ok}}),
+		subject.WithRun(bad, compilation.RunResult{Result: compilation.Result{Status: status.Flagged}}),
+	)
+	s.Fuzz = &subject.Fuzz{Litmus: *litmus.NewOrPanic(filepath.Join(dir, "foo.fuzz.litmus")), Trace: tpath}
+	p.Corpus = corpus.Corpus{"foo": *s}
+	return p
+}
+
+// fakeDriver 'replays' traces by copying them into the output litmus file.
+//
+// To simulate inconsistent traces, it fails to replay any trace that contains step d without step b.
+type fakeDriver struct{}
+
+func (fakeDriver) Replay(_ context.Context, j fuzzer.ReplayJob) error {
+	tr, err := fuzzer.ReadTraceFile(j.Trace)
+	if err != nil {
+		return err
+	}
+	if hasSteps(tr, "d") && !hasSteps(tr, "b") {
+		return errors.New("step d needs step b")
+	}
+	return tr.WriteFile(j.OutLitmus)
+}
+
+func (fakeDriver) DumpStats(context.Context, *litmus.Statset, string) error {
+	return nil
+}
+
+// fakeChecker flags every run of a subject whose litmus file contains steps c and f.
+type fakeChecker struct{}
+
+func (fakeChecker) Stage() stage.Stage {
+	return stage.Invoke
+}
+
+func (fakeChecker) Run(_ context.Context, p *plan.Plan) (*plan.Plan, error) {
+	np := *p
+	np.Corpus = make(corpus.Corpus, len(p.Corpus))
+	for sname, s := range p.Corpus {
+		tr, err := fuzzer.ReadTraceFile(s.Fuzz.Litmus.Path)
+		if err != nil {
+			return nil, err
+		}
+		st := status.Ok
+		if hasSteps(tr, "c", "f") {
+			st = status.Flagged
+		}
+		s.Compilations = make(compilation.Map, len(p.Compilers))
+		for cid := range p.Compilers {
+			s.Compilations[cid] = compilation.Compilation{Run: &compilation.RunResult{Result: compilation.Result{Status: st}}}
+		}
+		np.Corpus[sname] = s
+	}
+	return &np, nil
+}
+
+func (fakeChecker) Close() error {
+	return nil
+}
+
+func hasSteps(tr *fuzzer.Trace, steps ...string) bool {
+	all := strings.Join(tr.Steps, " ")
+	for _, s := range steps {
+		if !strings.Contains(all, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordingObserver records every reduce message it receives.
+type recordingObserver struct {
+	msgs []reducer.Message
+}
+
+func (r *recordingObserver) OnReduce(m reducer.Message) {
+	r.msgs = append(r.msgs, m)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package regressor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/subject/corpus"
+)
+
+// ErrBadShare occurs when the regression share is outside the range [0, 1].
+var ErrBadShare = errors.New("regression share must be between 0 and 1")
+
+// Option is the type of options to New.
+type Option func(*Regressor) error
+
+// Options applies each option in os in turn.
+func Options(os ...Option) Option {
+	return func(r *Regressor) error {
+		for _, o := range os {
+			if err := o(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// UseShare sets the proportion of each corpus that the regressor replaces with regression subjects.
+func UseShare(share float64) Option {
+	return func(r *Regressor) error {
+		if share < 0 || 1 < share {
+			return fmt.Errorf("%w: %g", ErrBadShare, share)
+		}
+		r.share = share
+		return nil
+	}
+}
+
+// UseSampleConfig sets the regression share from cfg, if it is non-nil.
+func UseSampleConfig(cfg *corpus.SampleConfig) Option {
+	if cfg == nil {
+		return Options()
+	}
+	return UseShare(cfg.RegressShare)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package regressor contains the part of the tester framework that retests previously saved subjects.
+//
+// The director keeps a regression corpus of every subject that a cycle flags, along with its fuzzed litmus test.
+// The regressor stage replaces a configurable share of each cycle's corpus with a sample from that regression corpus,
+// so that we notice when a new compiler build fixes or regresses a known bug.
+package regressor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+)
+
+// ErrStoreNil occurs when a regressor is constructed without a store.
+var ErrStoreNil = errors.New("regression store nil")
+
+// Regressor holds the main configuration for the regression part of the tester framework.
+type Regressor struct {
+	// store is the store from which the regressor draws previously saved subjects.
+	store *Store
+	// share is the proportion of each corpus that the regressor replaces with regression subjects.
+	share float64
+}
+
+// New constructs a regressor that draws subjects from store, with options os.
+func New(store *Store, os ...Option) (*Regressor, error) {
+	if store == nil {
+		return nil, ErrStoreNil
+	}
+	r := Regressor{store: store}
+	if err := Options(os...)(&r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Stage gets the stage for this Regressor.
+func (*Regressor) Stage() stage.Stage {
+	return stage.Regress
+}
+
+// Close does nothing.
+func (*Regressor) Close() error {
+	return nil
+}
+
+// Run replaces the configured share of the corpus in p with subjects from the regression corpus.
+//
+// If the regression corpus has too few subjects to fill the share, the regressor uses all of them.
+func (r *Regressor) Run(_ context.Context, p *plan.Plan) (*plan.Plan, error) {
+	if p == nil {
+		return nil, plan.ErrNil
+	}
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+	rc, err := r.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	np := *p
+	if np.Corpus, err = r.mix(p.Metadata.Rand(), p.Corpus, rc); err != nil {
+		return nil, err
+	}
+	return &np, nil
+}
+
+// mix replaces the configured share of c with a sample of rc.
+func (r *Regressor) mix(rng *rand.Rand, c, rc corpus.Corpus) (corpus.Corpus, error) {
+	want := int(math.Round(r.share * float64(len(c))))
+	if want <= 0 || len(rc) == 0 {
+		return c, nil
+	}
+	rs, err := rc.Sample(rng, want)
+	if err != nil {
+		return nil, err
+	}
+
+	mc := corpus.Corpus{}
+	// Sample treats a non-positive size as 'take everything', so we need to special-case replacing all of c.
+	if keep := len(c) - len(rs); 0 < keep {
+		kc, err := c.Sample(rng, keep)
+		if err != nil {
+			return nil, err
+		}
+		mc = kc.Copy()
+	}
+	for n, s := range rs {
+		mc[n] = s
+	}
+	return mc, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package regressor_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/stage/regressor"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNew_errors tests the error result of New in various situations.
+func TestNew_errors(t *testing.T) {
+	t.Parallel()
+
+	st := regressor.NewStore("regress")
+
+	cases := map[string]struct {
+		st  *regressor.Store
+		os  []regressor.Option
+		err error
+	}{
+		"ok":         {st: st, os: []regressor.Option{regressor.UseShare(0.5)}},
+		"nil-config": {st: st, os: []regressor.Option{regressor.UseSampleConfig(nil)}},
+		"nil-store":  {err: regressor.ErrStoreNil},
+		"low-share":  {st: st, os: []regressor.Option{regressor.UseShare(-0.1)}, err: regressor.ErrBadShare},
+		"high-share": {
+			st:  st,
+			os:  []regressor.Option{regressor.UseSampleConfig(&corpus.SampleConfig{RegressShare: 1.5})},
+			err: regressor.ErrBadShare,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := regressor.New(c.st, c.os...)
+			testhelp.ExpectErrorIs(t, err, c.err, "constructing regressor")
+		})
+	}
+}
+
+// TestRegressor_Run tests that the regressor replaces the right number of subjects in a plan.
+func TestRegressor_Run(t *testing.T) {
+	t.Parallel()
+
+	in := t.TempDir()
+	st := regressor.NewStore(filepath.Join(t.TempDir(), "regress"))
+	rc := make(corpus.Corpus, 3)
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("bad%d", i)
+		path := writeLitmus(t, in, name+".litmus", fmt.Sprintf("C %s { x = %d; }", name, i))
+		rc[name] = *subject.NewOrPanic(litmus.NewOrPanic(path))
+	}
+	_, err := st.Add(rc)
+	require.NoError(t, err, "populating store")
+	empty := regressor.NewStore(filepath.Join(t.TempDir(), "empty"))
+
+	cases := map[string]struct {
+		st       *regressor.Store
+		share    float64
+		size     int
+		nregress int
+	}{
+		"no-share":    {st: st, share: 0, size: 10, nregress: 0},
+		"empty-store": {st: empty, share: 0.5, size: 10, nregress: 0},
+		"some":        {st: st, share: 0.2, size: 10, nregress: 2},
+		"too-few":     {st: st, share: 0.5, size: 10, nregress: 3},
+		"all":         {st: st, share: 1, size: 3, nregress: 3},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := regressor.New(c.st, regressor.UseShare(c.share))
+			require.NoError(t, err, "constructing regressor")
+
+			p := plan.Mock()
+			p.Corpus = make(corpus.Corpus, c.size)
+			for i := 0; i < c.size; i++ {
+				p.Corpus[fmt.Sprintf("s%d", i)] = *subject.NewOrPanic(litmus.NewOrPanic(fmt.Sprintf("s%d.litmus", i)))
+			}
+
+			p2, err := r.Run(context.Background(), p)
+			require.NoError(t, err, "running regressor")
+			assert.Len(t, p2.Corpus, c.size, "regressor shouldn't change corpus size")
+			assert.Len(t, p.Corpus, c.size, "regressor shouldn't modify input corpus")
+
+			var nregress int
+			for n := range p2.Corpus {
+				if strings.HasPrefix(n, regressor.NamePrefix) {
+					nregress++
+				}
+			}
+			assert.Equal(t, c.nregress, nregress, "wrong number of regression subjects")
+		})
+	}
+}
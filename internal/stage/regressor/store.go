@@ -0,0 +1,258 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package regressor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/c4-project/c4t/internal/helper/errhelp"
+	"github.com/c4-project/c4t/internal/helper/iohelp"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+)
+
+const (
+	// NamePrefix prefixes the name of every subject in a regression corpus.
+	NamePrefix = "regress_"
+
+	indexBasename = "index.json"
+	segSource     = "source"
+	segFuzz       = "fuzz"
+	segTransform  = "transform"
+	// hashLen is the number of characters of a subject's canonical hash that go into its regression name.
+	hashLen = 12
+)
+
+// Store is a regression corpus kept on disk.
+//
+// Each subject in the store has its own directory containing copies of its original, fuzzed, and transformed litmus
+// tests, and the store keeps an index of subjects, with paths relative to its root, alongside them.
+//
+// A store may be shared between goroutines (for instance, several director instances on the same machine), but not
+// between processes.
+type Store struct {
+	// DirRoot is the root directory of the store.
+	DirRoot string
+
+	// mu serialises access to the store's index.
+	mu sync.Mutex
+}
+
+// NewStore makes a store with root directory root.
+func NewStore(root string) *Store {
+	return &Store{DirRoot: root}
+}
+
+// FileIndex gets the path to the store's index file.
+func (s *Store) FileIndex() string {
+	return filepath.Join(s.DirRoot, indexBasename)
+}
+
+// Load loads the regression corpus from the store, with paths pointing into the store.
+// If the store doesn't exist yet, the corpus is empty.
+func (s *Store) Load() (corpus.Corpus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx, err := s.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	c := make(corpus.Corpus, len(idx))
+	for n, sub := range idx {
+		c[n] = s.rebase(sub)
+	}
+	return c, nil
+}
+
+// Add adds copies of each subject in c to the store, returning the names under which new subjects were added.
+//
+// Subjects are named by the canonical hash of their best litmus test, and a subject whose hash is already in the
+// store is skipped.  The copies keep only the subjects' litmus tests, fuzzer output, and transformer output, not any
+// compilations.
+func (s *Store) Add(c corpus.Corpus) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx, err := s.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	var added []string
+	for _, n := range c.Names() {
+		sub := c[n]
+		hash, err := bestHash(&sub)
+		if err != nil {
+			return nil, err
+		}
+		rname := regressName(hash)
+		if _, ok := idx[rname]; ok {
+			continue
+		}
+		if idx[rname], err = s.copySubject(rname, sub, hash); err != nil {
+			return nil, err
+		}
+		added = append(added, rname)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+	return added, s.writeIndex(idx)
+}
+
+// regressName gets the name under which the subject with canonical hash hash goes in the store.
+func regressName(hash string) string {
+	if hashLen < len(hash) {
+		hash = hash[:hashLen]
+	}
+	return NamePrefix + hash
+}
+
+// bestHash gets the canonical hash of the best litmus test of sub, computing it if not already known.
+func bestHash(sub *subject.Subject) (string, error) {
+	l, err := sub.BestLitmus()
+	if err != nil {
+		return "", err
+	}
+	if l.Hash != "" {
+		return l.Hash, nil
+	}
+	return litmus.CanonicalHashOfFile(l.Filepath())
+}
+
+// copySubject copies the files of sub into the directory for rname, returning its index entry.
+func (s *Store) copySubject(rname string, sub subject.Subject, hash string) (subject.Subject, error) {
+	var (
+		rs  = subject.Subject{Source: sub.Source}
+		err error
+	)
+	if sub.Source.HasPath() {
+		if rs.Source.Path, err = s.copyFile(rname, segSource, sub.Source.Path); err != nil {
+			return rs, err
+		}
+	}
+	if sub.Fuzz != nil {
+		f := *sub.Fuzz
+		if f.Litmus.HasPath() {
+			if f.Litmus.Path, err = s.copyFile(rname, segFuzz, f.Litmus.Path); err != nil {
+				return rs, err
+			}
+		}
+		if f.Trace != "" {
+			if f.Trace, err = s.copyFile(rname, segFuzz, f.Trace); err != nil {
+				return rs, err
+			}
+		}
+		rs.Fuzz = &f
+	}
+	if sub.Transform != nil {
+		tf := *sub.Transform
+		if tf.Litmus.HasPath() {
+			if tf.Litmus.Path, err = s.copyFile(rname, segTransform, tf.Litmus.Path); err != nil {
+				return rs, err
+			}
+		}
+		rs.Transform = &tf
+	}
+	// The copy has the same best litmus test as sub, so the hash carries over.
+	// Remembering the hash saves recomputing it if the subject is ever saved again.
+	l, err := rs.BestLitmus()
+	if err != nil {
+		return rs, err
+	}
+	l.Hash = hash
+	return rs, nil
+}
+
+// copyFile copies the file at slashpath src into segment seg of the directory for rname.
+// It returns the slashpath of the copy relative to the store root.
+func (s *Store) copyFile(rname, seg, src string) (string, error) {
+	rel := path.Join(rname, seg, path.Base(src))
+	dst := filepath.Join(s.DirRoot, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dst), 0744); err != nil {
+		return "", err
+	}
+	r, err := os.Open(filepath.FromSlash(src))
+	if err != nil {
+		return "", err
+	}
+	w, err := os.Create(dst)
+	if err != nil {
+		_ = r.Close()
+		return "", err
+	}
+	_, err = iohelp.CopyClose(w, r)
+	return rel, err
+}
+
+// rebase makes the store-relative paths in sub point into the store.
+func (s *Store) rebase(sub subject.Subject) subject.Subject {
+	if sub.Source.HasPath() {
+		sub.Source.Path = s.abs(sub.Source.Path)
+	}
+	if sub.Fuzz != nil {
+		f := *sub.Fuzz
+		if f.Litmus.HasPath() {
+			f.Litmus.Path = s.abs(f.Litmus.Path)
+		}
+		if f.Trace != "" {
+			f.Trace = s.abs(f.Trace)
+		}
+		sub.Fuzz = &f
+	}
+	if sub.Transform != nil && sub.Transform.Litmus.HasPath() {
+		tf := *sub.Transform
+		tf.Litmus.Path = s.abs(tf.Litmus.Path)
+		sub.Transform = &tf
+	}
+	return sub
+}
+
+func (s *Store) abs(rel string) string {
+	return filepath.ToSlash(filepath.Join(s.DirRoot, filepath.FromSlash(rel)))
+}
+
+func (s *Store) loadIndex() (corpus.Corpus, error) {
+	f, err := os.Open(s.FileIndex())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return corpus.Corpus{}, nil
+		}
+		return nil, err
+	}
+	var idx corpus.Corpus
+	derr := json.NewDecoder(f).Decode(&idx)
+	cerr := f.Close()
+	if idx == nil {
+		idx = corpus.Corpus{}
+	}
+	return idx, errhelp.FirstError(derr, cerr)
+}
+
+// writeIndex writes idx to the store's index file, going through a temporary file so that a reader never sees a
+// partially written index.
+func (s *Store) writeIndex(idx corpus.Corpus) error {
+	if err := os.MkdirAll(s.DirRoot, 0744); err != nil {
+		return err
+	}
+	tmp := s.FileIndex() + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	eerr := json.NewEncoder(f).Encode(idx)
+	cerr := f.Close()
+	if err := errhelp.FirstError(eerr, cerr); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.FileIndex())
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package regressor_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/stage/regressor"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStore_Load_empty tests that loading a store that doesn't exist yet gives an empty corpus.
+func TestStore_Load_empty(t *testing.T) {
+	t.Parallel()
+
+	s := regressor.NewStore(filepath.Join(t.TempDir(), "regress"))
+	c, err := s.Load()
+	require.NoError(t, err, "loading nonexistent store")
+	assert.Empty(t, c, "nonexistent store should be empty")
+}
+
+// TestStore_Add tests adding subjects to a store, and then loading them back.
+func TestStore_Add(t *testing.T) {
+	t.Parallel()
+
+	in := t.TempDir()
+	s := regressor.NewStore(filepath.Join(t.TempDir(), "regress"))
+
+	// foo and bar differ only in name and layout, so the store should keep only one of them.
+	c := corpus.Corpus{
+		"foo": *subject.NewOrPanic(litmus.NewOrPanic(writeLitmus(t, in, "foo.litmus", "C foo\n{ x = 0; }"))),
+		"bar": *subject.NewOrPanic(litmus.NewOrPanic(writeLitmus(t, in, "bar.litmus", "C bar { x = 0; }"))),
+		"baz": *subject.NewOrPanic(
+			litmus.NewOrPanic(writeLitmus(t, in, "baz.litmus", "C baz { y = 0; }")),
+			subject.WithFuzz(&subject.Fuzz{
+				Litmus: *litmus.NewOrPanic(writeLitmus(t, in, "baz_1.litmus", "C baz_1 { y = 1; }")),
+				Trace:  writeLitmus(t, in, "baz_1.trace", "trace"),
+			}),
+		),
+	}
+
+	added, err := s.Add(c)
+	require.NoError(t, err, "adding to store")
+	assert.Len(t, added, 2, "should have added only the non-duplicate subjects")
+
+	added, err = s.Add(c)
+	require.NoError(t, err, "re-adding to store")
+	assert.Empty(t, added, "shouldn't re-add subjects already in store")
+
+	rc, err := s.Load()
+	require.NoError(t, err, "loading store")
+	require.Len(t, rc, 2, "wrong number of subjects in store")
+
+	var fuzzed int
+	for n, rs := range rc {
+		assert.True(t, strings.HasPrefix(n, regressor.NamePrefix), "regression subject name should have prefix")
+		assert.Empty(t, rs.Compilations, "regression subjects shouldn't carry compilations")
+
+		l, err := rs.BestLitmus()
+		require.NoError(t, err, "getting best litmus of", n)
+		assert.NotEmpty(t, l.Hash, "best litmus should be hashed")
+		assert.FileExists(t, l.Filepath(), "best litmus should be in store")
+		assert.True(t, strings.HasPrefix(l.Filepath(), s.DirRoot), "best litmus should be in store")
+		if rs.Fuzz == nil {
+			continue
+		}
+		fuzzed++
+		bs, err := os.ReadFile(l.Filepath())
+		require.NoError(t, err, "reading fuzzed litmus")
+		assert.Equal(t, "C baz_1 { y = 1; }", string(bs), "fuzzed litmus should be copied verbatim")
+		assert.FileExists(t, filepath.FromSlash(rs.Fuzz.Trace), "trace should be in store")
+		assert.FileExists(t, rs.Source.Filepath(), "source should be in store")
+	}
+	assert.Equal(t, 1, fuzzed, "one regression subject should be fuzzed")
+}
+
+// TestStore_Add_transformed tests that the store keeps the transformed litmus test of a transformed subject, under the
+// hash of that test.
+func TestStore_Add_transformed(t *testing.T) {
+	t.Parallel()
+
+	in := t.TempDir()
+	s := regressor.NewStore(filepath.Join(t.TempDir(), "regress"))
+
+	sub := subject.NewOrPanic(
+		litmus.NewOrPanic(writeLitmus(t, in, "foo.litmus", "C foo { x = 0; }")),
+		subject.WithFuzz(&subject.Fuzz{Litmus: *litmus.NewOrPanic(writeLitmus(t, in, "foo_1.litmus", "C foo_1 { x = 1; }"))}),
+	)
+	tpath := writeLitmus(t, in, "foo_1_t.litmus", "C foo_1_t { x = 2; }")
+	require.NoError(t, sub.AddTransform(subject.Transform{Litmus: *litmus.NewOrPanic(tpath)}), "adding transform")
+	want, err := litmus.CanonicalHashOfFile(tpath)
+	require.NoError(t, err, "hashing transformed litmus")
+
+	added, err := s.Add(corpus.Corpus{"foo": *sub})
+	require.NoError(t, err, "adding to store")
+	require.Len(t, added, 1, "should have added the subject")
+
+	rc, err := s.Load()
+	require.NoError(t, err, "loading store")
+	rs := rc[added[0]]
+	require.NotNil(t, rs.Transform, "stored subject should keep its transform")
+	l, err := rs.BestLitmus()
+	require.NoError(t, err, "getting best litmus")
+	assert.Same(t, &rs.Transform.Litmus, l, "best litmus should be the transformed test")
+	assert.True(t, strings.HasPrefix(l.Filepath(), s.DirRoot), "transformed litmus should be in store")
+	bs, err := os.ReadFile(l.Filepath())
+	require.NoError(t, err, "reading transformed litmus")
+	assert.Equal(t, "C foo_1_t { x = 2; }", string(bs), "transformed litmus should be copied verbatim")
+	assert.Equal(t, want, l.Hash, "stored hash should be that of the transformed litmus")
+}
+
+// writeLitmus writes content to a file called name in dir, returning its slashpath.
+func writeLitmus(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644), "writing test file", name)
+	return filepath.ToSlash(path)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package transformer
+
+import (
+	"errors"
+
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/subject/corpus/builder"
+)
+
+// ErrObserverNil occurs when we try to pass a nil observer as an option.
+var ErrObserverNil = errors.New("observer nil")
+
+// Option is the type of options to pass to New.
+type Option func(*Transformer) error
+
+// Options bundles up each option in os into a single option.
+func Options(os ...Option) Option {
+	return func(t *Transformer) error {
+		for _, op := range os {
+			if err := op(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ObserveWith adds each observer in obs to the transformer's observer list.
+func ObserveWith(obs ...builder.Observer) Option {
+	return func(t *Transformer) error {
+		for _, ob := range obs {
+			if ob == nil {
+				return ErrObserverNil
+			}
+		}
+		t.obs = append(t.obs, obs...)
+		return nil
+	}
+}
+
+// PopulateStatsWith makes the transformer populate statistics for each transformed litmus test using d.
+func PopulateStatsWith(d litmus.StatDumper) Option {
+	return func(t *Transformer) error {
+		t.stats = d
+		return nil
+	}
+}
+
+// WithWorkerCount sets the number of subjects the transformer transforms in parallel to nworkers.
+func WithWorkerCount(nworkers int) Option {
+	return func(t *Transformer) error {
+		t.nworkers = nworkers
+		return nil
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package transformer contains the part of the tester framework that runs external transformers over a corpus.
+//
+// A transformer is a configured command (for instance, a C-level source rewriter or a normaliser) that reads one
+// litmus test and writes another.  The transformer stage runs it over each subject's best litmus test, and records the
+// output on the subject much as the fuzzer does; later stages then use the transformed test.
+package transformer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/1set/gut/ystring"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/service"
+	"github.com/c4-project/c4t/internal/model/service/transformer"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/corpus/builder"
+)
+
+var (
+	// ErrRunnerNil occurs when a transformer is constructed without a service runner.
+	ErrRunnerNil = errors.New("service runner nil")
+
+	// ErrConfigNil occurs when a transformer is constructed without a configuration.
+	ErrConfigNil = errors.New("transformer config nil")
+
+	// ErrRootBlank occurs when a transformer is constructed without an output directory.
+	ErrRootBlank = errors.New("transformer output directory blank")
+)
+
+// Transformer holds the main configuration for the transformer part of the tester framework.
+type Transformer struct {
+	// runner runs the transformer command.
+	runner service.Runner
+
+	// config is the transformer configuration.
+	config *transformer.Config
+
+	// root is the directory into which the transformer writes its outputs.
+	root string
+
+	// nworkers is the number of subjects to transform in parallel.
+	nworkers int
+
+	// stats, if present, populates statistics for each transformed litmus test.
+	stats litmus.StatDumper
+
+	// obs track the transformer's progress across a corpus.
+	obs []builder.Observer
+}
+
+// New constructs a new Transformer given service runner r, configuration cfg, output directory root, and options os.
+func New(r service.Runner, cfg *transformer.Config, root string, os ...Option) (*Transformer, error) {
+	if r == nil {
+		return nil, ErrRunnerNil
+	}
+	if cfg == nil {
+		return nil, ErrConfigNil
+	}
+	if err := cfg.Check(); err != nil {
+		return nil, err
+	}
+	if ystring.IsBlank(root) {
+		return nil, ErrRootBlank
+	}
+	t := Transformer{runner: r, config: cfg, root: root, nworkers: 10}
+	if err := Options(os...)(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Stage gets the stage for this Transformer.
+func (*Transformer) Stage() stage.Stage {
+	return stage.Transform
+}
+
+// Close does nothing.
+func (*Transformer) Close() error {
+	return nil
+}
+
+// Run transforms every subject in p that hasn't yet been transformed.
+func (t *Transformer) Run(ctx context.Context, p *plan.Plan) (*plan.Plan, error) {
+	if err := checkPlan(p); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(t.root, 0744); err != nil {
+		return nil, err
+	}
+
+	outp := *p
+	var err error
+	outp.Corpus, err = t.transformCorpus(ctx, p.Corpus)
+	return &outp, err
+}
+
+func checkPlan(p *plan.Plan) error {
+	if p == nil {
+		return plan.ErrNil
+	}
+	if err := p.Check(); err != nil {
+		return err
+	}
+	return p.Metadata.RequireStage(stage.Plan)
+}
+
+func (t *Transformer) transformCorpus(ctx context.Context, c corpus.Corpus) (corpus.Corpus, error) {
+	ts := transformable(c)
+	if len(ts) == 0 {
+		return c, nil
+	}
+
+	cfg := builder.Config{
+		Init:      c,
+		Observers: t.obs,
+		Manifest: builder.Manifest{
+			Name:  "transform",
+			NReqs: len(ts),
+		},
+	}
+	return builder.ParBuild(ctx, t.nworkers, ts, cfg, func(ctx context.Context, s subject.Named, rq chan<- builder.Request) error {
+		tr, err := t.transformSubject(ctx, s)
+		if err != nil {
+			return err
+		}
+		return builder.TransformRequest(s.Name, *tr).SendTo(ctx, rq)
+	})
+}
+
+// transformable gets the sub-corpus of c containing subjects with litmus tests that haven't yet been transformed.
+//
+// Subjects can already be transformed if, for instance, they came from a previous cycle's corpus.  We don't transform
+// subjects that an earlier stage failed to process.
+func transformable(c corpus.Corpus) corpus.Corpus {
+	ts := make(corpus.Corpus, len(c))
+	for n, s := range c {
+		if s.Transform != nil || s.HasFailed() {
+			continue
+		}
+		if _, err := s.BestLitmus(); err == nil {
+			ts[n] = s
+		}
+	}
+	return ts
+}
+
+func (t *Transformer) transformSubject(ctx context.Context, s subject.Named) (*subject.Transform, error) {
+	in, err := s.BestLitmus()
+	if err != nil {
+		return nil, err
+	}
+	out := filepath.Join(t.root, s.Name+".litmus")
+
+	ri, err := t.config.Job(in.Filepath(), out)
+	if err != nil {
+		return nil, err
+	}
+	stime := time.Now()
+	if err := t.runner.Run(ctx, ri); err != nil {
+		return nil, fmt.Errorf("when transforming %s: %w", s.Name, err)
+	}
+	dur := time.Since(stime)
+
+	l, err := t.probe(ctx, in, out)
+	if err != nil {
+		return nil, fmt.Errorf("when probing transformed %s: %w", s.Name, err)
+	}
+	return &subject.Transform{Duration: dur, Litmus: *l}, nil
+}
+
+// probe builds the litmus record for the transformer output at out, given the input test in.
+// We assume that the transformer preserves the architecture of its input.
+func (t *Transformer) probe(ctx context.Context, in *litmus.Litmus, out string) (*litmus.Litmus, error) {
+	opts := []litmus.Option{litmus.ReadHashFromFile()}
+	if !in.Arch.IsEmpty() {
+		opts = append(opts, litmus.WithArch(in.Arch))
+	}
+	if t.stats != nil {
+		opts = append(opts, litmus.PopulateStatsFrom(ctx, t.stats))
+	}
+	return litmus.New(filepath.ToSlash(out), opts...)
+}
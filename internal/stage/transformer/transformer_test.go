@@ -0,0 +1,162 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package transformer_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/model/service"
+	transformer2 "github.com/c4-project/c4t/internal/model/service/transformer"
+	"github.com/c4-project/c4t/internal/plan"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/stage/transformer"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNew_errors tests the error result of New in various situations.
+func TestNew_errors(t *testing.T) {
+	t.Parallel()
+
+	cfg := &transformer2.Config{Run: service.RunInfo{Cmd: "normalise"}}
+
+	cases := map[string]struct {
+		r    service.Runner
+		cfg  *transformer2.Config
+		root string
+		os   []transformer.Option
+		err  error
+	}{
+		"ok":         {r: upcaser{}, cfg: cfg, root: "transform"},
+		"nil-runner": {cfg: cfg, root: "transform", err: transformer.ErrRunnerNil},
+		"nil-config": {r: upcaser{}, root: "transform", err: transformer.ErrConfigNil},
+		"bad-config": {r: upcaser{}, cfg: &transformer2.Config{}, root: "transform", err: transformer2.ErrCmdBlank},
+		"blank-root": {r: upcaser{}, cfg: cfg, err: transformer.ErrRootBlank},
+		"nil-observer": {
+			r:    upcaser{},
+			cfg:  cfg,
+			root: "transform",
+			os:   []transformer.Option{transformer.ObserveWith(nil)},
+			err:  transformer.ErrObserverNil,
+		},
+	}
+
+	for name, c := range cases {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := transformer.New(c.r, c.cfg, c.root, c.os...)
+			testhelp.ExpectErrorIs(t, err, c.err, "constructing transformer")
+		})
+	}
+}
+
+// TestTransformer_Run tests running the transformer on a small plan with a fake transformer command.
+func TestTransformer_Run(t *testing.T) {
+	t.Parallel()
+
+	in := t.TempDir()
+	root := filepath.Join(t.TempDir(), "transform")
+
+	cfg := transformer2.Config{Run: service.RunInfo{Cmd: "upcase", Args: []string{"${in}", "${out}"}}}
+	tf, err := transformer.New(upcaser{}, &cfg, root)
+	require.NoError(t, err, "constructing transformer")
+
+	done := subject.Transform{Litmus: *litmus.NewOrPanic("done.t.litmus")}
+
+	p := plan.Mock()
+	p.Metadata.ConfirmStage(stage.Plan, timing.Span{})
+	p.Corpus = corpus.Corpus{
+		"src": *subject.NewOrPanic(litmus.NewOrPanic(writeFile(t, in, "src.litmus", "C src"), litmus.WithArch(id.ArchC))),
+		"fuzz": *subject.NewOrPanic(
+			litmus.NewOrPanic(writeFile(t, in, "fuzz.litmus", "C fuzz")),
+			subject.WithFuzz(&subject.Fuzz{
+				Litmus: *litmus.NewOrPanic(writeFile(t, in, "fuzz_1.litmus", "C fuzz_1"), litmus.WithArch(id.ArchC)),
+			}),
+		),
+		"done": {Source: *litmus.NewOrPanic("done.litmus"), Transform: &done},
+	}
+
+	p2, err := tf.Run(context.Background(), p)
+	require.NoError(t, err, "running transformer")
+	require.Len(t, p2.Corpus, 3, "transformer shouldn't change corpus size")
+
+	for name, want := range map[string]string{"src": "C SRC", "fuzz": "C FUZZ_1"} {
+		s := p2.Corpus[name]
+		require.NotNil(t, s.Transform, "subject should be transformed:", name)
+		l, err := s.BestLitmus()
+		require.NoError(t, err, "getting best litmus of", name)
+		assert.Equal(t, &s.Transform.Litmus, l, "transformed litmus should be best:", name)
+		assert.True(t, l.Arch.Equal(id.ArchC), "transformed litmus should keep arch:", name)
+		assert.NotEmpty(t, l.Hash, "transformed litmus should be hashed:", name)
+
+		bs, err := os.ReadFile(l.Filepath())
+		require.NoError(t, err, "reading transformed litmus of", name)
+		assert.Equal(t, want, string(bs), "wrong transformer output for", name)
+	}
+	assert.Equal(t, &done, p2.Corpus["done"].Transform, "already-transformed subject shouldn't be re-transformed")
+	assert.Nil(t, p.Corpus["src"].Transform, "input plan modified")
+}
+
+// TestTransformer_Run_error tests that the transformer propagates a failing command.
+func TestTransformer_Run_error(t *testing.T) {
+	t.Parallel()
+
+	cfg := transformer2.Config{Run: service.RunInfo{Cmd: "fail"}}
+	tf, err := transformer.New(failer{}, &cfg, t.TempDir())
+	require.NoError(t, err, "constructing transformer")
+
+	p := plan.Mock()
+	p.Metadata.ConfirmStage(stage.Plan, timing.Span{})
+	_, err = tf.Run(context.Background(), p)
+	testhelp.ExpectErrorIs(t, err, errFail, "running failing transformer")
+}
+
+// writeFile writes content to a file called name in dir, returning its slashpath.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644), "writing test file", name)
+	return filepath.ToSlash(path)
+}
+
+// upcaser is a fake service runner that treats its first two arguments as input and output files, and copies the
+// upper-cased input to the output.
+type upcaser struct{}
+
+func (u upcaser) WithStdout(io.Writer) service.Runner    { return u }
+func (u upcaser) WithStderr(io.Writer) service.Runner    { return u }
+func (u upcaser) WithGrace(time.Duration) service.Runner { return u }
+
+func (upcaser) Run(_ context.Context, r service.RunInfo) error {
+	bs, err := os.ReadFile(filepath.FromSlash(r.Args[0]))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.Args[1], []byte(strings.ToUpper(string(bs))), 0644)
+}
+
+var errFail = errors.New("transformer failed")
+
+// failer is a fake service runner that always fails.
+type failer struct{ upcaser }
+
+func (failer) Run(context.Context, service.RunInfo) error {
+	return errFail
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat
+
+import (
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// Compiler holds statistics for a compiler configuration on a particular machine.
+//
+// As the director names compilers by full ID, each configuration of optimisation level, machine profile, and extra flags
+// gets its own statset.
+type Compiler struct {
+	// Cycles counts the number of cycles in which this compiler was tested.
+	Cycles uint64 `json:"cycles"`
+
+	// StatusTotals contains totals of each status across every run of this compiler.
+	StatusTotals map[status.Status]uint64 `json:"status_totals,omitempty"`
+}
+
+// AddAnalysis adds the information from compiler analysis a to this statset.
+func (c *Compiler) AddAnalysis(a analysis.Compiler) {
+	if c.StatusTotals == nil {
+		c.StatusTotals = make(map[status.Status]uint64)
+	}
+	c.Cycles++
+	for s, n := range a.Counts {
+		c.StatusTotals[s] += uint64(n)
+	}
+}
+
+// Yield summarises this statset as a perturber yield, counting bad statuses as hits.
+func (c Compiler) Yield() perturber.Yield {
+	y := perturber.Yield{Cycles: c.Cycles}
+	for s, n := range c.StatusTotals {
+		y.Subjects += n
+		if s.IsBad() {
+			y.Hits += n
+		}
+	}
+	return y
+}
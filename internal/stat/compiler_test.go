@@ -0,0 +1,40 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat_test
+
+import (
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stat"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// ExampleSet_CompilerYield is a runnable example for Set.CompilerYield.
+func ExampleSet_CompilerYield() {
+	mid, cid := id.FromString("foo"), id.FromString("gcc.o3.mmarch=native")
+
+	var m stat.Machine
+	m.AddAnalysis(analysis.Analysis{Compilers: map[id.ID]analysis.Compiler{
+		cid: {Counts: map[status.Status]int{status.Ok: 8, status.Flagged: 1, status.CompileFail: 1}},
+	}})
+	m.AddAnalysis(analysis.Analysis{Compilers: map[id.ID]analysis.Compiler{
+		cid: {Counts: map[status.Status]int{status.Ok: 9, status.RunTimeout: 1}},
+	}})
+	// Configurations with extra flags count towards the yield of their base configuration.
+	m.AddAnalysis(analysis.Analysis{Compilers: map[id.ID]analysis.Compiler{
+		cid.Join(id.FromString("f0123abcd")): {Counts: map[status.Status]int{status.Ok: 4, status.Flagged: 1}},
+	}})
+	s := stat.Set{Machines: map[id.ID]stat.Machine{mid: m}}
+
+	fmt.Printf("%+v\n", s.CompilerYield(mid, cid))
+	fmt.Printf("%+v\n", s.CompilerYield(mid, id.FromString("clang")))
+
+	// Output:
+	// {Cycles:3 Subjects:25 Hits:4}
+	// {Cycles:0 Subjects:0 Hits:0}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/c4-project/c4t/internal/director"
+)
+
+// HealthState is the enumeration of states of the director's per-machine circuit breaker.
+type HealthState uint8
+
+const (
+	// Healthy means that the machine is running cycles normally.
+	Healthy HealthState = iota
+	// BackingOff means that the machine's recent cycles have errored, and the director is waiting before retrying.
+	BackingOff
+	// Parked means that the machine has errored enough times in a row to trip the circuit breaker, and the director
+	// is waiting for it to pass a health probe.
+	Parked
+
+	// LastHealthState refers to the last health state.
+	LastHealthState = Parked
+)
+
+//go:generate stringer -type HealthState
+
+// HealthStateFromString tries to convert a string into a HealthState.
+func HealthStateFromString(s string) (HealthState, error) {
+	for i := Healthy; i <= LastHealthState; i++ {
+		if strings.EqualFold(s, i.String()) {
+			return i, nil
+		}
+	}
+	return Healthy, fmt.Errorf("unknown HealthState: %q", s)
+}
+
+// MarshalJSON marshals a health state to JSON using its string form.
+func (i HealthState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON unmarshals a health state from JSON using its string form.
+func (i *HealthState) UnmarshalJSON(bytes []byte) error {
+	var (
+		is  string
+		err error
+	)
+	if err = json.Unmarshal(bytes, &is); err != nil {
+		return err
+	}
+	*i, err = HealthStateFromString(is)
+	return err
+}
+
+// Health records the health of a machine, as tracked by the director's circuit breaker.
+type Health struct {
+	// State is the current state of the machine.
+	State HealthState `json:"state"`
+
+	// Since is the time at which the machine entered its current state.
+	Since time.Time `json:"since,omitempty"`
+
+	// ConsecutiveErrors counts the errored cycles since the machine last finished a cycle or passed a health probe.
+	ConsecutiveErrors uint64 `json:"consecutive_errors,omitempty"`
+
+	// LastProbeError contains the error from the machine's last failed health probe, if it is parked.
+	LastProbeError string `json:"last_probe_error,omitempty"`
+}
+
+// AddCycle adds the information from cycle message c to this health record.
+//
+// A machine's instances share its health, and one may finish a cycle it started before another parked the machine;
+// so, a finished cycle doesn't take the machine out of the Parked state.
+func (h *Health) AddCycle(c director.CycleMessage) {
+	switch c.Kind {
+	case director.CycleFinish:
+		h.ConsecutiveErrors = 0
+		if h.State != Parked {
+			h.setState(Healthy)
+		}
+	case director.CycleError:
+		h.ConsecutiveErrors++
+	}
+}
+
+// AddInstance adds the information from instance message m to this health record.
+func (h *Health) AddInstance(m director.InstanceMessage) {
+	switch m.Kind {
+	case director.KindInstanceBackoff:
+		// Another instance may have parked the machine since this one started backing off.
+		if h.State != Parked {
+			h.setState(BackingOff)
+		}
+	case director.KindInstanceParked:
+		h.setState(Parked)
+	case director.KindInstanceProbeFailed:
+		if m.Err != nil {
+			h.LastProbeError = m.Err.Error()
+		}
+	case director.KindInstanceResumed:
+		h.ConsecutiveErrors = 0
+		h.LastProbeError = ""
+		h.setState(Healthy)
+	}
+}
+
+func (h *Health) setState(s HealthState) {
+	if h.State == s && !h.Since.IsZero() {
+		return
+	}
+	h.State = s
+	h.Since = time.Now()
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c4-project/c4t/internal/director"
+	"github.com/c4-project/c4t/internal/stat"
+)
+
+// ExampleMachine_AddInstance is a runnable example for Machine.AddInstance.
+func ExampleMachine_AddInstance() {
+	var m stat.Machine
+	show := func() {
+		fmt.Printf("%s after %d error(s); %d park(s), %d resume(s)\n",
+			m.Health.State, m.Health.ConsecutiveErrors, m.Session.Parks, m.Session.Resumes)
+	}
+
+	m.AddCycle(director.CycleErrorMessage(director.Cycle{}, errors.New("oops")))
+	m.AddInstance(director.InstanceBackoffMessage(1, 5*time.Second))
+	show()
+	m.AddCycle(director.CycleErrorMessage(director.Cycle{}, errors.New("oops")))
+	m.AddInstance(director.InstanceParkedMessage(2))
+	show()
+	// Another instance on the machine finishes a cycle it started before the machine parked.
+	m.AddCycle(director.CycleFinishMessage(director.Cycle{}))
+	show()
+	m.AddInstance(director.InstanceProbeFailedMessage(errors.New("no route to host")))
+	fmt.Println(m.Health.LastProbeError)
+	m.AddInstance(director.InstanceResumedMessage())
+	show()
+
+	// Output:
+	// BackingOff after 1 error(s); 0 park(s), 0 resume(s)
+	// Parked after 2 error(s); 1 park(s), 0 resume(s)
+	// Parked after 0 error(s); 1 park(s), 0 resume(s)
+	// no route to host
+	// Healthy after 0 error(s); 1 park(s), 1 resume(s)
+}
+
+// TestHealthState_MarshalJSON_roundTrip tests that health states survive a JSON round trip.
+func TestHealthState_MarshalJSON_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	for i := stat.Healthy; i <= stat.LastHealthState; i++ {
+		i := i
+		t.Run(i.String(), func(t *testing.T) {
+			t.Parallel()
+
+			bs, err := json.Marshal(i)
+			require.NoError(t, err, "marshalling health state")
+			var got stat.HealthState
+			require.NoError(t, json.Unmarshal(bs, &got), "unmarshalling health state")
+			assert.Equal(t, i, got, "health state didn't round-trip")
+		})
+	}
+}
@@ -7,7 +7,9 @@ package stat
 
 import (
 	"github.com/c4-project/c4t/internal/director"
+	"github.com/c4-project/c4t/internal/id"
 	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/subject/confirm"
 	"github.com/c4-project/c4t/internal/subject/status"
 )
 
@@ -16,6 +18,9 @@ type Machine struct {
 	// LastCycle is the last announced cycle in this session.
 	LastCycle director.Cycle `json:"last_cycle,omitempty"`
 
+	// Health records the current health of this machine, as tracked by the director's circuit breaker.
+	Health Health `json:"health,omitempty"`
+
 	// Session contains statistics for this session.
 	Session MachineSpan `json:"session,omitempty"`
 	// Total contains statistics across all sessions.
@@ -25,6 +30,7 @@ type Machine struct {
 // ResetForSession removes from this statset any statistics that no longer apply across session boundaries.
 func (m *Machine) ResetForSession() {
 	m.LastCycle = director.Cycle{}
+	m.Health = Health{}
 	m.Session.Reset()
 }
 
@@ -33,10 +39,18 @@ func (m *Machine) AddCycle(c director.CycleMessage) {
 	if c.Kind == director.CycleStart {
 		m.LastCycle = c.Cycle
 	}
+	m.Health.AddCycle(c)
 	m.Session.AddCycle(c)
 	m.Total.AddCycle(c)
 }
 
+// AddInstance adds the information from instance message i to this machine statset.
+func (m *Machine) AddInstance(i director.InstanceMessage) {
+	m.Health.AddInstance(i)
+	m.Session.AddInstance(i)
+	m.Total.AddInstance(i)
+}
+
 // AddAnalysis adds the information from analysis a to this machine statset.
 func (m *Machine) AddAnalysis(a analysis.Analysis) {
 	m.Session.AddAnalysis(a)
@@ -49,19 +63,39 @@ type MachineSpan struct {
 	FinishedCycles uint64 `json:"finished_cycles"`
 	// ErroredCycles counts the number of cycles that resulted in an error.
 	ErroredCycles uint64 `json:"errored_cycles"`
+	// Parks counts the number of times the director parked this machine after consecutive errored cycles.
+	Parks uint64 `json:"parks,omitempty"`
+	// Resumes counts the number of times this machine passed a health probe after being parked.
+	Resumes uint64 `json:"resumes,omitempty"`
 	// Mutation contains totals for mutation testing since this span started.
 	Mutation Mutation `json:"mutation,omitempty"`
 
 	// SessionStatusTotals contains status totals since this span started.
 	// It may be empty if this machine has not yet been active this span.
 	StatusTotals map[status.Status]uint64 `json:"status_totals,omitempty"`
+
+	// ConfirmationTotals contains totals of confirmation verdicts since this span started.
+	// It may be empty if this machine has not yet confirmed any runs this span.
+	ConfirmationTotals map[confirm.Verdict]uint64 `json:"confirmation_totals,omitempty"`
+
+	// Compilers contains statistics for each compiler, by (full) compiler ID, since this span started.
+	Compilers map[id.ID]Compiler `json:"compilers,omitempty"`
+
+	// Sources contains statistics for the descendants of each source subject, by source slashpath, since this span
+	// started.
+	Sources map[string]Source `json:"sources,omitempty"`
 }
 
 // Reset resets a machine span.
 func (m *MachineSpan) Reset() {
 	m.FinishedCycles = 0
 	m.ErroredCycles = 0
+	m.Parks = 0
+	m.Resumes = 0
 	m.StatusTotals = make(map[status.Status]uint64)
+	m.ConfirmationTotals = make(map[confirm.Verdict]uint64)
+	m.Compilers = make(map[id.ID]Compiler)
+	m.Sources = make(map[string]Source)
 	m.Mutation.Reset()
 }
 
@@ -75,9 +109,22 @@ func (m *MachineSpan) AddCycle(c director.CycleMessage) {
 	}
 }
 
+// AddInstance adds the information from instance message i to this machine span.
+func (m *MachineSpan) AddInstance(i director.InstanceMessage) {
+	switch i.Kind {
+	case director.KindInstanceParked:
+		m.Parks++
+	case director.KindInstanceResumed:
+		m.Resumes++
+	}
+}
+
 // AddAnalysis adds the information from analysis a to this machine statset.
 func (m *MachineSpan) AddAnalysis(a analysis.Analysis) {
 	m.addStatusTotals(a)
+	m.addConfirmationTotals(a)
+	m.addCompilers(a)
+	m.addSources(a)
 	m.addMutation(a)
 }
 
@@ -91,6 +138,26 @@ func (m *MachineSpan) addStatusTotals(a analysis.Analysis) {
 	}
 }
 
+func (m *MachineSpan) addConfirmationTotals(a analysis.Analysis) {
+	if m.ConfirmationTotals == nil {
+		m.ConfirmationTotals = make(map[confirm.Verdict]uint64)
+	}
+	for v, count := range a.Confirmations {
+		m.ConfirmationTotals[v] += uint64(count)
+	}
+}
+
+func (m *MachineSpan) addCompilers(a analysis.Analysis) {
+	if m.Compilers == nil {
+		m.Compilers = make(map[id.ID]Compiler)
+	}
+	for cid, ca := range a.Compilers {
+		c := m.Compilers[cid]
+		c.AddAnalysis(ca)
+		m.Compilers[cid] = c
+	}
+}
+
 func (m *MachineSpan) addMutation(a analysis.Analysis) {
 	if len(a.Mutation) == 0 {
 		return
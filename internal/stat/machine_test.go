@@ -0,0 +1,30 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat_test
+
+import (
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stat"
+	"github.com/c4-project/c4t/internal/subject/confirm"
+)
+
+// ExampleMachineSpan_AddAnalysis is a runnable example for MachineSpan.AddAnalysis.
+func ExampleMachineSpan_AddAnalysis() {
+	var m stat.MachineSpan
+	m.AddAnalysis(analysis.Analysis{Confirmations: map[confirm.Verdict]int{confirm.Reproducible: 2, confirm.NotReproduced: 1}})
+	m.AddAnalysis(analysis.Analysis{Confirmations: map[confirm.Verdict]int{confirm.Reproducible: 1, confirm.Intermittent: 4}})
+
+	for v := confirm.Unchecked + 1; v <= confirm.Last; v++ {
+		fmt.Println(v, m.ConfirmationTotals[v])
+	}
+
+	// Output:
+	// NotReproduced 1
+	// Intermittent 4
+	// Reproducible 3
+}
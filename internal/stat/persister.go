@@ -10,6 +10,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/stage/perturber"
 
 	"github.com/c4-project/c4t/internal/subject/corpus/builder"
 
@@ -27,6 +31,8 @@ import (
 
 // Persister is a forward handler that maintains and persists a statistics set on disk.
 type Persister struct {
+	// mu guards set, as the director may read yields from it while it is being updated.
+	mu sync.Mutex
 	// set is the statistics set being persisted.
 	set Set
 	// f is the target file (we need a file to be able to truncate properly).
@@ -62,58 +68,90 @@ func (s *Persister) Close() error {
 
 // OnMachines feeds the information from m into the stats set.
 func (s *Persister) OnMachines(m machine.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.set.OnMachines(m)
 	s.flush()
 }
 
 // OnPrepare feeds the information from m into the stats set.
 func (s *Persister) OnPrepare(m director.PrepareMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.set.OnPrepare(m)
 	s.flush()
 }
 
 // OnCycle feeds the information from c into the stats set.
 func (s *Persister) OnCycle(c director.CycleMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.set.OnCycle(c)
 	s.flush()
 }
 
 // OnCycleInstance feeds the information from c and m into the stats set.
 func (s *Persister) OnCycleInstance(c director.Cycle, m director.InstanceMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.set.OnCycleInstance(c, m)
 	s.flush()
 }
 
 // OnCycleAnalysis feeds the information from a into the stats set.
 func (s *Persister) OnCycleAnalysis(a director.CycleAnalysis) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.set.OnCycleAnalysis(a)
 	s.flush()
 }
 
 // OnCycleBuild feeds the information from c and m into the stats set.
 func (s *Persister) OnCycleBuild(c director.Cycle, m builder.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.set.OnCycleBuild(c, m)
 	s.flush()
 }
 
 // OnCycleCompiler feeds the information from c and m into the stats set.
 func (s *Persister) OnCycleCompiler(c director.Cycle, m compiler.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.set.OnCycleCompiler(c, m)
 	s.flush()
 }
 
 // OnCycleCopy feeds the information from c and m into the stats set.
 func (s *Persister) OnCycleCopy(c director.Cycle, m copier.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.set.OnCycleCopy(c, m)
 	s.flush()
 }
 
 // OnCycleSave feeds the information from c and m into the stats set.
 func (s *Persister) OnCycleSave(c director.Cycle, m saver.ArchiveMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.set.OnCycleSave(c, m)
 	s.flush()
 }
 
+// CompilerYield gets the yield of the compiler with ID cid on the machine with ID mid.
+func (s *Persister) CompilerYield(mid, cid id.ID) perturber.Yield {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.CompilerYield(mid, cid)
+}
+
+// SourceYield gets the yield of the descendants of the source subject with slashpath path on the machine with ID mid.
+func (s *Persister) SourceYield(mid id.ID, path string) perturber.Yield {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set.SourceYield(mid, path)
+}
+
 func (s *Persister) tryReadStats() error {
 	if empty, err := iohelp.IsFileEmpty(s.f); err != nil {
 		return fmt.Errorf("while checking file for existing stats: %w", err)
@@ -46,3 +46,14 @@ func UseTotals(on bool) Option {
 		p.ctx.UseTotals = on
 	}
 }
+
+// ShowSources enables showing the n most and least productive source subjects on each machine.
+// If n is not positive, source showing is disabled.
+func ShowSources(n int) Option {
+	return func(p *Printer) {
+		if n < 0 {
+			n = 0
+		}
+		p.ctx.SourceCount = n
+	}
+}
@@ -37,6 +37,7 @@ func TestPrinter_OnAnalysis_regress(t *testing.T) {
 			"me": pretty.Options(pretty.ShowMutants(stat.FilterEscapedMutants), pretty.UseTotals(false)),
 			"mh": pretty.Options(pretty.ShowMutants(stat.FilterHitMutants), pretty.UseTotals(false)),
 			"mk": pretty.Options(pretty.ShowMutants(stat.FilterKilledMutants), pretty.UseTotals(false)),
+			"so": pretty.Options(pretty.ShowSources(3), pretty.UseTotals(false)),
 		}
 
 		var gotw bytes.Buffer
@@ -29,6 +29,7 @@ type context struct {
 	Stats        *stat.Set
 	MutantFilter stat.MutantFilter
 	UseTotals    bool
+	SourceCount  int
 }
 
 // Span gets from m the span required by the context.
@@ -38,3 +39,16 @@ func (c context) Span(m stat.Machine) stat.MachineSpan {
 	}
 	return m.Session
 }
+
+// sourceRanks holds the most and least productive sources on a machine.
+type sourceRanks struct {
+	Most  []stat.NamedSource
+	Least []stat.NamedSource
+}
+
+// SourceRanks gets from m the most and least productive sources, up to the number required by the context, in the
+// span required by the context.
+func (c context) SourceRanks(m stat.Machine) sourceRanks {
+	s := c.Span(m)
+	return sourceRanks{Most: s.MostProductiveSources(c.SourceCount), Least: s.LeastProductiveSources(c.SourceCount)}
+}
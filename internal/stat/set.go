@@ -20,6 +20,7 @@ import (
 	"github.com/c4-project/c4t/internal/machine"
 	"github.com/c4-project/c4t/internal/model/service/compiler"
 	"github.com/c4-project/c4t/internal/stage/analyser/saver"
+	"github.com/c4-project/c4t/internal/stage/perturber"
 	"github.com/c4-project/c4t/internal/subject/corpus/builder"
 )
 
@@ -67,8 +68,13 @@ func (s *Set) OnCycleCompiler(director.Cycle, compiler.Message) {}
 // OnCycleCopy does nothing, for now.
 func (s *Set) OnCycleCopy(director.Cycle, copier.Message) {}
 
-// OnCycleInstance does nothing, for now.
-func (s *Set) OnCycleInstance(director.Cycle, director.InstanceMessage) {}
+// OnCycleInstance incorporates instance information from m, which arrived during cycle c, into the statistics set.
+func (s *Set) OnCycleInstance(c director.Cycle, m director.InstanceMessage) {
+	s.liftCycle(c, func(mach *Machine) {
+		mach.AddInstance(m)
+	})
+	s.EventCount++
+}
 
 // OnCycleSave does nothing, for now.
 func (s *Set) OnCycleSave(director.Cycle, saver.ArchiveMessage) {}
@@ -89,6 +95,25 @@ func (s *Set) liftCycle(c director.Cycle, f func(m *Machine)) {
 	s.Machines[c.MachineID] = mach
 }
 
+// CompilerYield gets the yield, across all sessions, of the compiler with ID cid on the machine with ID mid.
+//
+// The yield includes that of any compiler whose ID has cid as a prefix, such as configurations of cid with extra flags.
+func (s *Set) CompilerYield(mid, cid id.ID) perturber.Yield {
+	var y perturber.Yield
+	for ccid, c := range s.Machines[mid].Total.Compilers {
+		if ccid.HasPrefix(cid) {
+			y = y.Add(c.Yield())
+		}
+	}
+	return y
+}
+
+// SourceYield gets the yield, across all sessions, of the descendants of the source subject with slashpath path on the
+// machine with ID mid.
+func (s *Set) SourceYield(mid id.ID, path string) perturber.Yield {
+	return s.Machines[mid].Total.Sources[path].Yield()
+}
+
 // ResetForSession resets statistics in s that are session-specific.
 func (s *Set) ResetForSession() {
 	s.SessionStartTime = time.Now()
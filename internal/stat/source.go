@@ -0,0 +1,127 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat
+
+import (
+	"sort"
+
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stage/perturber"
+)
+
+// Source holds statistics for the descendants of a source subject (that is, an input litmus test) on a particular
+// machine.
+type Source struct {
+	// Cycles counts the number of cycles in which descendants of this source were tested.
+	Cycles uint64 `json:"cycles"`
+
+	// Subjects counts the number of descendants of this source that were tested.
+	Subjects uint64 `json:"subjects"`
+
+	// Hits counts the number of those descendants that were flagged or failed on at least one compiler.
+	Hits uint64 `json:"hits"`
+}
+
+// Yield summarises this statset as a perturber yield.
+func (s Source) Yield() perturber.Yield {
+	return perturber.Yield{Cycles: s.Cycles, Subjects: s.Subjects, Hits: s.Hits}
+}
+
+// NamedSource wraps a Source statset with the path of its source subject.
+type NamedSource struct {
+	// Path is the slashpath of the source subject's litmus test.
+	Path string
+
+	Source
+}
+
+// sourceAnalysis tallies, for each source path, the subjects in an analysis descended from that source.
+// Subjects whose source has no path don't count towards any source.
+func sourceAnalysis(a analysis.Analysis) map[string]Source {
+	// A subject appears once for each status it had on some compiler, so we work out its descent first.
+	type descent struct {
+		path string
+		hit  bool
+	}
+	descs := map[string]descent{}
+	for st, c := range a.ByStatus {
+		for n, s := range c {
+			d, ok := descs[n]
+			if !ok {
+				d.path = s.Source.Path
+			}
+			d.hit = d.hit || st.IsBad()
+			descs[n] = d
+		}
+	}
+
+	srcs := map[string]Source{}
+	for _, d := range descs {
+		if d.path == "" {
+			continue
+		}
+		s := srcs[d.path]
+		s.Cycles = 1
+		s.Subjects++
+		if d.hit {
+			s.Hits++
+		}
+		srcs[d.path] = s
+	}
+	return srcs
+}
+
+func (m *MachineSpan) addSources(a analysis.Analysis) {
+	sa := sourceAnalysis(a)
+	if len(sa) == 0 {
+		return
+	}
+	if m.Sources == nil {
+		m.Sources = make(map[string]Source, len(sa))
+	}
+	for path, s := range sa {
+		t := m.Sources[path]
+		t.Cycles += s.Cycles
+		t.Subjects += s.Subjects
+		t.Hits += s.Hits
+		m.Sources[path] = t
+	}
+}
+
+// MostProductiveSources gets up to n of the sources in this span, ordered from highest to lowest hit rate.
+//
+// Sources with equal hit rates are ordered by decreasing number of tested descendants, then by path.
+func (m *MachineSpan) MostProductiveSources(n int) []NamedSource {
+	return m.rankSources(n, func(r1, r2 float64) bool { return r1 > r2 })
+}
+
+// LeastProductiveSources gets up to n of the sources in this span, ordered from lowest to highest hit rate.
+//
+// Sources with equal hit rates are ordered by decreasing number of tested descendants, then by path.
+func (m *MachineSpan) LeastProductiveSources(n int) []NamedSource {
+	return m.rankSources(n, func(r1, r2 float64) bool { return r1 < r2 })
+}
+
+func (m *MachineSpan) rankSources(n int, before func(r1, r2 float64) bool) []NamedSource {
+	ss := make([]NamedSource, 0, len(m.Sources))
+	for path, s := range m.Sources {
+		ss = append(ss, NamedSource{Path: path, Source: s})
+	}
+	sort.Slice(ss, func(i, j int) bool {
+		ri, rj := ss[i].Yield().Rate(), ss[j].Yield().Rate()
+		if ri != rj {
+			return before(ri, rj)
+		}
+		if ss[i].Subjects != ss[j].Subjects {
+			return ss[i].Subjects > ss[j].Subjects
+		}
+		return ss[i].Path < ss[j].Path
+	})
+	if 0 <= n && n < len(ss) {
+		ss = ss[:n]
+	}
+	return ss
+}
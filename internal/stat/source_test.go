@@ -0,0 +1,53 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package stat_test
+
+import (
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/plan/analysis"
+	"github.com/c4-project/c4t/internal/stat"
+	"github.com/c4-project/c4t/internal/subject"
+	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// ExampleSet_SourceYield is a runnable example for Set.SourceYield.
+func ExampleSet_SourceYield() {
+	mid := id.FromString("foo")
+	fuzzed := func(src string) subject.Subject {
+		return *subject.NewOrPanic(litmus.NewOrPanic(src), subject.WithFuzz(&subject.Fuzz{}))
+	}
+
+	var m stat.Machine
+	m.AddAnalysis(analysis.Analysis{ByStatus: map[status.Status]corpus.Corpus{
+		// mp_1 is ok on one compiler and flagged on another, but only counts once.
+		status.Ok:      {"mp_1": fuzzed("mp.litmus"), "mp_2": fuzzed("mp.litmus"), "sb_1": fuzzed("sb.litmus")},
+		status.Flagged: {"mp_1": fuzzed("mp.litmus")},
+	}})
+	m.AddAnalysis(analysis.Analysis{ByStatus: map[status.Status]corpus.Corpus{
+		status.Ok:          {"sb_1": fuzzed("sb.litmus")},
+		status.CompileFail: {"mp_1": fuzzed("mp.litmus")},
+	}})
+	s := stat.Set{Machines: map[id.ID]stat.Machine{mid: m}}
+
+	fmt.Printf("%+v\n", s.SourceYield(mid, "mp.litmus"))
+	fmt.Printf("%+v\n", s.SourceYield(mid, "sb.litmus"))
+	fmt.Printf("%+v\n", s.SourceYield(mid, "lb.litmus"))
+
+	for _, src := range m.Total.MostProductiveSources(-1) {
+		fmt.Println(src.Path, src.Hits)
+	}
+
+	// Output:
+	// {Cycles:2 Subjects:3 Hits:2}
+	// {Cycles:2 Subjects:2 Hits:0}
+	// {Cycles:0 Subjects:0 Hits:0}
+	// mp.litmus 2
+	// sb.litmus 0
+}
@@ -6,12 +6,17 @@
 package compilation
 
 import (
+	"errors"
 	"fmt"
 	"path"
+	"strings"
 
 	"github.com/c4-project/c4t/internal/id"
 )
 
+// ErrBadName occurs when we try to parse a compilation name that isn't of the form 'subject@compiler'.
+var ErrBadName = errors.New("compilation name should be of the form subject@compiler")
+
 // Name describes the unique name of a particular instance of the batch compiler.
 type Name struct {
 	// SubjectName is the name of the subject.
@@ -30,3 +35,16 @@ func (n Name) String() string {
 func (n Name) Path() string {
 	return path.Join(append(n.CompilerID.Tags(), n.SubjectName)...)
 }
+
+// ParseName parses a name of the form produced by String.
+func ParseName(s string) (Name, error) {
+	i := strings.LastIndex(s, "@")
+	if i <= 0 || i == len(s)-1 {
+		return Name{}, fmt.Errorf("%w: %q", ErrBadName, s)
+	}
+	cid, err := id.TryFromString(s[i+1:])
+	if err != nil {
+		return Name{}, err
+	}
+	return Name{SubjectName: s[:i], CompilerID: cid}, nil
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package compilation_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/c4-project/c4t/internal/subject/compilation"
+)
+
+// ExampleParseName is a runnable example for ParseName.
+func ExampleParseName() {
+	n, err := compilation.ParseName("foo_1@gcc.x86.o3")
+	fmt.Println(n.SubjectName, n.CompilerID, err)
+
+	for _, s := range []string{"foo", "@gcc", "foo@"} {
+		_, err := compilation.ParseName(s)
+		fmt.Println(errors.Is(err, compilation.ErrBadName))
+	}
+
+	// Output:
+	// foo_1 gcc.x86.o3 <nil>
+	// true
+	// true
+	// true
+}
@@ -6,7 +6,9 @@
 package compilation
 
 import (
+	"github.com/c4-project/c4t/internal/subject/confirm"
 	"github.com/c4-project/c4t/internal/subject/obs"
+	"github.com/c4-project/c4t/internal/subject/status"
 )
 
 // RunResult represents information about a single run of a subject.
@@ -15,4 +17,43 @@ type RunResult struct {
 
 	// Obs is this run's processed observation, if any.
 	Obs *obs.Obs `toml:"obs,omitempty" json:"obs,omitempty"`
+
+	// Repeats contains the status of each repeat of this run, if the runner repeated it more than once.
+	// In that case, Obs is the merger of every repeat's observation, and Status is the most severe repeat status (with
+	// flagged and forbidden statuses outranking failures and timeouts).
+	Repeats []status.Status `toml:"repeats,omitempty" json:"repeats,omitempty"`
+
+	// Confirmation records whether this run's status recurred when the run was re-run to confirm it.
+	Confirmation confirm.Verdict `toml:"confirmation,omitzero" json:"confirmation,omitempty"`
+}
+
+// NRepeatsWith counts the number of repeats of this run that had status s.
+func (r RunResult) NRepeatsWith(s status.Status) int {
+	n := 0
+	for _, rs := range r.Repeats {
+		if rs == s {
+			n++
+		}
+	}
+	return n
+}
+
+// CheckAgainst reclassifies this run as forbidden if it completed and its observation contains a state that the
+// reference observation ref doesn't allow.
+//
+// If ref reports undefined behaviour, the reference model allows any state, and so no reclassification happens.
+// If the observation can't be compared against ref (for instance, because the two name their variables differently),
+// CheckAgainst leaves the run alone and returns an error.
+func (r *RunResult) CheckAgainst(ref obs.Obs) error {
+	if r.Obs == nil || ref.Flags.Has(obs.Undef) || !(r.Status == status.Ok || r.Status == status.Flagged) {
+		return nil
+	}
+	xs, err := r.Obs.Forbidden(ref)
+	if err != nil {
+		return err
+	}
+	if len(xs) != 0 {
+		r.Status = status.Forbidden
+	}
+	return nil
 }
@@ -7,6 +7,7 @@ package compilation_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -21,6 +22,20 @@ import (
 	"github.com/c4-project/c4t/internal/subject/obs"
 )
 
+// ExampleRunResult_NRepeatsWith is a runnable example for RunResult.NRepeatsWith.
+func ExampleRunResult_NRepeatsWith() {
+	r := compilation.RunResult{
+		Result:  compilation.Result{Status: status.RunTimeout},
+		Repeats: []status.Status{status.Ok, status.Flagged, status.RunTimeout, status.Flagged},
+	}
+	fmt.Println(r.NRepeatsWith(status.Flagged), "of", len(r.Repeats))
+	fmt.Println(r.NRepeatsWith(status.RunFail), "of", len(r.Repeats))
+
+	// Output:
+	// 2 of 4
+	// 0 of 4
+}
+
 // TestRun_JSONDecode tests the decoding of a test run from various JSON examples.
 func TestRun_JSONDecode(t *testing.T) {
 	t.Parallel()
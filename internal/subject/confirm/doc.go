@@ -0,0 +1,7 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+// Package confirm contains the verdicts used to record whether bad run results reproduce when re-run.
+package confirm
@@ -0,0 +1,38 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package confirm
+
+import "encoding/json"
+
+// MarshalText marshals a Verdict to text via its string representation.
+func (i Verdict) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText unmarshals a Verdict from text via its string representation.
+func (i *Verdict) UnmarshalText(text []byte) error {
+	var err error
+	*i, err = FromString(string(text))
+	return err
+}
+
+// MarshalJSON marshals a Verdict to JSON via its string representation.
+func (i Verdict) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON unmarshals a Verdict from JSON via its string representation.
+func (i *Verdict) UnmarshalJSON(bytes []byte) error {
+	var (
+		sstr string
+		err  error
+	)
+	if err = json.Unmarshal(bytes, &sstr); err != nil {
+		return err
+	}
+	*i, err = FromString(sstr)
+	return err
+}
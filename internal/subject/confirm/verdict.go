@@ -0,0 +1,74 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package confirm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// Verdict is the type of confirmation verdicts.
+type Verdict uint8
+
+const (
+	// Unchecked represents a result that has not been through confirmation.
+	Unchecked Verdict = iota
+	// NotReproduced represents a result that did not recur in any confirmation repeat.
+	NotReproduced
+	// Intermittent represents a result that recurred in some, but not all, confirmation repeats.
+	Intermittent
+	// Reproducible represents a result that recurred in every confirmation repeat.
+	Reproducible
+
+	// Last is the last valid verdict.
+	Last = Reproducible
+)
+
+//go:generate stringer -type=Verdict
+
+// ErrBad occurs when FromString encounters an unknown verdict string.
+var ErrBad = errors.New("bad confirmation verdict")
+
+// FromString tries to resolve s to a verdict.
+func FromString(s string) (Verdict, error) {
+	for i := Unchecked; i <= Last; i++ {
+		if strings.EqualFold(s, i.String()) {
+			return i, nil
+		}
+	}
+	return Unchecked, fmt.Errorf("%w: %q", ErrBad, s)
+}
+
+// FromCounts gets the verdict for a result that recurred nrepro times over nrepeats confirmation repeats.
+//
+// If nrepeats is not positive, no confirmation took place, and the verdict is Unchecked.
+func FromCounts(nrepro, nrepeats int) Verdict {
+	switch {
+	case nrepeats <= 0:
+		return Unchecked
+	case nrepro <= 0:
+		return NotReproduced
+	case nrepro < nrepeats:
+		return Intermittent
+	default:
+		return Reproducible
+	}
+}
+
+// Confirmable is true if, and only if, a run with status s should be re-run to confirm it.
+//
+// Only bad run outcomes are confirmable; compile outcomes don't recur at run time, and so can't be confirmed by
+// re-running.  Unknown statuses (for instance, of runs whose results weren't collected) aren't confirmable either.
+func Confirmable(s status.Status) bool {
+	// Unknown has no flags, and so would match any flag set.
+	if s == status.Unknown {
+		return false
+	}
+	return (status.FlagFlagged | status.FlagForbidden | status.FlagRunFail | status.FlagRunTimeout).MatchesStatus(s)
+}
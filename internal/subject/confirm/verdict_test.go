@@ -0,0 +1,69 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package confirm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/c4-project/c4t/internal/helper/testhelp"
+
+	"github.com/c4-project/c4t/internal/subject/confirm"
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// ExampleFromCounts is a runnable example for FromCounts.
+func ExampleFromCounts() {
+	fmt.Println(confirm.FromCounts(0, 0))
+	fmt.Println(confirm.FromCounts(0, 5))
+	fmt.Println(confirm.FromCounts(3, 5))
+	fmt.Println(confirm.FromCounts(5, 5))
+
+	// Output:
+	// Unchecked
+	// NotReproduced
+	// Intermittent
+	// Reproducible
+}
+
+// ExampleConfirmable is a runnable example for Confirmable.
+func ExampleConfirmable() {
+	for s := status.Unknown; s <= status.Last; s++ {
+		fmt.Println(s, confirm.Confirmable(s))
+	}
+
+	// Output:
+	// Unknown false
+	// Ok false
+	// Filtered false
+	// Flagged true
+	// CompileFail false
+	// CompileTimeout false
+	// RunFail true
+	// RunTimeout true
+	// Forbidden true
+}
+
+// TestVerdict_MarshalJSON_roundTrip tests that marshalling and unmarshalling verdicts to JSON is the identity.
+func TestVerdict_MarshalJSON_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	for i := confirm.Unchecked; i <= confirm.Last; i++ {
+		i := i
+		t.Run(i.String(), func(t *testing.T) {
+			t.Parallel()
+			testhelp.TestJSONRoundTrip(t, i, "round-trip Verdict")
+		})
+	}
+}
+
+// TestFromString_bad tests that FromString rejects unknown verdicts.
+func TestFromString_bad(t *testing.T) {
+	t.Parallel()
+
+	_, err := confirm.FromString("mostly")
+	testhelp.ExpectErrorIs(t, err, confirm.ErrBad, "resolving bad verdict")
+}
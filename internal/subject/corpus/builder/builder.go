@@ -12,6 +12,8 @@ import (
 	"fmt"
 
 	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/confirm"
+	"github.com/c4-project/c4t/internal/subject/obs"
 
 	"github.com/c4-project/c4t/internal/model/recipe"
 
@@ -44,6 +46,9 @@ type Builder struct {
 	// obs is the observer set for the builder.
 	obs []Observer
 
+	// hashes maps the canonical hash of each subject in the corpus to its name, if the builder is deduplicating.
+	hashes map[string]string
+
 	// reqCh is the receiving channel for requests.
 	reqCh <-chan Request
 
@@ -66,9 +71,41 @@ func New(cfg Config) (*Builder, error) {
 		reqCh:  reqCh,
 		SendCh: reqCh,
 	}
+	if cfg.Dedup {
+		b.hashes = corpusHashes(b.c)
+	}
 	return &b, nil
 }
 
+// corpusHashes maps the canonical hash of each subject in c to its name.
+// Where there are already duplicates in c, the first name in order wins.
+func corpusHashes(c corpus.Corpus) map[string]string {
+	hashes := make(map[string]string, len(c))
+	for _, n := range c.Names() {
+		s := c[n]
+		if h := subjectHash(&s); h != "" {
+			if _, ok := hashes[h]; !ok {
+				hashes[h] = n
+			}
+		}
+	}
+	return hashes
+}
+
+// subjectHash gets the canonical hash of the best litmus test of s, if it has one; else, it returns "".
+//
+// Failed subjects have no hash, as their best litmus test isn't the one the failed stage was trying to produce.
+func subjectHash(s *subject.Subject) string {
+	if s.HasFailed() {
+		return ""
+	}
+	l, err := s.BestLitmus()
+	if err != nil {
+		return ""
+	}
+	return l.Hash
+}
+
 func initCorpus(init corpus.Corpus, nreqs int) corpus.Corpus {
 	if init == nil {
 		// The requests are probably all going to be add requests, so it's a good starter capacity.
@@ -102,23 +139,58 @@ func (b *Builder) runSingle(i int, ctx context.Context) error {
 }
 
 func (b *Builder) runRequest(i int, r Request) error {
+	if of, ok := b.duplicate(r); ok {
+		OnBuild(DuplicateMessage(i, r, of), b.obs...)
+		return nil
+	}
 	OnBuild(StepMessage(i, r), b.obs...)
 	switch {
 	case r.Add != nil:
 		return b.add(r.Name, subject.Subject(*r.Add))
 	case r.Compile != nil:
 		return b.addCompile(r.Name, r.Compile.CompilerID, r.Compile.Result)
+	case r.Confirm != nil:
+		return b.addConfirm(r.Name, r.Confirm.CompilerID, r.Confirm.Verdict)
+	case r.Failure != nil:
+		return b.addFailure(r.Name, subject.Failure(*r.Failure))
+	case r.Oracle != nil:
+		return b.addOracle(r.Name, r.Oracle.Obs)
 	case r.Recipe != nil:
 		return b.addRecipe(r.Name, r.Recipe.Arch, r.Recipe.Recipe)
 	case r.Run != nil:
 		return b.addRun(r.Name, r.Run.CompilerID, r.Run.Result)
+	case r.Transform != nil:
+		return b.addTransform(r.Name, subject.Transform(*r.Transform))
 	default:
 		return fmt.Errorf("%w: %v", ErrBadBuilderRequest, r)
 	}
 }
 
+// duplicate checks whether r is an add request for a subject whose hash is already in the corpus.
+// If so, it returns the name of the existing subject.
+func (b *Builder) duplicate(r Request) (string, bool) {
+	if b.hashes == nil || r.Add == nil {
+		return "", false
+	}
+	s := subject.Subject(*r.Add)
+	h := subjectHash(&s)
+	if h == "" {
+		return "", false
+	}
+	of, ok := b.hashes[h]
+	return of, ok
+}
+
 func (b *Builder) add(name string, s subject.Subject) error {
-	return b.c.Add(subject.Named{Name: name, Subject: s})
+	if err := b.c.Add(subject.Named{Name: name, Subject: s}); err != nil {
+		return err
+	}
+	if b.hashes != nil {
+		if h := subjectHash(&s); h != "" {
+			b.hashes[h] = name
+		}
+	}
+	return nil
 }
 
 func (b *Builder) addCompile(name string, cid id.ID, res compilation.CompileResult) error {
@@ -127,6 +199,25 @@ func (b *Builder) addCompile(name string, cid id.ID, res compilation.CompileResu
 	})
 }
 
+func (b *Builder) addConfirm(name string, cid id.ID, v confirm.Verdict) error {
+	return b.rmwSubject(name, func(s *subject.Subject) error {
+		return s.AddConfirmation(cid, v)
+	})
+}
+
+func (b *Builder) addFailure(name string, f subject.Failure) error {
+	return b.rmwSubject(name, func(s *subject.Subject) error {
+		s.AddFailure(f)
+		return nil
+	})
+}
+
+func (b *Builder) addOracle(name string, o obs.Obs) error {
+	return b.rmwSubject(name, func(s *subject.Subject) error {
+		return s.AddOracle(o)
+	})
+}
+
 func (b *Builder) addRecipe(name string, arch id.ID, r recipe.Recipe) error {
 	return b.rmwSubject(name, func(s *subject.Subject) error {
 		return s.AddRecipe(arch, r)
@@ -139,6 +230,12 @@ func (b *Builder) addRun(name string, cid id.ID, r compilation.RunResult) error
 	})
 }
 
+func (b *Builder) addTransform(name string, t subject.Transform) error {
+	return b.rmwSubject(name, func(s *subject.Subject) error {
+		return s.AddTransform(t)
+	})
+}
+
 // rmwSubject hoists a mutating function over subjects so that it operates on the corpus subject name.
 // This hoisting function is necessary because we can't directly mutate the subject in-place.
 func (b *Builder) rmwSubject(name string, f func(*subject.Subject) error) error {
@@ -156,7 +253,13 @@ func (b *Builder) rmwSubject(name string, f func(*subject.Subject) error) error
 // ParBuild runs f in a parallelised manner across the subjects in src.
 // It uses the responses from f in a Builder, and returns the resulting corpus.
 // Note that src may be different from cfg.Init; this is useful when building a new corpus from scratch.
+//
+// If src is empty but cfg.Init isn't (for instance, because every subject in cfg.Init has failed an earlier stage),
+// there is nothing to build, and ParBuild returns a copy of cfg.Init.
 func ParBuild(ctx context.Context, nworkers int, src corpus.Corpus, cfg Config, f func(context.Context, subject.Named, chan<- Request) error) (corpus.Corpus, error) {
+	if len(src) == 0 && len(cfg.Init) != 0 {
+		return cfg.Init.Copy(), nil
+	}
 	b, err := New(cfg)
 	if err != nil {
 		return nil, err
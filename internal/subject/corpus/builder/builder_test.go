@@ -7,12 +7,14 @@ package builder_test
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/c4-project/c4t/internal/observing"
 
 	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/plan/stage"
 
 	"github.com/stretchr/testify/mock"
 
@@ -24,6 +26,8 @@ import (
 
 	"github.com/c4-project/c4t/internal/subject"
 	"github.com/c4-project/c4t/internal/subject/corpus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -164,3 +168,136 @@ func exerciseSendTo(t *testing.T, eg *errgroup.Group, ectx context.Context, ch c
 	})
 	return eg.Wait()
 }
+
+// TestBuilder_Run_dedup tests that a deduplicating builder drops subjects with duplicate hashes, and reports them.
+func TestBuilder_Run_dedup(t *testing.T) {
+	t.Parallel()
+
+	hashed := func(path, hash string) subject.Subject {
+		l := litmus.NewOrPanic(path)
+		l.Hash = hash
+		return *subject.NewOrPanic(l)
+	}
+	init := corpus.Corpus{"old": hashed("old.litmus", "aaaa")}
+	adds := []subject.Named{
+		{Name: "foo", Subject: hashed("foo.litmus", "bbbb")},
+		{Name: "bar", Subject: hashed("bar.litmus", "bbbb")},
+		{Name: "baz", Subject: hashed("baz.litmus", "aaaa")},
+		{Name: "nohash1", Subject: hashed("nohash1.litmus", "")},
+		{Name: "nohash2", Subject: hashed("nohash2.litmus", "")},
+	}
+
+	var obs recordingObserver
+	b, err := builder.New(builder.Config{
+		Init:      init,
+		Observers: []builder.Observer{&obs},
+		Manifest:  builder.Manifest{Name: "dedup", NReqs: len(adds)},
+		Dedup:     true,
+	})
+	require.NoError(t, err, "constructing builder")
+
+	var got corpus.Corpus
+	eg, ectx := errgroup.WithContext(context.Background())
+	eg.Go(func() error {
+		var err error
+		got, err = b.Run(ectx)
+		return err
+	})
+	eg.Go(func() error {
+		for i := range adds {
+			if err := builder.AddRequest(&adds[i]).SendTo(ectx, b.SendCh); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, eg.Wait(), "running builder")
+
+	assert.ElementsMatch(t, []string{"old", "foo", "nohash1", "nohash2"}, got.Names(), "wrong corpus")
+	dups := map[string]string{}
+	for _, m := range obs.msgs {
+		if m.Duplicate != "" {
+			dups[m.Request.Name] = m.Duplicate
+		}
+	}
+	assert.Equal(t, map[string]string{"bar": "foo", "baz": "old"}, dups, "wrong duplicates reported")
+}
+
+// TestBuilder_Run_failure tests that the builder records failures, keeping failed subjects out of deduplication.
+func TestBuilder_Run_failure(t *testing.T) {
+	t.Parallel()
+
+	l := litmus.NewOrPanic("foo.litmus")
+	l.Hash = "aaaa"
+	init := corpus.Corpus{"foo": *subject.NewOrPanic(l)}
+
+	fail := subject.NewFailure(stage.Fuzz, errors.New("fuzzer crashed"), true)
+	failed := subject.Named{Name: "foo_1", Subject: *subject.NewOrPanic(l)}
+	failed.Failure = &fail
+
+	rqs := []builder.Request{
+		builder.AddRequest(&failed),
+		builder.FailureRequest("foo", subject.NewFailure(stage.Lift, errors.New("lifter crashed"), false)),
+	}
+
+	b, err := builder.New(builder.Config{
+		Init:     init,
+		Manifest: builder.Manifest{Name: "failure", NReqs: len(rqs)},
+		Dedup:    true,
+	})
+	require.NoError(t, err, "constructing builder")
+
+	var got corpus.Corpus
+	eg, ectx := errgroup.WithContext(context.Background())
+	eg.Go(func() error {
+		var err error
+		got, err = b.Run(ectx)
+		return err
+	})
+	eg.Go(func() error {
+		for _, r := range rqs {
+			if err := r.SendTo(ectx, b.SendCh); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, eg.Wait(), "running builder")
+
+	require.ElementsMatch(t, []string{"foo", "foo_1"}, got.Names(), "failed subject shouldn't count as a duplicate")
+	if f := got["foo"].Failure; assert.NotNil(t, f, "failure not recorded") {
+		assert.Equal(t, stage.Lift, f.Stage, "wrong failure stage")
+	}
+	assert.Equal(t, &fail, got["foo_1"].Failure, "added failure not kept")
+}
+
+// TestParBuild_noViable tests that ParBuild passes through an initial corpus in which every subject has failed.
+func TestParBuild_noViable(t *testing.T) {
+	t.Parallel()
+
+	s := subject.NewOrPanic(litmus.NewOrPanic("foo.litmus"))
+	s.AddFailure(subject.NewFailure(stage.Fuzz, errors.New("fuzzer crashed"), true))
+	init := corpus.Corpus{"foo": *s}
+
+	cfg := builder.Config{Init: init, Manifest: builder.Manifest{Name: "lift", NReqs: 0}}
+	got, err := builder.ParBuild(context.Background(), 1, init.Viable(), cfg,
+		func(context.Context, subject.Named, chan<- builder.Request) error {
+			t.Error("nothing should be built")
+			return nil
+		})
+	require.NoError(t, err, "building from no viable subjects")
+	assert.Equal(t, init, got, "failed subjects should pass through")
+
+	_, err = builder.ParBuild(context.Background(), 1, corpus.Corpus{}, builder.Config{Manifest: cfg.Manifest},
+		func(context.Context, subject.Named, chan<- builder.Request) error { return nil })
+	testhelp.ExpectErrorIs(t, err, builder.ErrBadTarget, "building from nothing")
+}
+
+// recordingObserver records every build message it receives.
+type recordingObserver struct {
+	msgs []builder.Message
+}
+
+func (r *recordingObserver) OnBuild(m builder.Message) {
+	r.msgs = append(r.msgs, m)
+}
@@ -19,4 +19,8 @@ type Config struct {
 
 	// Obs is the list of observers to notify as the builder performs various tasks.
 	Observers []Observer
+
+	// Dedup, if true, makes the builder drop any added subject whose best litmus test has the same canonical hash as
+	// that of a subject already in the corpus.
+	Dedup bool
 }
@@ -26,6 +26,10 @@ type Message struct {
 
 	// Request carries a builder request, if we're on a build-step.
 	Request *Request `json:"request,omitempty"`
+
+	// Duplicate carries, if we're on a build-step that the builder dropped as a duplicate, the name of the subject
+	// already in the corpus that the request duplicated.
+	Duplicate string `json:"duplicate,omitempty"`
 }
 
 // OnBuild sends an OnBuild message to each observer in obs.
@@ -45,6 +49,14 @@ func StepMessage(i int, r Request) Message {
 	return Message{Batch: observing.NewBatchStep(i), Request: &r}
 }
 
+// DuplicateMessage creates a build-step message for step i and add request r, which the builder dropped as a duplicate
+// of the subject named of.
+func DuplicateMessage(i int, r Request, of string) Message {
+	m := StepMessage(i, r)
+	m.Duplicate = of
+	return m
+}
+
 // EndMessage creates a build-end message.
 func EndMessage() Message {
 	return Message{Batch: observing.NewBatchEnd()}
@@ -9,6 +9,8 @@ import (
 	"context"
 
 	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/confirm"
+	"github.com/c4-project/c4t/internal/subject/obs"
 
 	"github.com/c4-project/c4t/internal/model/recipe"
 
@@ -28,11 +30,23 @@ type Request struct {
 	// Compile is populated if this request is a Compile.
 	Compile *Compile `json:"compile,omitempty"`
 
+	// Confirm is populated if this request is a Confirm.
+	Confirm *Confirm `json:"confirm,omitempty"`
+
+	// Failure is populated if this request is a Failure.
+	Failure *Failure `json:"failure,omitempty"`
+
+	// Oracle is populated if this request is an Oracle.
+	Oracle *Oracle `json:"oracle,omitempty"`
+
 	// Recipe is populated if this request is a Recipe.
 	Recipe *Recipe `json:"recipe,omitempty"`
 
 	// Run is populated if this request is a Run.
 	Run *Run `json:"run,omitempty"`
+
+	// Transform is populated if this request is a Transform.
+	Transform *Transform `json:"transform,omitempty"`
 }
 
 // SendTo tries to send this request down ch while checking to see if ctx has been cancelled.
@@ -68,6 +82,40 @@ func CompileRequest(name compilation.Name, r compilation.CompileResult) Request
 	return Request{Name: name.SubjectName, Compile: &Compile{CompilerID: name.CompilerID, Result: r}}
 }
 
+// Confirm is a request to record a confirmation verdict against a run of the named subject.
+type Confirm struct {
+	// CompilerID is the ID of the compiler whose run was confirmed.
+	CompilerID id.ID `json:"compiler_id,omitempty"`
+
+	// Verdict is the confirmation verdict.
+	Verdict confirm.Verdict `json:"verdict,omitempty"`
+}
+
+// ConfirmRequest constructs an add-confirmation request for the compilation with name name and verdict v.
+func ConfirmRequest(name compilation.Name, v confirm.Verdict) Request {
+	return Request{Name: name.SubjectName, Confirm: &Confirm{CompilerID: name.CompilerID, Verdict: v}}
+}
+
+// Failure is a request to record that a stage failed to process the named subject.
+type Failure subject.Failure
+
+// FailureRequest constructs an add-failure request for the subject with name sname and failure f.
+func FailureRequest(sname string, f subject.Failure) Request {
+	fl := Failure(f)
+	return Request{Name: sname, Failure: &fl}
+}
+
+// Oracle is a request to check the named subject against the given reference observation.
+type Oracle struct {
+	// Obs is the observation produced by the reference model.
+	Obs obs.Obs `json:"obs,omitempty"`
+}
+
+// OracleRequest constructs an add-oracle request for the subject with name sname and reference observation o.
+func OracleRequest(sname string, o obs.Obs) Request {
+	return Request{Name: sname, Oracle: &Oracle{Obs: o}}
+}
+
 // Recipe is a request to add the given recipe to the named subject, under the named architecture.
 type Recipe struct {
 	// Arch is the ID of the architecture for which this lifting is occurring.
@@ -95,3 +143,12 @@ type Run struct {
 func RunRequest(name compilation.Name, r compilation.RunResult) Request {
 	return Request{Name: name.SubjectName, Run: &Run{CompilerID: name.CompilerID, Result: r}}
 }
+
+// Transform is a request to add the given transformer output to the named subject.
+type Transform subject.Transform
+
+// TransformRequest constructs an add-transform request for the subject with name sname and transformer output t.
+func TransformRequest(sname string, t subject.Transform) Request {
+	tr := Transform(t)
+	return Request{Name: sname, Transform: &tr}
+}
@@ -12,6 +12,7 @@ import (
 	"sort"
 
 	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/status"
 
 	"github.com/c4-project/c4t/internal/helper/stringhelp"
 
@@ -57,6 +58,17 @@ func (c Corpus) FilterToNames(names ...string) Corpus {
 	return c2
 }
 
+// Viable filters c to contain only subjects that no stage has failed to process.
+func (c Corpus) Viable() Corpus {
+	c2 := make(Corpus, len(c))
+	for n, s := range c {
+		if !s.HasFailed() {
+			c2[n] = s
+		}
+	}
+	return c2
+}
+
 // Add tries to add s to the corpus.
 // It fails if the corpus already has a subject with the given name.
 func (c Corpus) Add(s subject.Named) error {
@@ -97,3 +109,23 @@ func (c Corpus) EraseCompilations() {
 		c[n] = s
 	}
 }
+
+// Reproduces checks whether the run of the compilation named n in c has status want.
+//
+// Runs made by re-invoking a plan don't go through the oracle, so Reproduces checks the run against its subject's
+// reference observation, if there is one; if it can't, the run doesn't reproduce.
+func (c Corpus) Reproduces(n compilation.Name, want status.Status) bool {
+	s, ok := c[n.SubjectName]
+	if !ok {
+		return false
+	}
+	r, err := s.RunResult(n.CompilerID)
+	if err != nil {
+		return false
+	}
+	rr := *r
+	if s.Oracle != nil && rr.CheckAgainst(*s.Oracle) != nil {
+		return false
+	}
+	return rr.Status == want
+}
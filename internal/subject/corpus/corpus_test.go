@@ -12,7 +12,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/c4-project/c4t/internal/id"
 	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/plan/stage"
+	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/obs"
+	"github.com/c4-project/c4t/internal/subject/status"
 
 	"github.com/c4-project/c4t/internal/subject/corpus"
 
@@ -61,6 +66,53 @@ func ExampleCorpus_FilterToNames() {
 	// foo is in c2
 }
 
+// ExampleCorpus_Viable is a runnable example for Corpus.Viable.
+func ExampleCorpus_Viable() {
+	c := corpus.Mock()
+	bar := c["bar"]
+	bar.AddFailure(subject.NewFailure(stage.Lift, nil, true))
+	c["bar"] = bar
+
+	for _, n := range c.Viable().Names() {
+		fmt.Println(n, "is viable")
+	}
+
+	// Output:
+	// barbaz is viable
+	// baz is viable
+	// foo is viable
+}
+
+// ExampleCorpus_Reproduces is a runnable example for Corpus.Reproduces.
+func ExampleCorpus_Reproduces() {
+	run := func(x string) compilation.RunResult {
+		return compilation.RunResult{
+			Result: compilation.Result{Status: status.Ok},
+			Obs:    &obs.Obs{States: []obs.State{{Values: obs.Valuation{"x": x}}}},
+		}
+	}
+	s := subject.NewOrPanic(litmus.NewOrPanic("foo.litmus"),
+		subject.WithRun(id.FromString("gcc"), run("2")),
+		subject.WithRun(id.FromString("clang"), run("0")),
+	)
+	// Re-invoked runs haven't been checked against the subject's reference observation, so Reproduces checks them.
+	s.Oracle = &obs.Obs{States: []obs.State{{Values: obs.Valuation{"x": "0"}}}}
+	c := corpus.Corpus{"foo": *s}
+
+	fmt.Println(c.Reproduces(compilation.Name{SubjectName: "foo", CompilerID: id.FromString("gcc")}, status.Forbidden))
+	fmt.Println(c.Reproduces(compilation.Name{SubjectName: "foo", CompilerID: id.FromString("gcc")}, status.Ok))
+	fmt.Println(c.Reproduces(compilation.Name{SubjectName: "foo", CompilerID: id.FromString("clang")}, status.Ok))
+	fmt.Println(c.Reproduces(compilation.Name{SubjectName: "foo", CompilerID: id.FromString("icc")}, status.Ok))
+	fmt.Println(c.Reproduces(compilation.Name{SubjectName: "bar", CompilerID: id.FromString("gcc")}, status.Ok))
+
+	// Output:
+	// true
+	// false
+	// true
+	// false
+	// false
+}
+
 // TestCorpus_Copy tests that Corpus.Copy performs a sufficiently deep copy of the corpus.
 func TestCorpus_Copy(t *testing.T) {
 	c := corpus.Mock()
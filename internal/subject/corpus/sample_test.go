@@ -6,12 +6,17 @@
 package corpus_test
 
 import (
+	"fmt"
 	"math/rand"
 	"reflect"
 	"testing"
 
 	"github.com/c4-project/c4t/internal/helper/testhelp"
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/subject"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/c4-project/c4t/internal/subject/corpus"
 )
@@ -114,3 +119,155 @@ func checkCorpusIsSample(t *testing.T, corpus, smp corpus.Corpus) {
 		}
 	}
 }
+
+// TestCorpus_StratifiedSample tests that stratified sampling fills each stratum's quota where it can.
+func TestCorpus_StratifiedSample(t *testing.T) {
+	t.Parallel()
+
+	// Twenty 2-thread subjects, four 3-thread subjects, and two 4-thread subjects, one of which uses an RMW.
+	c := corpus.Corpus{}
+	for i := 0; i < 26; i++ {
+		var st litmus.Statset
+		switch {
+		case i < 20:
+			st.Threads = 2
+		case i < 24:
+			st.Threads = 3
+		default:
+			st.Threads = 4
+		}
+		if i == 25 {
+			st.AtomicStatements.AddType(id.FromString("xchg"), 1)
+		}
+		c[fmt.Sprintf("s%02d", i)] = *subject.NewOrPanic(&litmus.Litmus{Path: fmt.Sprintf("s%02d.litmus", i), Stats: &st})
+	}
+	threads := func(s subject.Subject) int { return s.Source.Stats.Threads }
+
+	cases := map[string]struct {
+		strata corpus.Strata
+		want   int
+		// check checks the sample.
+		check func(t *testing.T, smp corpus.Corpus)
+	}{
+		"quota-met": {
+			strata: corpus.Strata{{MinThreads: 3, Quota: 0.5}},
+			want:   8,
+			check: func(t *testing.T, smp corpus.Corpus) {
+				n := 0
+				for _, s := range smp {
+					if 3 <= threads(s) {
+						n++
+					}
+				}
+				assert.Equal(t, 4, n, "wrong number of 3+-thread subjects")
+			},
+		},
+		"quota-short": {
+			strata: corpus.Strata{{MinThreads: 4, Quota: 0.5}},
+			want:   10,
+			check: func(t *testing.T, smp corpus.Corpus) {
+				assert.Contains(t, smp, "s24", "4-thread subject missing")
+				assert.Contains(t, smp, "s25", "4-thread subject missing")
+			},
+		},
+		"atomic-type": {
+			strata: corpus.Strata{{AtomicTypes: []string{"xchg"}, Quota: 0.25}},
+			want:   4,
+			check: func(t *testing.T, smp corpus.Corpus) {
+				assert.Contains(t, smp, "s25", "RMW subject missing")
+			},
+		},
+		"overlapping": {
+			strata: corpus.Strata{
+				// Both 4-thread subjects go to the first stratum, so the second must make up its quota with 3-thread ones.
+				{MinThreads: 4, MaxThreads: 4, Quota: 0.5},
+				{MinThreads: 3, Quota: 0.5},
+			},
+			want: 4,
+			check: func(t *testing.T, smp corpus.Corpus) {
+				n3, n4 := 0, 0
+				for _, s := range smp {
+					switch threads(s) {
+					case 3:
+						n3++
+					case 4:
+						n4++
+					}
+				}
+				assert.Equal(t, 2, n3, "wrong number of 3-thread subjects")
+				assert.Equal(t, 2, n4, "wrong number of 4-thread subjects")
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		cs := cs
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			for seed := int64(0); seed < 10; seed++ {
+				smp, err := c.StratifiedSample(rand.New(rand.NewSource(seed)), cs.want, cs.strata)
+				require.NoError(t, err, "sampling corpus")
+				require.Len(t, smp, cs.want, "wrong sample size")
+				checkCorpusIsSample(t, c, smp)
+				cs.check(t, smp)
+			}
+		})
+	}
+}
+
+// TestCorpus_StratifiedSample_badQuota tests that stratified sampling rejects out-of-range quotas.
+func TestCorpus_StratifiedSample_badQuota(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]corpus.Strata{
+		"negative":  {{Quota: -0.1}},
+		"over-one":  {{Quota: 1.5}},
+		"sum-above": {{Quota: 0.6}, {Quota: 0.6}},
+	}
+	for name, ss := range cases {
+		ss := ss
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := corpus.Mock().StratifiedSample(rand.New(rand.NewSource(1)), 1, ss)
+			testhelp.ExpectErrorIs(t, err, corpus.ErrBadQuota, "sampling corpus")
+		})
+	}
+}
+
+// TestCorpus_WeightedSample tests that weighted sampling favours heavily weighted subjects, and never picks
+// non-positively weighted subjects while positively weighted ones remain.
+func TestCorpus_WeightedSample(t *testing.T) {
+	t.Parallel()
+
+	c := corpus.Corpus{}
+	for i := 0; i < 20; i++ {
+		c[fmt.Sprintf("s%02d", i)] = *subject.NewOrPanic(litmus.NewOrPanic(fmt.Sprintf("s%02d.litmus", i)))
+	}
+	// s00 and s01 are much heavier than the rest; s19 is never preferred.
+	w := func(s subject.Subject) float64 {
+		switch s.Source.Path {
+		case "s00.litmus", "s01.litmus":
+			return 100
+		case "s19.litmus":
+			return 0
+		default:
+			return 1
+		}
+	}
+
+	heavy := 0
+	for seed := int64(0); seed < 50; seed++ {
+		smp, err := c.WeightedSample(rand.New(rand.NewSource(seed)), 10, nil, w)
+		require.NoError(t, err, "sampling with seed", seed)
+		checkCorpusIsSample(t, c, smp)
+		assert.Len(t, smp, 10, "wrong sample size")
+		assert.NotContains(t, smp, "s19", "zero-weight subject sampled")
+		for _, n := range []string{"s00", "s01"} {
+			if _, ok := smp[n]; ok {
+				heavy++
+			}
+		}
+	}
+	// Uniformly, we'd expect each heavy subject in half of the samples; weighted, it should be in almost all of them.
+	assert.Less(t, 90, heavy, "heavy subjects not favoured")
+}
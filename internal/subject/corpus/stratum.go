@@ -0,0 +1,232 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package corpus
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/c4-project/c4t/internal/id"
+	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/subject"
+)
+
+// ErrBadQuota occurs when a stratum has a quota outside the range [0, 1], or the quotas of a set of strata sum to more
+// than 1.
+var ErrBadQuota = errors.New("stratum quotas must be between 0 and 1, and sum to at most 1")
+
+// SampleConfig configures how corpora are sampled.
+type SampleConfig struct {
+	// Strata contains the strata to use for stratified sampling; if empty, sampling is uniform.
+	Strata Strata `toml:"strata,omitempty" json:"strata,omitempty"`
+
+	// RegressShare is the proportion of each cycle's corpus, between 0 and 1, that the director should draw from its
+	// regression corpus of previously saved subjects.
+	RegressShare float64 `toml:"regress_share,omitzero" json:"regress_share,omitempty"`
+
+	// WeightBySource, if true, makes the perturber favour subjects whose source subjects have historically had more
+	// flagged or failed descendants.
+	WeightBySource bool `toml:"weight_by_source,omitempty" json:"weight_by_source,omitempty"`
+}
+
+// Stratum describes a class of subjects, picked out by the statistics of their best litmus test, from which
+// stratified sampling should draw a fixed share of its sample.
+//
+// Each criterion is ignored if left at its zero value; a subject with no statistics is in no stratum.
+type Stratum struct {
+	// Name is a human-readable name for the stratum.
+	Name string `toml:"name,omitempty" json:"name,omitempty"`
+
+	// Quota is the proportion of each sample, between 0 and 1, that should come from this stratum.
+	Quota float64 `toml:"quota" json:"quota"`
+
+	// MinThreads is the minimum number of threads for subjects in this stratum.
+	MinThreads int `toml:"min_threads,omitzero" json:"min_threads,omitempty"`
+
+	// MaxThreads is the maximum number of threads for subjects in this stratum.
+	MaxThreads int `toml:"max_threads,omitzero" json:"max_threads,omitempty"`
+
+	// AtomicTypes, if non-empty, requires subjects in this stratum to contain at least one atomic expression or
+	// statement whose type has one of these IDs as a prefix.
+	//
+	// These, and MemOrders, are strings rather than IDs, as the TOML loader can't decode lists of IDs.
+	AtomicTypes []string `toml:"atomic_types,omitempty" json:"atomic_types,omitempty"`
+
+	// MemOrders, if non-empty, requires subjects in this stratum to contain at least one atomic expression or
+	// statement whose memory order has one of these IDs as a prefix.
+	MemOrders []string `toml:"mem_orders,omitempty" json:"mem_orders,omitempty"`
+}
+
+// Contains checks whether s is in this stratum.
+func (t Stratum) Contains(s subject.Subject) bool {
+	l, err := s.BestLitmus()
+	if err != nil || l.Stats == nil {
+		return false
+	}
+	return t.containsStats(*l.Stats)
+}
+
+func (t Stratum) containsStats(st litmus.Statset) bool {
+	if t.MinThreads != 0 && st.Threads < t.MinThreads {
+		return false
+	}
+	if t.MaxThreads != 0 && t.MaxThreads < st.Threads {
+		return false
+	}
+	if len(t.AtomicTypes) != 0 &&
+		!anyPrefixed(st.AtomicExpressions.Types, t.AtomicTypes) && !anyPrefixed(st.AtomicStatements.Types, t.AtomicTypes) {
+		return false
+	}
+	if len(t.MemOrders) != 0 &&
+		!anyPrefixed(st.AtomicExpressions.MemOrders, t.MemOrders) && !anyPrefixed(st.AtomicStatements.MemOrders, t.MemOrders) {
+		return false
+	}
+	return true
+}
+
+// anyPrefixed checks whether counts has a nonzero count for an ID with one of prefixes as a prefix.
+func anyPrefixed(counts map[id.ID]int, prefixes []string) bool {
+	for k, n := range counts {
+		if n == 0 {
+			continue
+		}
+		for _, p := range prefixes {
+			if k.HasPrefix(id.FromString(p)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Strata is a list of strata.
+//
+// When a subject is in more than one stratum, it counts towards whichever stratum draws it first.
+type Strata []Stratum
+
+// Check checks that each quota in ss is in range, that the quotas sum to at most 1, and that each ID is valid.
+func (ss Strata) Check() error {
+	total := 0.0
+	for i, t := range ss {
+		if t.Quota < 0 || 1 < t.Quota {
+			return fmt.Errorf("%w: stratum %d has quota %g", ErrBadQuota, i, t.Quota)
+		}
+		if err := checkIDs(append(append([]string(nil), t.AtomicTypes...), t.MemOrders...)); err != nil {
+			return fmt.Errorf("stratum %d: %w", i, err)
+		}
+		total += t.Quota
+	}
+	// Allow a little slack for rounding errors in quotas such as thirds.
+	if 1+1e-9 < total {
+		return fmt.Errorf("%w: quotas sum to %g", ErrBadQuota, total)
+	}
+	return nil
+}
+
+func checkIDs(ids []string) error {
+	for _, s := range ids {
+		if _, err := id.TryFromString(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StratifiedSample tries to select a sample of size want from this corpus, drawing the share of the sample given by
+// each stratum's quota from subjects in that stratum.
+//
+// Strata are drawn in order.  If a stratum has too few subjects to fill its quota, it contributes all of them.  The rest
+// of the sample is then drawn uniformly from the subjects not yet drawn.  If ss is empty, this is the same as Sample.
+func (c Corpus) StratifiedSample(rng *rand.Rand, want int, ss Strata) (Corpus, error) {
+	if len(ss) == 0 {
+		return c.Sample(rng, want)
+	}
+	if err := ss.Check(); err != nil {
+		return nil, err
+	}
+	if len(c) == 0 {
+		return nil, ErrNone
+	}
+	if want <= 0 || len(c) <= want {
+		return c, nil
+	}
+	// We shuffle once up front, so that each stratum takes a uniform sample of its members by taking them in order.
+	names := c.Names()
+	rng.Shuffle(len(names), func(i, j int) { names[i], names[j] = names[j], names[i] })
+	return c.sampleInOrder(names, want, ss), nil
+}
+
+// Weigher is the type of functions that assign sampling weights to subjects.
+//
+// Subjects with higher weights are more likely to be sampled; subjects with non-positive weights are sampled only when
+// there are no positively weighted subjects left.
+type Weigher func(s subject.Subject) float64
+
+// WeightedSample is like StratifiedSample, but biases both the strata and the rest of the sample towards subjects to
+// which w gives higher weights.  If w is nil, this is the same as StratifiedSample.
+func (c Corpus) WeightedSample(rng *rand.Rand, want int, ss Strata, w Weigher) (Corpus, error) {
+	if w == nil {
+		return c.StratifiedSample(rng, want, ss)
+	}
+	if err := ss.Check(); err != nil {
+		return nil, err
+	}
+	if len(c) == 0 {
+		return nil, ErrNone
+	}
+	if want <= 0 || len(c) <= want {
+		return c, nil
+	}
+	return c.sampleInOrder(c.weightedOrder(rng, w), want, ss), nil
+}
+
+// weightedOrder gets a random ordering of the names in c in which subjects with higher weight under w tend to come
+// first.
+//
+// This uses the Efraimidis-Spirakis method: each subject gets the key u^(1/w) for a uniform u, and we sort by
+// descending key.  Taking any prefix of the ordering is then a weighted sample without replacement.
+func (c Corpus) weightedOrder(rng *rand.Rand, w Weigher) []string {
+	names := c.Names()
+	keys := make(map[string]float64, len(names))
+	for _, n := range names {
+		keys[n] = math.Inf(-1)
+		if wt := w(c[n]); 0 < wt {
+			// We work with logarithms of keys to avoid underflow; 1-u is in (0, 1], so its logarithm is finite.
+			keys[n] = math.Log(1-rng.Float64()) / wt
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool { return keys[names[i]] > keys[names[j]] })
+	return names
+}
+
+// sampleInOrder takes a sample of size want from c, filling each stratum in ss and then the rest of the sample with
+// the first suitable subjects in names.
+func (c Corpus) sampleInOrder(names []string, want int, ss Strata) Corpus {
+	sample := make(Corpus, want)
+	for _, t := range ss {
+		quota := int(math.Round(t.Quota * float64(want)))
+		for _, n := range names {
+			if quota <= 0 || want <= len(sample) {
+				break
+			}
+			if _, ok := sample[n]; ok || !t.Contains(c[n]) {
+				continue
+			}
+			sample[n] = c[n]
+			quota--
+		}
+	}
+	for _, n := range names {
+		if want <= len(sample) {
+			break
+		}
+		sample[n] = c[n]
+	}
+	return sample
+}
@@ -14,12 +14,21 @@ var (
 	// ErrDuplicateCompile occurs when one tries to insert a compile result that already exists.
 	ErrDuplicateCompile = errors.New("duplicate compile result")
 
+	// ErrDuplicateConfirmation occurs when one tries to confirm a run that has already been confirmed.
+	ErrDuplicateConfirmation = errors.New("duplicate confirmation verdict")
+
+	// ErrDuplicateOracle occurs when one tries to insert a reference observation that already exists.
+	ErrDuplicateOracle = errors.New("duplicate oracle observation")
+
 	// ErrDuplicateRecipe occurs when one tries to insert a recipe that already exists.
 	ErrDuplicateRecipe = errors.New("duplicate recipe")
 
 	// ErrDuplicateRun occurs when one tries to insert a run that already exists.
 	ErrDuplicateRun = errors.New("duplicate run")
 
+	// ErrDuplicateTransform occurs when one tries to insert a transformer output that already exists.
+	ErrDuplicateTransform = errors.New("duplicate transformer output")
+
 	// ErrMissingCompile occurs on requests for compile results for a compiler that do not have them.
 	ErrMissingCompile = errors.New("no such compile result")
 
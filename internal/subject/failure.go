@@ -0,0 +1,36 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package subject
+
+import (
+	"github.com/c4-project/c4t/internal/plan/stage"
+)
+
+// Failure records that a stage failed to process a subject.
+//
+// Stages that process subjects independently (such as the fuzzer and lifter) record failures on the subject, rather
+// than failing outright, so that one bad subject doesn't cost the rest of the corpus.  Later stages skip failed
+// subjects.
+type Failure struct {
+	// Stage is the stage that failed.
+	Stage stage.Stage `toml:"stage" json:"stage"`
+
+	// Timeout is true if the stage failed because it ran out of time.
+	Timeout bool `toml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// Error is the error message produced by the failure.
+	Error string `toml:"error,omitempty" json:"error,omitempty"`
+}
+
+// NewFailure constructs a failure record for stage st failing with error err.
+// If timeout is true, the failure is a timeout.
+func NewFailure(st stage.Stage, err error, timeout bool) Failure {
+	f := Failure{Stage: st, Timeout: timeout}
+	if err != nil {
+		f.Error = err.Error()
+	}
+	return f
+}
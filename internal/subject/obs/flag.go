@@ -99,6 +99,26 @@ func (o Flag) IsInteresting() bool {
 		(o&(Sat|Unsat) == 0) // Flags that are neither sat nor unsat are interesting; they suggest something weird happened.
 }
 
+// Merge combines this flag with the flag o2 of another observation of the same test.
+//
+// Undefined and partial flags carry over from either side.  For existential observations, the result is satisfied if
+// either side is; for universal observations, it is unsatisfied if either side is.
+func (o Flag) Merge(o2 Flag) Flag {
+	m := (o | o2) & (Undef | Exist | Partial)
+	sat, unsat := Sat, Unsat
+	if m.IsExistential() {
+		sat, unsat = Unsat, Sat
+	}
+	// From here, sat and unsat are flipped for existentials: the 'dominant' flag is the one in unsat.
+	switch {
+	case o.Has(unsat) || o2.Has(unsat):
+		m |= unsat
+	case o.Has(sat) && o2.Has(sat):
+		m |= sat
+	}
+	return m
+}
+
 // IsSat gets whether a flag represents a satisfying observation.
 func (o Flag) IsSat() bool {
 	return o.Has(Sat)
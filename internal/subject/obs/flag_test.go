@@ -158,3 +158,19 @@ func ExampleFlag_IsPartial() {
 	// partial: true
 	// e-partial: true
 }
+
+// ExampleFlag_Merge is a testable example for Flag.Merge.
+func ExampleFlag_Merge() {
+	fmt.Println("sat+sat:", obs.Sat.Merge(obs.Sat).Strings())
+	fmt.Println("sat+unsat:", obs.Sat.Merge(obs.Unsat).Strings())
+	fmt.Println("e-sat+e-unsat:", (obs.Sat | obs.Exist).Merge(obs.Unsat|obs.Exist).Strings())
+	fmt.Println("e-unsat+e-unsat:", (obs.Unsat | obs.Exist).Merge(obs.Unsat|obs.Exist).Strings())
+	fmt.Println("sat+undef-partial:", obs.Sat.Merge(obs.Sat|obs.Undef|obs.Partial).Strings())
+
+	// Output:
+	// sat+sat: [sat]
+	// sat+unsat: [unsat]
+	// e-sat+e-unsat: [exist sat]
+	// e-unsat+e-unsat: [exist unsat]
+	// sat+undef-partial: [partial sat undef]
+}
@@ -6,7 +6,19 @@
 // Package obs concerns 'observations': the end result of running a test on a particular machine.
 package obs
 
-import "github.com/c4-project/c4t/internal/subject/status"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/c4-project/c4t/internal/subject/status"
+)
+
+// ErrNoSharedVars occurs when checking an observation against a reference observation with which one of its states
+// shares no variables.
+//
+// This usually means that the reference model names its variables differently from the test runner.
+var ErrNoSharedVars = errors.New("state shares no variables with reference observation")
 
 // Obs represents an observation in C4's JSON-based format.
 type Obs struct {
@@ -48,6 +60,66 @@ func (o Obs) WithTag(t Tag) []State {
 	return xs
 }
 
+// Allows tests whether o, taken as the full set of states allowed by a reference model, allows the state s.
+//
+// A state is allowed if at least one state in o agrees with it on every variable that both bind.
+func (o Obs) Allows(s State) bool {
+	for _, a := range o.States {
+		if a.Values.Agrees(s.Values) {
+			return true
+		}
+	}
+	return false
+}
+
+// Forbidden gets the list of states in this observation that the reference observation ref doesn't allow.
+//
+// It fails with ErrNoSharedVars if any state in this observation shares no variables with any state in ref, as we
+// can't then tell whether ref allows it.
+func (o Obs) Forbidden(ref Obs) ([]State, error) {
+	var xs []State
+	for _, s := range o.States {
+		if !ref.overlaps(s) {
+			return nil, fmt.Errorf("%w: %s", ErrNoSharedVars, strings.Join(s.Values.Vars(), ", "))
+		}
+		if !ref.Allows(s) {
+			xs = append(xs, s)
+		}
+	}
+	return xs, nil
+}
+
+// overlaps tests whether s shares at least one variable with any state in o.
+func (o Obs) overlaps(s State) bool {
+	for _, a := range o.States {
+		if a.Values.Overlaps(s.Values) {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge merges the observation o2 into this observation, as if both were repeats of the same test.
+//
+// Flags are combined using Flag.Merge.  States with identical valuations have their occurrence counts summed; other
+// states are appended in order of first appearance.
+func (o *Obs) Merge(o2 Obs) {
+	o.Flags = o.Flags.Merge(o2.Flags)
+	for _, s := range o2.States {
+		o.mergeState(s)
+	}
+}
+
+func (o *Obs) mergeState(s State) {
+	for i, s2 := range o.States {
+		if s2.Values.Equal(s.Values) {
+			o.States[i].Occurrences += s.Occurrences
+			return
+		}
+	}
+	o.States = append(o.States, s)
+}
+
 // State represents a single state in C4's JSON-based format.
 type State struct {
 	// Tag is the kind of state this is.
@@ -6,6 +6,7 @@
 package obs_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -62,6 +63,59 @@ func ExampleObs_Status() {
 	// e-unsat: Ok
 }
 
+// ExampleObs_Forbidden is a testable example for Obs.Forbidden.
+func ExampleObs_Forbidden() {
+	ref := obs.Obs{States: []obs.State{
+		{Values: obs.Valuation{"0:r0": "0", "x": "1"}},
+		{Values: obs.Valuation{"0:r0": "1", "x": "1"}},
+	}}
+	run := obs.Obs{States: []obs.State{
+		{Occurrences: 10, Values: obs.Valuation{"0:r0": "0", "x": "1"}},
+		{Occurrences: 2, Values: obs.Valuation{"0:r0": "1", "x": "2"}},
+	}}
+	xs, err := run.Forbidden(ref)
+	if err != nil {
+		fmt.Println("error:", err)
+	}
+	for _, s := range xs {
+		fmt.Println(s.Occurrences, s.Values["0:r0"], s.Values["x"])
+	}
+
+	// If the reference model names its variables differently, we can't compare against it.
+	run2 := obs.Obs{States: []obs.State{
+		{Occurrences: 1, Values: obs.Valuation{"P0:r0": "0", "y": "1"}},
+	}}
+	_, err = run2.Forbidden(ref)
+	fmt.Println(errors.Is(err, obs.ErrNoSharedVars))
+
+	// Output:
+	// 2 1 2
+	// true
+}
+
+// ExampleObs_Merge is a testable example for Obs.Merge.
+func ExampleObs_Merge() {
+	o := obs.Obs{Flags: obs.Sat, States: []obs.State{
+		{Occurrences: 10, Values: obs.Valuation{"x": "0"}},
+		{Occurrences: 5, Values: obs.Valuation{"x": "1"}},
+	}}
+	o.Merge(obs.Obs{Flags: obs.Unsat, States: []obs.State{
+		{Occurrences: 1, Values: obs.Valuation{"x": "2"}},
+		{Occurrences: 14, Values: obs.Valuation{"x": "0"}},
+	}})
+
+	fmt.Println(o.Flags.Strings())
+	for _, s := range o.States {
+		fmt.Println(s.Occurrences, s.Values["x"])
+	}
+
+	// Output:
+	// [unsat]
+	// 24 0
+	// 5 1
+	// 1 2
+}
+
 // TestObs_jsonRoundTrip tests that Obs can go round a JSON round-trip.
 func TestObs_jsonRoundTrip(t *testing.T) {
 	t.Parallel()
@@ -21,3 +21,36 @@ func (v Valuation) Vars() []string {
 	sort.Strings(xs)
 	return xs
 }
+
+// Equal tests whether v and v2 bind exactly the same variables to the same values.
+func (v Valuation) Equal(v2 Valuation) bool {
+	return len(v) == len(v2) && v.Agrees(v2)
+}
+
+// Overlaps tests whether v and v2 bind at least one variable in common.
+func (v Valuation) Overlaps(v2 Valuation) bool {
+	for x := range v {
+		if _, ok := v2[x]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Agrees tests whether v and v2 bind at least one variable in common, and bind the same values to every variable
+// they both bind.
+//
+// Variables bound by only one of the two valuations are otherwise ignored; this lets us compare observations from
+// backends that don't report the same set of variables.  Valuations with no variables in common don't agree, as
+// there is nothing on which to compare them.
+func (v Valuation) Agrees(v2 Valuation) bool {
+	if !v.Overlaps(v2) {
+		return false
+	}
+	for x, val := range v {
+		if val2, ok := v2[x]; ok && val != val2 {
+			return false
+		}
+	}
+	return true
+}
@@ -28,3 +28,20 @@ func ExampleValuation_Vars() {
 	// x
 	// y
 }
+
+// ExampleValuation_Agrees is a runnable example for Valuation.Agrees.
+func ExampleValuation_Agrees() {
+	v := obs.Valuation{"x": "1", "y": "2"}
+	fmt.Println(v.Agrees(obs.Valuation{"x": "1", "y": "2"}))
+	fmt.Println(v.Agrees(obs.Valuation{"x": "1"}))
+	fmt.Println(v.Agrees(obs.Valuation{"x": "1", "z": "3"}))
+	fmt.Println(v.Agrees(obs.Valuation{"x": "1", "y": "3"}))
+	fmt.Println(v.Agrees(obs.Valuation{"z": "3"}))
+
+	// Output:
+	// true
+	// true
+	// true
+	// false
+	// false
+}
@@ -21,13 +21,15 @@ const (
 	FlagRunFail
 	// FlagRunTimeout signifies a runtime timeout.
 	FlagRunTimeout
+	// FlagForbidden signifies that a subject exhibited a state forbidden by the reference model.
+	FlagForbidden
 
 	// FlagFail is the union of all failure flags.
 	FlagFail = FlagCompileFail | FlagRunFail
 	// FlagTimeout is the union of all timeout flags.
 	FlagTimeout = FlagCompileTimeout | FlagRunTimeout
 	// FlagBad is the union of all 'bad' flags; it should match the calculation in Status.IsBad.
-	FlagBad = FlagFail | FlagTimeout | FlagFlagged
+	FlagBad = FlagFail | FlagTimeout | FlagFlagged | FlagForbidden
 
 	// TODO(@MattWindsor91): stop classing timeouts as bad across the board?
 )
@@ -74,6 +76,7 @@ var statusFlags = [Last + 1]Flag{
 	CompileFail:    FlagCompileFail,
 	RunTimeout:     FlagRunTimeout,
 	RunFail:        FlagRunFail,
+	Forbidden:      FlagForbidden,
 }
 
 // Flag gets the flag equivalent of this status.
@@ -34,11 +34,15 @@ const (
 	RunFail
 	// RunTimeout indicates that a run timed out.
 	RunTimeout
+	// Forbidden indicates that a run completed successfully, but its observation contained a state that the
+	// reference model forbids.
+	// Usually this means a miscompilation occurred.
+	Forbidden
 
 	// FirstBad refers to the first status that represents an unwanted outcome.
 	FirstBad = Flagged
 	// Last is the last valid status.
-	Last = RunTimeout
+	Last = Forbidden
 )
 
 //go:generate stringer -type=Status
@@ -16,11 +16,12 @@ func _() {
 	_ = x[CompileTimeout-5]
 	_ = x[RunFail-6]
 	_ = x[RunTimeout-7]
+	_ = x[Forbidden-8]
 }
 
-const _Status_name = "UnknownOkFilteredFlaggedCompileFailCompileTimeoutRunFailRunTimeout"
+const _Status_name = "UnknownOkFilteredFlaggedCompileFailCompileTimeoutRunFailRunTimeoutForbidden"
 
-var _Status_index = [...]uint8{0, 7, 9, 17, 24, 35, 49, 56, 66}
+var _Status_index = [...]uint8{0, 7, 9, 17, 24, 35, 49, 56, 66, 75}
 
 func (i Status) String() string {
 	if i < 0 || i >= Status(len(_Status_index)-1) {
@@ -13,6 +13,8 @@ import (
 	"fmt"
 
 	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/confirm"
+	"github.com/c4-project/c4t/internal/subject/obs"
 
 	"github.com/c4-project/c4t/internal/model/litmus"
 
@@ -26,6 +28,9 @@ type Subject struct {
 	// Fuzz is the fuzzer output for this subject, if it has been fuzzed.
 	Fuzz *Fuzz `toml:"fuzz,omitempty" json:"fuzz,omitempty"`
 
+	// Transform is the external transformer output for this subject, if it has been transformed.
+	Transform *Transform `toml:"transform,omitempty" json:"transform,omitempty"`
+
 	// Source refers to the original litmus test for this subject.
 	Source litmus.Litmus `toml:"source,omitempty" json:"source,omitempty"`
 
@@ -36,15 +41,25 @@ type Subject struct {
 	// Recipes contains information about this subject's lifted test recipes.
 	// If nil, this subject hasn't had any recipes generated.
 	Recipes recipe.Map `toml:"recipes,omitempty" json:"recipes,omitempty"`
+
+	// Oracle contains the set of states that the reference model allows for this subject's best litmus test.
+	// If nil, this subject hasn't been checked against a reference model.
+	Oracle *obs.Obs `toml:"oracle,omitempty" json:"oracle,omitempty"`
+
+	// Failure, if present, records that a stage failed to process this subject.
+	Failure *Failure `toml:"failure,omitempty" json:"failure,omitempty"`
 }
 
 // BestLitmus tries to get the 'best' litmus test for further development.
 //
-// When there is a fuzzing record for this subject, the fuzz output is the best path.
+// When there is a transformer record for this subject, the transformer output is the best path.
+// Otherwise, when there is a fuzzing record for this subject, the fuzz output is the best path.
 // Otherwise, if there is a non-empty Litmus file for this subject, that file is the best path.
 // Else, BestLitmus returns an error.
 func (s *Subject) BestLitmus() (*litmus.Litmus, error) {
 	switch {
+	case s.HasTransformFile():
+		return &s.Transform.Litmus, nil
 	case s.HasFuzzFile():
 		return &s.Fuzz.Litmus, nil
 	case s.Source.HasPath():
@@ -59,6 +74,16 @@ func (s *Subject) HasFuzzFile() bool {
 	return s.Fuzz != nil && s.Fuzz.Litmus.HasPath()
 }
 
+// HasFailed gets whether a stage has failed to process this subject.
+func (s *Subject) HasFailed() bool {
+	return s.Failure != nil
+}
+
+// HasTransformFile gets whether this subject has a transformed testcase file.
+func (s *Subject) HasTransformFile() bool {
+	return s.Transform != nil && s.Transform.Litmus.HasPath()
+}
+
 // Compilation gets the compilation information for the compiler ID cid.
 func (s *Subject) Compilation(cid id.ID) (compilation.Compilation, error) {
 	// TODO(@MattWindsor91): obsolete?
@@ -117,6 +142,78 @@ func (s *Subject) AddRun(cid id.ID, r compilation.RunResult) error {
 	})
 }
 
+// AddTransform sets the transformer output for this subject to t.
+// It fails if there already _is_ a transformer output.
+func (s *Subject) AddTransform(t Transform) error {
+	if s.Transform != nil {
+		return ErrDuplicateTransform
+	}
+	s.Transform = &t
+	return nil
+}
+
+// AddFailure records that a stage failed to process this subject with failure f.
+// If there is already a failure, the subject keeps it, as later failures are usually knock-on effects of the first.
+func (s *Subject) AddFailure(f Failure) {
+	if s.Failure == nil {
+		s.Failure = &f
+	}
+}
+
+// AddOracle sets the reference observation for this subject to o, and checks every existing run against it.
+// It fails if there already _is_ a reference observation, or if o can't be compared against one of the runs; in
+// either case, the subject is left unchanged.
+func (s *Subject) AddOracle(o obs.Obs) error {
+	if s.Oracle != nil {
+		return ErrDuplicateOracle
+	}
+
+	// Copying so as not to alter any other subject sharing these compilations.
+	cs := make(compilation.Map, len(s.Compilations))
+	for cid, cc := range s.Compilations {
+		if cc.Run != nil {
+			r := *cc.Run
+			if err := r.CheckAgainst(o); err != nil {
+				return fmt.Errorf("checking run of %s against oracle: %w", cid, err)
+			}
+			cc.Run = &r
+		}
+		cs[cid] = cc
+	}
+	if s.Compilations != nil {
+		s.Compilations = cs
+	}
+	s.Oracle = &o
+	return nil
+}
+
+// AddConfirmation records the confirmation verdict v against the run for compiler ID cid.
+// It fails if there is no such run, or if the run has already been confirmed.
+func (s *Subject) AddConfirmation(cid id.ID, v confirm.Verdict) error {
+	cc, err := s.Compilation(cid)
+	if err != nil {
+		return err
+	}
+	if cc.Run == nil {
+		return fmt.Errorf("%w: compiler=%q", ErrMissingRun, cid)
+	}
+	if cc.Run.Confirmation != confirm.Unchecked {
+		return fmt.Errorf("%w: compiler=%q", ErrDuplicateConfirmation, cid)
+	}
+	r := *cc.Run
+	r.Confirmation = v
+	cc.Run = &r
+
+	// Copying so as not to alter any other subject sharing these compilations.
+	cs := make(compilation.Map, len(s.Compilations))
+	for k, c := range s.Compilations {
+		cs[k] = c
+	}
+	cs[cid] = cc
+	s.Compilations = cs
+	return nil
+}
+
 func (s *Subject) mapCompilation(cid id.ID, f func(cc *compilation.Compilation) error) error {
 	s.ensureCompilationMap()
 	// Deliberately taking the zero value if the compilation hasn't been seen yet.
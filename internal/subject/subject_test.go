@@ -11,8 +11,11 @@ import (
 	"testing"
 
 	"github.com/c4-project/c4t/internal/subject/compilation"
+	"github.com/c4-project/c4t/internal/subject/confirm"
+	"github.com/c4-project/c4t/internal/subject/obs"
 
 	"github.com/c4-project/c4t/internal/model/litmus"
+	"github.com/c4-project/c4t/internal/plan/stage"
 
 	"github.com/stretchr/testify/assert"
 
@@ -247,6 +250,117 @@ func TestSubject_AddRun(t *testing.T) {
 	})
 }
 
+// TestSubject_AddOracle checks that AddOracle reclassifies runs properly.
+func TestSubject_AddOracle(t *testing.T) {
+	t.Parallel()
+
+	ref := obs.Obs{States: []obs.State{{Values: obs.Valuation{"x": "0"}}, {Values: obs.Valuation{"x": "1"}}}}
+
+	mkRun := func(s status.Status, vals ...string) *compilation.RunResult {
+		o := obs.Obs{}
+		for _, v := range vals {
+			o.States = append(o.States, obs.State{Values: obs.Valuation{"x": v}})
+		}
+		return &compilation.RunResult{Result: compilation.Result{Status: s}, Obs: &o}
+	}
+
+	s := subject.Subject{Compilations: compilation.Map{
+		id.FromString("ok"):        {Run: mkRun(status.Ok, "0", "1")},
+		id.FromString("flagged"):   {Run: mkRun(status.Flagged, "1")},
+		id.FromString("forbidden"): {Run: mkRun(status.Ok, "0", "2")},
+		id.FromString("timeout"):   {Run: mkRun(status.RunTimeout, "2")},
+		id.FromString("none"):      {},
+	}}
+	orig := s.Compilations[id.FromString("forbidden")].Run
+
+	if !assert.NoError(t, s.AddOracle(ref), "err when adding oracle to subject") {
+		return
+	}
+	assert.Equal(t, &ref, s.Oracle, "oracle not stored")
+	assert.Equal(t, status.Ok, orig.Status, "original run should not be modified")
+
+	for cid, want := range map[string]status.Status{
+		"ok":        status.Ok,
+		"flagged":   status.Flagged,
+		"forbidden": status.Forbidden,
+		"timeout":   status.RunTimeout,
+	} {
+		got, err := s.RunResult(id.FromString(cid))
+		if assert.NoError(t, err, "err when getting run", cid) {
+			assert.Equal(t, want, got.Status, "wrong status for", cid)
+		}
+	}
+
+	err := s.AddOracle(ref)
+	testhelp.ExpectErrorIs(t, err, subject.ErrDuplicateOracle, "adding oracle twice")
+
+	// A reference observation that names its variables differently can't be checked against the runs.
+	s2 := subject.Subject{Compilations: compilation.Map{id.FromString("ok"): {Run: mkRun(status.Ok, "0")}}}
+	err = s2.AddOracle(obs.Obs{States: []obs.State{{Values: obs.Valuation{"y": "0"}}}})
+	testhelp.ExpectErrorIs(t, err, obs.ErrNoSharedVars, "adding incomparable oracle")
+	assert.Nil(t, s2.Oracle, "incomparable oracle stored")
+}
+
+// TestSubject_AddTransform tests AddTransform, including adding a transform twice.
+func TestSubject_AddTransform(t *testing.T) {
+	t.Parallel()
+
+	s := *subject.NewOrPanic(litmus.NewOrPanic("foo.litmus"))
+	tr := subject.Transform{Litmus: *litmus.NewOrPanic("foo.t.litmus")}
+
+	if !assert.NoError(t, s.AddTransform(tr), "err when adding transform to subject") {
+		return
+	}
+	assert.Equal(t, &tr, s.Transform, "transform not stored")
+
+	err := s.AddTransform(tr)
+	testhelp.ExpectErrorIs(t, err, subject.ErrDuplicateTransform, "adding transform twice")
+}
+
+// TestSubject_AddFailure tests that AddFailure keeps the first failure added to a subject.
+func TestSubject_AddFailure(t *testing.T) {
+	t.Parallel()
+
+	s := *subject.NewOrPanic(litmus.NewOrPanic("foo.litmus"))
+	assert.False(t, s.HasFailed(), "fresh subject shouldn't have failed")
+
+	f1 := subject.NewFailure(stage.Fuzz, errors.New("fuzzer crashed"), false)
+	s.AddFailure(f1)
+	assert.True(t, s.HasFailed(), "subject should have failed")
+
+	s.AddFailure(subject.NewFailure(stage.Lift, errors.New("lifter crashed"), true))
+	assert.Equal(t, &f1, s.Failure, "first failure should stick")
+}
+
+// TestSubject_AddConfirmation tests AddConfirmation on a subject with several runs.
+func TestSubject_AddConfirmation(t *testing.T) {
+	t.Parallel()
+
+	flagged := &compilation.RunResult{Result: compilation.Result{Status: status.Flagged}}
+	s := subject.Subject{Compilations: compilation.Map{
+		id.FromString("flagged"): {Run: flagged},
+		id.FromString("none"):    {},
+	}}
+
+	err := s.AddConfirmation(id.FromString("flagged"), confirm.Intermittent)
+	if !assert.NoError(t, err, "err when confirming run") {
+		return
+	}
+	assert.Equal(t, confirm.Unchecked, flagged.Confirmation, "original run should not be modified")
+	got, err := s.RunResult(id.FromString("flagged"))
+	if assert.NoError(t, err, "err when getting run") {
+		assert.Equal(t, confirm.Intermittent, got.Confirmation, "verdict not stored")
+		assert.Equal(t, status.Flagged, got.Status, "status should not change")
+	}
+
+	err = s.AddConfirmation(id.FromString("flagged"), confirm.Reproducible)
+	testhelp.ExpectErrorIs(t, err, subject.ErrDuplicateConfirmation, "confirming run twice")
+	err = s.AddConfirmation(id.FromString("none"), confirm.Reproducible)
+	testhelp.ExpectErrorIs(t, err, subject.ErrMissingRun, "confirming missing run")
+	err = s.AddConfirmation(id.FromString("nonsuch"), confirm.Reproducible)
+	testhelp.ExpectErrorIs(t, err, subject.ErrMissingCompilation, "confirming missing compilation")
+}
+
 // TestSubject_BestLitmus tests a few cases of BestLitmus.
 // It should be more comprehensive than the examples.
 func TestSubject_BestLitmus(t *testing.T) {
@@ -263,6 +377,16 @@ func TestSubject_BestLitmus(t *testing.T) {
 		"litmus-only":      {s: *subject.NewOrPanic(litmus.NewOrPanic("foo")), err: nil, want: "foo"},
 		"litmus-only-fuzz": {s: *subject.NewOrPanic(litmus.NewOrPanic("foo"), subject.WithFuzz(&subject.Fuzz{})), err: nil, want: "foo"},
 		"fuzz":             {s: *subject.NewOrPanic(litmus.NewOrPanic("foo"), subject.WithFuzz(&subject.Fuzz{Litmus: *litmus.NewOrPanic("bar")})), err: nil, want: "bar"},
+		"zero-transform":   {s: subject.Subject{Source: *litmus.NewOrPanic("foo"), Transform: &subject.Transform{}}, err: nil, want: "foo"},
+		"transform": {
+			s: subject.Subject{
+				Source:    *litmus.NewOrPanic("foo"),
+				Fuzz:      &subject.Fuzz{Litmus: *litmus.NewOrPanic("bar")},
+				Transform: &subject.Transform{Litmus: *litmus.NewOrPanic("baz")},
+			},
+			err:  nil,
+			want: "baz",
+		},
 	}
 	for name, c := range cases {
 		c := c
@@ -0,0 +1,21 @@
+// Copyright (c) 2020-2021 C4 Project
+//
+// This file is part of c4t.
+// Licenced under the MIT licence; see `LICENSE`.
+
+package subject
+
+import (
+	"time"
+
+	"github.com/c4-project/c4t/internal/model/litmus"
+)
+
+// Transform is the set of file paths, and other metadata, associated with the output of an external transformer.
+type Transform struct {
+	// Duration is the length of time it took to transform this file.
+	Duration time.Duration `toml:"duration,omitzero" json:"duration,omitempty"`
+
+	// Litmus holds information about this subject's transformed Litmus file.
+	Litmus litmus.Litmus `toml:"litmus,omitempty" json:"litmus,omitempty"`
+}
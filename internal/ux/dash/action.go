@@ -12,6 +12,7 @@ import (
 
 	"github.com/c4-project/c4t/internal/helper/errhelp"
 
+	"github.com/c4-project/c4t/internal/subject"
 	"github.com/c4-project/c4t/internal/subject/compilation"
 
 	"github.com/c4-project/c4t/internal/subject/status"
@@ -65,17 +66,32 @@ func (o *actionObserver) OnBuildStart(m builder.Manifest) {
 // OnBuildRequest acknowledges a action-builder request.
 func (o *actionObserver) OnBuildRequest(r builder.Request) {
 	switch {
+	case r.Add != nil && r.Add.Failure != nil:
+		o.onFailure(r.Name, *r.Add.Failure)
 	case r.Add != nil:
 		o.onAdd(r.Name)
 	case r.Compile != nil:
 		o.onCompile(r.Name, r.Compile)
+	case r.Confirm != nil:
+		o.onConfirm(r.Name, r.Confirm)
+	case r.Failure != nil:
+		o.onFailure(r.Name, subject.Failure(*r.Failure))
+	case r.Oracle != nil:
+		o.onOracle(r.Name)
 	case r.Recipe != nil:
 		o.onRecipe(r.Name, r.Recipe)
 	case r.Run != nil:
 		o.onRun(r.Name, r.Run)
+	case r.Transform != nil:
+		o.onTransform(r.Name, r.Transform)
 	}
 }
 
+// OnBuildDuplicate acknowledges the dropping of subject sname as a duplicate of subject of.
+func (o *actionObserver) OnBuildDuplicate(sname, of string) {
+	o.logAndStepGauge("DUP", sname+" = "+of, colourDup)
+}
+
 // onAdd acknowledges the addition of a subject to a action being built.
 func (o *actionObserver) onAdd(sname string) {
 	o.logAndStepGauge("ADD", sname, colourAdd)
@@ -97,6 +113,31 @@ func (o *actionObserver) onMachOp(sname, opname string, cid id.ID, r compilation
 	o.logAndStepGauge(opname, desc, statusColours[r.Status])
 }
 
+// onConfirm acknowledges the confirmation of a run.
+func (o *actionObserver) onConfirm(sname string, b *builder.Confirm) {
+	desc := fmt.Sprintf("%s [%s]", idQualSubjectDesc(sname, b.CompilerID), b.Verdict)
+	o.logAndStepGauge("CONFIRM", desc, colourConfirm)
+}
+
+// onFailure acknowledges a stage failing to process a subject.
+func (o *actionObserver) onFailure(sname string, f subject.Failure) {
+	desc := fmt.Sprintf("%s [%s]", sname, f.Stage)
+	if f.Timeout {
+		desc += " (timeout)"
+	}
+	o.logAndStepGauge("FAIL", desc, colourFail)
+}
+
+// onOracle acknowledges the checking of a subject against a reference model.
+func (o *actionObserver) onOracle(sname string) {
+	o.logAndStepGauge("ORACLE", sname, colourOracle)
+}
+
+// onTransform acknowledges the transformation of a subject by an external transformer.
+func (o *actionObserver) onTransform(sname string, b *builder.Transform) {
+	o.logAndStepGauge("TRANSFORM", fmt.Sprintf("%s %s", sname, b.Duration), colourTransform)
+}
+
 // onRecipe acknowledges the addition of a recipe to a action being built.
 func (o *actionObserver) onRecipe(sname string, b *builder.Recipe) {
 	o.logAndStepGauge("LIFT", idQualSubjectDesc(sname, b.Arch), colourLift)
@@ -183,6 +224,18 @@ func (o *actionObserver) onInstance(m director.InstanceMessage) {
 		err = o.log.Write("-- INSTANCE CLOSED --\n")
 	case director.KindInstanceMutant:
 		err = o.log.Write(fmt.Sprintf("-- INSTANCE MUTANT NOW %s --\n", m.Mutant))
+	case director.KindInstanceRescan:
+		err = o.log.Write(fmt.Sprintf("-- RESCAN ADDED %d, REJECTED %d --\n", len(m.Added), len(m.Rejected)))
+	case director.KindInstanceBackoff:
+		err = o.log.Write(fmt.Sprintf("-- BACKING OFF %s AFTER %d ERROR(S) --\n", m.Delay, m.Errors))
+	case director.KindInstanceParked:
+		err = o.log.Write(fmt.Sprintf("-- PARKED AFTER %d ERROR(S) --\n", m.Errors))
+	case director.KindInstanceProbeFailed:
+		err = o.log.Write(fmt.Sprintf("-- HEALTH PROBE FAILED: %s --\n", m.Err))
+	case director.KindInstanceResumed:
+		err = o.log.Write("-- INSTANCE RESUMED --\n")
+	case director.KindInstanceStopping:
+		err = o.log.Write(fmt.Sprintf("-- STOPPING: %s --\n", m.Stop))
 	}
 	o.logError(err)
 }
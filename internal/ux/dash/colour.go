@@ -19,9 +19,14 @@ const (
 	colourOptNormal = cell.ColorMagenta
 	colourOptBreak  = cell.ColorRed
 
-	colourAdd  = cell.ColorBlue
-	colourLift = cell.ColorCyan
-	colourRun  = cell.ColorGreen
+	colourAdd       = cell.ColorBlue
+	colourDup       = cell.ColorGray
+	colourConfirm   = cell.ColorTeal
+	colourFail      = cell.ColorMaroon
+	colourLift      = cell.ColorCyan
+	colourOracle    = cell.ColorOlive
+	colourRun       = cell.ColorGreen
+	colourTransform = cell.ColorAqua
 
 	colourUnknown        = cell.ColorWhite
 	colourOk             = cell.ColorGreen
@@ -31,6 +36,7 @@ const (
 	colourCompileTimeout = cell.ColorBlue
 	colourRunFail        = cell.ColorMagenta
 	colourRunTimeout     = cell.ColorCyan
+	colourForbidden      = cell.ColorFuchsia
 )
 
 // statusColours maps each status flag to its colour.
@@ -44,6 +50,7 @@ var statusColours = [status.Last + 1]cell.Color{
 	colourCompileTimeout,
 	colourRunFail,
 	colourRunTimeout,
+	colourForbidden,
 }
 
 // optColour divines a colour to signify the optimisation level described by o.
@@ -63,6 +70,8 @@ func summaryColor(sc director.CycleAnalysis) cell.Color {
 	switch {
 	case sc.Analysis.HasFailures():
 		return colourCompileFail
+	case sc.Analysis.HasForbidden():
+		return colourForbidden
 	case sc.Analysis.HasFlagged():
 		return colourFlagged
 	default:
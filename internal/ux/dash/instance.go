@@ -195,6 +195,10 @@ func (o *Instance) OnBuild(m builder.Message) {
 			NReqs: m.Num,
 		})
 	case observing.BatchStep:
+		if m.Duplicate != "" {
+			o.action.OnBuildDuplicate(m.Request.Name, m.Duplicate)
+			break
+		}
 		o.action.OnBuildRequest(*m.Request)
 	case observing.BatchEnd:
 		o.action.OnBuildFinish()
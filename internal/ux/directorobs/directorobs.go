@@ -12,6 +12,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/c4-project/c4t/internal/stage/perturber"
 	"github.com/c4-project/c4t/internal/stat"
 
 	"github.com/c4-project/c4t/internal/director"
@@ -121,18 +122,30 @@ func statPersisterFromConfig(c *config.Config) (*stat.Persister, error) {
 	return stat.NewPersister(f)
 }
 
+// Yields gets a source of historical compiler yields backed by the statistics persister, if there is one.
+func (o *Obs) Yields() perturber.YieldSource {
+	if o.statPersister == nil {
+		return nil
+	}
+	return o.statPersister
+}
+
 func (o *Obs) Observers() []director.Observer {
 	return []director.Observer{o.fwd}
 }
 
 func (o *Obs) Run(ctx context.Context, cancel context.CancelFunc) error {
 	eg, ectx := errgroup.WithContext(ctx)
+	// The forwarder stops once every instance has closed, but the dashboard would keep running until ctx closes.
+	dctx, dcancel := context.WithCancel(ectx)
+	defer dcancel()
 	if o.dash != nil {
 		eg.Go(func() error {
-			return o.dash.Run(ectx, cancel)
+			return o.dash.Run(dctx, cancel)
 		})
 	}
 	eg.Go(func() error {
+		defer dcancel()
 		return o.fwd.Run(ectx)
 	})
 	return eg.Wait()
@@ -125,6 +125,21 @@ func (j *Logger) OnCycleInstance(c director.Cycle, m director.InstanceMessage) {
 		j.l.Printf("[instance %d has closed]\n", c.Instance)
 	case director.KindInstanceMutant:
 		j.l.Printf("[instance %d has changed mutant to %s]\n", c.Instance, m.Mutant)
+	case director.KindInstanceRescan:
+		j.l.Printf("[instance %d added %v from rescan]\n", c.Instance, m.Added)
+		for _, r := range m.Rejected {
+			j.l.Printf("[instance %d couldn't probe %s]\n", c.Instance, r)
+		}
+	case director.KindInstanceBackoff:
+		j.l.Printf("[instance %d backing off for %s after %d error(s)]\n", c.Instance, m.Delay, m.Errors)
+	case director.KindInstanceParked:
+		j.l.Printf("[instance %d parked after %d error(s)]\n", c.Instance, m.Errors)
+	case director.KindInstanceProbeFailed:
+		j.l.Printf("[instance %d failed health probe: %s]\n", c.Instance, m.Err)
+	case director.KindInstanceResumed:
+		j.l.Printf("[instance %d resumed]\n", c.Instance)
+	case director.KindInstanceStopping:
+		j.l.Printf("[instance %d stopping: %s]\n", c.Instance, m.Stop)
 	}
 }
 
@@ -15,16 +15,21 @@ import (
 	"github.com/c4-project/c4t/internal/plan/analysis"
 	"github.com/c4-project/c4t/internal/stage/analyser/saver"
 
+	"github.com/c4-project/c4t/internal/stage/bisector"
 	"github.com/c4-project/c4t/internal/stage/mach/observer"
+	"github.com/c4-project/c4t/internal/stage/reducer"
 
 	"github.com/c4-project/c4t/internal/stage/planner"
 
 	"github.com/c4-project/c4t/internal/stage/perturber"
 
+	"github.com/c4-project/c4t/internal/quantity"
+
 	"github.com/c4-project/c4t/internal/copier"
 
 	"github.com/c4-project/c4t/internal/observing"
 
+	"github.com/c4-project/c4t/internal/subject"
 	"github.com/c4-project/c4t/internal/subject/status"
 
 	"github.com/1set/gut/ystring"
@@ -39,14 +44,23 @@ type Logger log.Logger
 
 // OnBuild logs build messages.
 func (l *Logger) OnBuild(b builder.Message) {
-	if b.Kind == observing.BatchStep {
-		l.onBuildRequest(b.Request)
+	if b.Kind != observing.BatchStep {
+		return
+	}
+	if b.Duplicate != "" {
+		(*log.Logger)(l).Printf("subject %q duplicates %q; dropping it", b.Request.Name, b.Duplicate)
+		return
 	}
+	l.onBuildRequest(b.Request)
 }
 
-// OnBuildRequest logs failed compile and run results.
+// OnBuildRequest logs stage failures, and failed compile and run results.
 func (l *Logger) onBuildRequest(r *builder.Request) {
 	switch {
+	case r.Add != nil && r.Add.Failure != nil:
+		l.onFailure(r.Name, *r.Add.Failure)
+	case r.Failure != nil:
+		l.onFailure(r.Name, subject.Failure(*r.Failure))
 	case r.Compile != nil && r.Compile.Result.Status != status.Ok:
 		(*log.Logger)(l).Printf("subject %q on compiler %q: %s", r.Name, r.Compile.CompilerID.String(), r.Compile.Result.Status)
 	case r.Run != nil && r.Run.Result.Status != status.Ok:
@@ -54,6 +68,11 @@ func (l *Logger) onBuildRequest(r *builder.Request) {
 	}
 }
 
+// onFailure logs a stage failing to process a subject.
+func (l *Logger) onFailure(sname string, f subject.Failure) {
+	(*log.Logger)(l).Printf("subject %q failed at stage %s: %s", sname, f.Stage, f.Error)
+}
+
 // OnCompilerConfig logs compiler config messages.
 func (l *Logger) OnCompilerConfig(m compiler.Message) {
 	switch m.Kind {
@@ -98,12 +117,17 @@ func (l *Logger) OnPerturb(m perturber.Message) {
 	case perturber.KindStart:
 		(*log.Logger)(l).Printf("perturbing plan...\n")
 		m.Quantities.Log((*log.Logger)(l))
+		quantity.LogCompilerInstances((*log.Logger)(l), m.CompilerInstances)
 	case perturber.KindSeedChanged:
 		(*log.Logger)(l).Printf("- seed is now %d\n", m.Seed)
 	case perturber.KindRandomisingOpts:
 		(*log.Logger)(l).Printf("- randomising compiler options...\n")
 	case perturber.KindSamplingCorpus:
 		(*log.Logger)(l).Printf("- sampling corpus...\n")
+	case perturber.KindChoseCompiler:
+		c := m.Choice
+		(*log.Logger)(l).Printf("  - chose %s (%s, weight %.3g, %d/%d hits over %d cycles)\n",
+			c.Compiler, c.Reason, c.Weight, c.Yield.Hits, c.Yield.Subjects, c.Yield.Cycles)
 	}
 }
 
@@ -144,6 +168,18 @@ func (l *Logger) OnInstance(m director.InstanceMessage) {
 		(*log.Logger)(l).Println("[instance closed]")
 	case director.KindInstanceMutant:
 		(*log.Logger)(l).Println("instance selecting mutant", m.Mutant)
+	case director.KindInstanceRescan:
+		(*log.Logger)(l).Printf("rescan added %d subject(s) %v; rejected %v\n", len(m.Added), m.Added, m.Rejected)
+	case director.KindInstanceBackoff:
+		(*log.Logger)(l).Printf("backing off for %s after %d error(s)\n", m.Delay, m.Errors)
+	case director.KindInstanceParked:
+		(*log.Logger)(l).Printf("instance parked after %d error(s)\n", m.Errors)
+	case director.KindInstanceProbeFailed:
+		(*log.Logger)(l).Println("health probe failed:", m.Err)
+	case director.KindInstanceResumed:
+		(*log.Logger)(l).Println("instance resumed")
+	case director.KindInstanceStopping:
+		(*log.Logger)(l).Println("instance stopping:", m.Stop)
 	}
 }
 
@@ -176,3 +212,54 @@ func (l *Logger) onCoverageRunStep(name string, num int) {
 func (l *Logger) onCoverageRunEnd(name string) {
 	(*log.Logger)(l).Printf("finished coverage profile %s\n", name)
 }
+
+// OnReduce logs information about a reduction in progress according to m.
+func (l *Logger) OnReduce(m reducer.Message) {
+	switch m.Kind {
+	case observing.BatchStart:
+		(*log.Logger)(l).Printf("reducing %s (%s, %d trace steps)...\n", m.Name, m.Status, m.Num)
+	case observing.BatchStep:
+		l.onReduceStep(m.Num, m.NSteps, m.Reproduced)
+	case observing.BatchEnd:
+		(*log.Logger)(l).Printf("reduced to %d trace steps\n", m.NSteps)
+	}
+}
+
+func (l *Logger) onReduceStep(i, nsteps int, repro bool) {
+	verdict := "not reproduced"
+	if repro {
+		verdict = "reproduced"
+	}
+	(*log.Logger)(l).Printf("- candidate %d (%d steps): %s\n", i, nsteps, verdict)
+}
+
+// OnBisect logs information about a bisection in progress according to m.
+func (l *Logger) OnBisect(m bisector.Message) {
+	switch m.Kind {
+	case observing.BatchStart:
+		(*log.Logger)(l).Printf("bisecting %s (%s, %d passes)...\n", m.Name, m.Status, m.Num)
+	case observing.BatchStep:
+		l.onBisectStep(m.Num, m.NPasses, m.Reproduced)
+	case observing.BatchEnd:
+		l.onBisectEnd(m.Passes)
+	}
+}
+
+func (l *Logger) onBisectStep(i, npasses int, repro bool) {
+	verdict := "not reproduced"
+	if repro {
+		verdict = "reproduced"
+	}
+	(*log.Logger)(l).Printf("- candidate %d (%d passes enabled): %s\n", i, npasses, verdict)
+}
+
+func (l *Logger) onBisectEnd(passes []string) {
+	if len(passes) == 0 {
+		(*log.Logger)(l).Println("reproduces with every optimisation pass disabled")
+		return
+	}
+	(*log.Logger)(l).Printf("smallest reproducing pass set (%d):\n", len(passes))
+	for _, p := range passes {
+		(*log.Logger)(l).Printf("- %s\n", p)
+	}
+}
@@ -16,7 +16,9 @@ import (
 	"github.com/c4-project/c4t/internal/copier"
 	"github.com/c4-project/c4t/internal/stage/mach/observer"
 
+	"github.com/c4-project/c4t/internal/stage/bisector"
 	"github.com/c4-project/c4t/internal/stage/perturber"
+	"github.com/c4-project/c4t/internal/stage/reducer"
 
 	"github.com/c4-project/c4t/internal/stage/planner"
 	"github.com/c4-project/c4t/internal/subject/corpus/builder"
@@ -76,3 +78,17 @@ func Coverage(l *log.Logger, verbose bool) []coverage.Observer {
 	}
 	return []coverage.Observer{(*Logger)(l)}
 }
+
+// Reducer builds a list of observers suitable for observing test-case reduction.
+//
+// Since reduction progress is the main output of the reducer, these observers log regardless of verbosity.
+func Reducer(l *log.Logger) []reducer.Observer {
+	return []reducer.Observer{(*Logger)(l)}
+}
+
+// Bisector builds a list of observers suitable for observing optimisation pass bisection.
+//
+// Since bisection progress is the main output of the bisector, these observers log regardless of verbosity.
+func Bisector(l *log.Logger) []bisector.Observer {
+	return []bisector.Observer{(*Logger)(l)}
+}